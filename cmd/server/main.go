@@ -2,23 +2,30 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sort"
 	"syscall"
 	"time"
 
 	"overlock-mcp-server/internal/schema"
+	"overlock-mcp-server/pkg/chainquery"
 	"overlock-mcp-server/pkg/config"
 	"overlock-mcp-server/pkg/handler"
+	"overlock-mcp-server/pkg/history"
+	"overlock-mcp-server/pkg/indexer"
+	"overlock-mcp-server/pkg/network"
+	"overlock-mcp-server/pkg/uptime"
+	"overlock-mcp-server/pkg/watch"
 
 	"github.com/cosmos/cosmos-sdk/types/query"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 // validateConnection performs a simple health check on the gRPC connection
@@ -39,7 +46,7 @@ func validateConnection(ctx context.Context, queryClient overlockv1beta1.QueryCl
 	return err
 }
 
-func startHTTPServer(cfg *config.Config, queryClient overlockv1beta1.QueryClient, grpcConn *grpc.ClientConn) error {
+func startHTTPServer(cfg *config.Config, networks *network.Registry, indexers map[string]*indexer.Indexer, changes history.Log, samples uptime.Store) error {
 	impl := &mcp.Implementation{
 		Name:    "overlock-providers-server",
 		Version: "1.0.0",
@@ -48,7 +55,9 @@ func startHTTPServer(cfg *config.Config, queryClient overlockv1beta1.QueryClient
 	srv := mcp.NewServer(impl, nil)
 
 	// Create shared providers handler for both tools
-	providersHandler := handler.NewProvidersHandler(queryClient, cfg.APITimeout)
+	providersHandler := handler.NewProvidersHandlerForNetworks(networks)
+	providersHandler.AttachIndexers(indexers)
+	providersHandler.SetStaleMaxAge(cfg.StaleMaxAge)
 
 	// Register get-providers tool
 	providersTool := &mcp.Tool{
@@ -60,19 +69,221 @@ func startHTTPServer(cfg *config.Config, queryClient overlockv1beta1.QueryClient
 
 	providerTool := &mcp.Tool{
 		Name:        "show-provider",
-		Description: "Get detailed information for a specific provider by their ID",
+		Description: "Get detailed information for a specific provider by its ID, name or overlock:// URI",
 		InputSchema: schema.CreateProviderToolInputSchema(),
 	}
 	mcp.AddTool(srv, providerTool, providersHandler.HandleShow)
 
+	showProvidersTool := &mcp.Tool{
+		Name:        "show-providers",
+		Description: "Get detailed information for many providers at once by their IDs, reporting which were found, not found or failed",
+		InputSchema: schema.CreateShowProvidersToolInputSchema(),
+	}
+	mcp.AddTool(srv, showProvidersTool, providersHandler.HandleShowBatch)
+
 	environmentTool := &mcp.Tool{
 		Name:        "show-environment",
-		Description: "Get detailed information for a specific environment by its ID",
+		Description: "Get detailed information for a specific environment by its ID, name or overlock:// URI",
 		InputSchema: schema.CreateEnvironmentToolInputSchema(),
 	}
-	environmentHandler := handler.NewEnvironmentHandler(queryClient, cfg.APITimeout)
+	environmentHandler := handler.NewEnvironmentHandlerForNetworks(networks)
+	environmentHandler.AttachIndexers(indexers)
+	environmentHandler.SetStaleMaxAge(cfg.StaleMaxAge)
 	mcp.AddTool(srv, environmentTool, environmentHandler.Handle)
 
+	showEnvironmentsTool := &mcp.Tool{
+		Name:        "show-environments",
+		Description: "Get detailed information for many environments at once by their IDs, reporting which were found, not found or failed",
+		InputSchema: schema.CreateShowEnvironmentsToolInputSchema(),
+	}
+	mcp.AddTool(srv, showEnvironmentsTool, environmentHandler.HandleShowBatch)
+
+	creatorTool := &mcp.Tool{
+		Name:        "show-creator",
+		Description: "Get every provider and environment owned by an Overlock account address, with counts by availability, country and environment type. Set expand to inline the provider of each environment",
+		InputSchema: schema.CreateShowCreatorToolInputSchema(),
+	}
+	creatorHandler := handler.NewCreatorHandlerForNetworks(networks)
+	creatorHandler.AttachIndexers(indexers)
+	mcp.AddTool(srv, creatorTool, creatorHandler.Handle)
+
+	accountTool := &mcp.Tool{
+		Name:        "show-account",
+		Description: "Get an Overlock account's number, sequence and balances, with denomination metadata and display amounts. Accepts the creator address of any provider or environment",
+		InputSchema: schema.CreateShowAccountToolInputSchema(),
+	}
+	accountHandler := handler.NewAccountHandlerForNetworks(networks)
+	mcp.AddTool(srv, accountTool, accountHandler.Handle)
+
+	// Create shared transactions handler for both tools
+	transactionsHandler := handler.NewTransactionsHandlerForNetworks(networks)
+	listTransactionsTool := &mcp.Tool{
+		Name:        "list-transactions",
+		Description: "Search transactions by event, e.g. every MsgCreateProvider or MsgUpdateEnvironment, or everything sent by a creator address, with decoded messages, height, time, gas and result code",
+		InputSchema: schema.CreateListTransactionsToolInputSchema(),
+	}
+	mcp.AddTool(srv, listTransactionsTool, transactionsHandler.HandleList)
+
+	showTransactionTool := &mcp.Tool{
+		Name:        "show-transaction",
+		Description: "Get a single transaction by hash with its crossplane messages and their results decoded to JSON, plus height, time, gas, fee and result code",
+		InputSchema: schema.CreateShowTransactionToolInputSchema(),
+	}
+	mcp.AddTool(srv, showTransactionTool, transactionsHandler.HandleShow)
+
+	networkStatsTool := &mcp.Tool{
+		Name:        "network-stats",
+		Description: "Compute network-wide totals and distributions: providers by country, environment type, availability and creator, environments by provider and creator, and provider registrations over time",
+		InputSchema: schema.CreateNetworkStatsToolInputSchema(),
+	}
+	statsHandler := handler.NewStatsHandlerForNetworks(networks)
+	statsHandler.AttachIndexers(indexers)
+	mcp.AddTool(srv, networkStatsTool, statsHandler.Handle)
+
+	recommendTool := &mcp.Tool{
+		Name:        "recommend-providers",
+		Description: "Recommend providers for placing a new environment: filters by allowed countries, environment type, annotation values and current load, and returns a ranked shortlist explaining why each candidate matched or was penalized",
+		InputSchema: schema.CreateRecommendProvidersToolInputSchema(),
+	}
+	recommendHandler := handler.NewRecommendHandlerForNetworks(networks)
+	recommendHandler.AttachIndexers(indexers)
+	mcp.AddTool(srv, recommendTool, recommendHandler.Handle)
+
+	compareTool := &mcp.Tool{
+		Name:        "compare-providers",
+		Description: "Compare 2 to 10 providers side by side: metadata, decoded annotations, location, availability, registration age and hosted environment counts, with the fields that differ highlighted",
+		InputSchema: schema.CreateCompareProvidersToolInputSchema(),
+	}
+	compareHandler := handler.NewCompareHandlerForNetworks(networks)
+	compareHandler.AttachIndexers(indexers)
+	mcp.AddTool(srv, compareTool, compareHandler.Handle)
+
+	auditTool := &mcp.Tool{
+		Name:        "audit-registry",
+		Description: "Scan every provider and environment for data-quality problems (invalid annotations, private or loopback IPs, port 0, duplicate endpoints, missing names, environments referring to missing providers) and report the findings grouped by rule and severity",
+		InputSchema: schema.CreateAuditRegistryToolInputSchema(),
+	}
+	auditHandler := handler.NewAuditHandlerForNetworks(networks)
+	auditHandler.AttachIndexers(indexers)
+	mcp.AddTool(srv, auditTool, auditHandler.Handle)
+
+	diagnoseTool := &mcp.Tool{
+		Name:        "diagnose-environment",
+		Description: "Diagnose an environment: fetch it, resolve its provider, check the provider's availability, test TCP reachability of its ip:port and validate the annotations, returning a pass/warn/fail checklist with a plain-language summary",
+		InputSchema: schema.CreateDiagnoseEnvironmentToolInputSchema(),
+	}
+	diagnoseHandler := handler.NewDiagnoseHandlerForNetworks(networks)
+	diagnoseHandler.AttachIndexers(indexers)
+	if cfg.ProbePrivateAddresses {
+		diagnoseHandler.AllowPrivateProbes()
+	}
+	mcp.AddTool(srv, diagnoseTool, diagnoseHandler.Handle)
+
+	networksTool := &mcp.Tool{
+		Name:        "list-networks",
+		Description: "List the Overlock networks this server can query, with their endpoints and current status",
+		InputSchema: schema.CreateListNetworksToolInputSchema(),
+	}
+	networksHandler := handler.NewNetworksHandler(networks)
+	mcp.AddTool(srv, networksTool, networksHandler.Handle)
+
+	chainStatusTool := &mcp.Tool{
+		Name:        "chain-status",
+		Description: "Show which chain a network's node is on: chain-id, node and app versions, latest block height and time, and whether it is still catching up. Flags a chain-id that differs from the configured one",
+		InputSchema: schema.CreateChainStatusToolInputSchema(),
+	}
+	chainStatusHandler := handler.NewChainStatusHandler(networks)
+	mcp.AddTool(srv, chainStatusTool, chainStatusHandler.Handle)
+
+	syncStatusTool := &mcp.Tool{
+		Name:        "sync-status",
+		Description: "Show the local index sync status per network: last sync time, staleness, record counts and errors",
+		InputSchema: schema.CreateSyncStatusToolInputSchema(),
+	}
+	indexHandler := handler.NewIndexHandler(indexers)
+	mcp.AddTool(srv, syncStatusTool, indexHandler.HandleStatus)
+
+	// Resource subscriptions: overlock:// resources polled in the background for changes
+	watchHandler := handler.NewWatchHandlerForNetworks(networks, srv, watch.Options{
+		MinInterval:   cfg.WatchMinInterval,
+		MaxPerSession: cfg.WatchMaxSubscriptions,
+	})
+	for _, template := range watchHandler.ResourceTemplates() {
+		srv.AddResourceTemplate(template, watchHandler.ReadResource)
+	}
+
+	subscribeTool := &mcp.Tool{
+		Name:        "subscribe-resource",
+		Description: "Watch a provider, an environment or a whole collection for changes. The server polls it in the background and, on every change, sends this session a notifications/message log notification (logger overlock.resources, level notice) naming the updated overlock:// URI. Log notifications are only delivered after the client enables logging with logging/setLevel at level notice or lower. The watched resource is listed in resources/list while subscribed",
+		InputSchema: schema.CreateSubscribeResourceToolInputSchema(),
+	}
+	mcp.AddTool(srv, subscribeTool, watchHandler.HandleSubscribe)
+
+	unsubscribeTool := &mcp.Tool{
+		Name:        "unsubscribe-resource",
+		Description: "Stop watching a resource previously subscribed with subscribe-resource",
+		InputSchema: schema.CreateUnsubscribeResourceToolInputSchema(),
+	}
+	mcp.AddTool(srv, unsubscribeTool, watchHandler.HandleUnsubscribe)
+
+	listSubscriptionsTool := &mcp.Tool{
+		Name:        "list-subscriptions",
+		Description: "List this session's resource subscriptions with their polling interval and last change",
+		InputSchema: schema.CreateListSubscriptionsToolInputSchema(),
+	}
+	mcp.AddTool(srv, listSubscriptionsTool, watchHandler.HandleList)
+
+	watchCtx, stopWatching := context.WithCancel(context.Background())
+	defer stopWatching()
+	go watchHandler.Run(watchCtx)
+
+	historyHandler := handler.NewHistoryHandler(changes, networks)
+	providerHistoryTool := &mcp.Tool{
+		Name:        "provider-history",
+		Description: "Show how a provider changed over time: creation, field updates with old and new values, and removal, as detected by the local index",
+		InputSchema: schema.CreateProviderHistoryToolInputSchema(),
+	}
+	mcp.AddTool(srv, providerHistoryTool, historyHandler.HandleProviderHistory)
+
+	environmentHistoryTool := &mcp.Tool{
+		Name:        "environment-history",
+		Description: "Show how an environment changed over time: creation, field updates with old and new values, and removal, as detected by the local index",
+		InputSchema: schema.CreateEnvironmentHistoryToolInputSchema(),
+	}
+	mcp.AddTool(srv, environmentHistoryTool, historyHandler.HandleEnvironmentHistory)
+
+	recentChangesTool := &mcp.Tool{
+		Name:        "recent-changes",
+		Description: "List recently created, updated and removed providers and environments across networks, newest first",
+		InputSchema: schema.CreateRecentChangesToolInputSchema(),
+	}
+	mcp.AddTool(srv, recentChangesTool, historyHandler.HandleRecentChanges)
+
+	uptimeHandler := handler.NewUptimeHandler(samples, networks)
+	providerUptimeTool := &mcp.Tool{
+		Name:        "provider-uptime",
+		Description: "Report a provider's measured availability over a time range: uptime percentage, p50/p95 TCP connect latency and outage windows, from background probes of its endpoint",
+		InputSchema: schema.CreateProviderUptimeToolInputSchema(),
+	}
+	mcp.AddTool(srv, providerUptimeTool, uptimeHandler.Handle)
+
+	// Generic access to the node's other query services, restricted to an allowlist
+	allowlistEntries := cfg.ChainQueryAllowlist
+	if len(allowlistEntries) == 0 {
+		allowlistEntries = chainquery.DefaultAllowlist
+	}
+	allowlist, err := chainquery.ParseAllowlist(allowlistEntries)
+	if err != nil {
+		return fmt.Errorf("invalid OVERLOCK_CHAIN_QUERY_ALLOWLIST: %w", err)
+	}
+	chainQueryTool := &mcp.Tool{
+		Name:        "chain-query",
+		Description: "Call any allowlisted gRPC query method of the node by full name with JSON request fields, e.g. cosmos.bank.v1beta1.Query/Balance, for chain data no other tool covers. Message types are resolved through server reflection",
+		InputSchema: schema.CreateChainQueryToolInputSchema(),
+	}
+	chainQueryHandler := handler.NewChainQueryHandlerForNetworks(networks, allowlist)
+	mcp.AddTool(srv, chainQueryTool, chainQueryHandler.Handle)
+
 	// Create the HTTP handler for MCP
 	httpHandler := mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server {
 		return srv
@@ -84,9 +295,16 @@ func startHTTPServer(cfg *config.Config, queryClient overlockv1beta1.QueryClient
 		Handler: httpHandler,
 	}
 
-	if grpcConn != nil {
-		log.Info().Str("grpc_url", cfg.OverlockGRPCURL).Msg("Connected to Overlock blockchain")
-		log.Info().Msg("Ready to serve Overlock Network data")
+	connected := 0
+	for _, name := range networks.Names() {
+		n, _ := networks.Get(name)
+		if n.Client != nil {
+			log.Info().Str("network", name).Str("grpc_url", n.GRPCURL).Msg("Connected to Overlock blockchain")
+			connected++
+		}
+	}
+	if connected > 0 {
+		log.Info().Str("default_network", networks.DefaultName()).Msg("Ready to serve Overlock Network data")
 	} else {
 		log.Warn().Msg("Starting server without gRPC connection - some functionality may be limited")
 	}
@@ -117,13 +335,11 @@ func startHTTPServer(cfg *config.Config, queryClient overlockv1beta1.QueryClient
 		log.Info().Msg("HTTP server stopped")
 	}
 
-	// Close gRPC connection
-	if grpcConn != nil {
-		if err := grpcConn.Close(); err != nil {
-			log.Error().Err(err).Msg("Failed to close gRPC connection")
-		} else {
-			log.Info().Msg("gRPC connection closed")
-		}
+	// Close gRPC connections
+	if err := networks.Close(); err != nil {
+		log.Error().Err(err).Msg("Failed to close gRPC connection")
+	} else {
+		log.Info().Msg("gRPC connections closed")
 	}
 	return nil
 }
@@ -147,31 +363,190 @@ func main() {
 		zerolog.SetGlobalLevel(zerolog.InfoLevel)
 	}
 
-	// Create gRPC connection
+	// Create a gRPC connection for every configured network
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	grpcConn, err := grpc.NewClient(
-		cfg.OverlockGRPCURL,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
-	if err != nil {
-		log.Warn().Err(err).Str("grpc_url", cfg.OverlockGRPCURL).Msg("Failed to connect to gRPC server")
-		grpcConn = nil
+	var networkList []*network.Network
+	for _, name := range sortedNetworkNames(cfg) {
+		n, err := network.Dial(cfg.Networks[name])
+		if err != nil {
+			log.Warn().Err(err).Str("network", name).Str("grpc_url", cfg.Networks[name].GRPCURL).Msg("Failed to connect to gRPC server")
+			n = &network.Network{
+				Name:            name,
+				GRPCURL:         cfg.Networks[name].GRPCURL,
+				TLS:             cfg.Networks[name].TLS,
+				Timeout:         cfg.Networks[name].APITimeout,
+				ExpectedChainID: cfg.Networks[name].ExpectedChainID,
+			}
+		} else if err := n.VerifyChainID(ctx); err != nil {
+			// Never answer for a chain other than the configured one, nor before it is known;
+			// the network is served once a later verification succeeds
+			log.Error().Err(err).Str("network", name).Msg("Refusing to serve network")
+			n.Refuse(err)
+		} else if err := validateConnection(ctx, n.Client); err != nil {
+			// Validate connection
+			log.Warn().Err(err).Str("network", name).Msg("Failed to validate gRPC connection")
+			n.Client = nil
+		}
+		networkList = append(networkList, n)
 	}
+	networks := network.NewRegistry(cfg.DefaultNetwork, networkList...)
 
-	var queryClient overlockv1beta1.QueryClient
-	if grpcConn != nil {
-		// Validate connection
-		queryClient = overlockv1beta1.NewQueryClient(grpcConn)
-		if err := validateConnection(ctx, queryClient); err != nil {
-			log.Warn().Err(err).Msg("Failed to validate gRPC connection")
-			queryClient = nil
-		}
+	// Start background index syncs
+	indexCtx, stopIndexers := context.WithCancel(context.Background())
+	for _, n := range networkList {
+		go n.WatchChainID(indexCtx, cfg.ChainIDCheckInterval)
 	}
+	changes := openHistory(cfg)
+	indexers, closeIndexers := startIndexers(indexCtx, cfg, networks, changes)
+
+	// Start background uptime probes
+	samples := openUptime(cfg)
+	startMonitors(indexCtx, cfg, networks, indexers, samples)
 
 	// Start HTTP server
-	if err := startHTTPServer(cfg, queryClient, grpcConn); err != nil {
+	if err := startHTTPServer(cfg, networks, indexers, changes, samples); err != nil {
 		log.Fatal().Err(err).Msg("HTTP server error")
 	}
+
+	stopIndexers()
+	closeIndexers()
+	if changes != nil {
+		if err := changes.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close change history")
+		}
+	}
+	if samples != nil {
+		if err := samples.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close uptime samples")
+		}
+	}
+}
+
+// openUptime opens the store fed by the uptime probes, or returns nil when uptime tracking is disabled
+func openUptime(cfg *config.Config) uptime.Store {
+	if !cfg.UptimeEnabled {
+		return nil
+	}
+	if cfg.UptimeDir != "" {
+		samples, err := openUptimeStore(cfg.UptimeDir)
+		if err == nil {
+			return samples
+		}
+		log.Warn().Err(err).Msg("Failed to open uptime samples, keeping them in memory")
+	}
+	return uptime.NewMemoryStore(0)
+}
+
+// openUptimeStore opens the persisted probe samples in dir, creating the directory when needed
+func openUptimeStore(dir string) (uptime.Store, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create uptime directory '%s': %w", dir, err)
+	}
+	return uptime.OpenBoltStore(filepath.Join(dir, "uptime.db"))
+}
+
+// startMonitors starts background uptime probes for every network when uptime tracking is enabled.
+// Providers are read from the local index when it is enabled, otherwise from the chain.
+func startMonitors(ctx context.Context, cfg *config.Config, networks *network.Registry, indexers map[string]*indexer.Indexer, samples uptime.Store) {
+	if samples == nil {
+		return
+	}
+	for _, name := range networks.Names() {
+		n, _ := networks.Get(name)
+
+		source := uptime.ChainSource(n.Client, n.Timeout)
+		if idx, ok := indexers[name]; ok {
+			source = uptime.IndexSource(idx)
+		}
+		monitor := uptime.NewMonitor(name, source, samples, uptime.Options{
+			Interval:    cfg.UptimeInterval,
+			Timeout:     cfg.UptimeTimeout,
+			Concurrency: cfg.UptimeConcurrency,
+			Retention:   cfg.UptimeRetention,
+
+			ProbePrivate: cfg.ProbePrivateAddresses,
+		})
+		go monitor.Run(ctx)
+		log.Info().Str("network", name).Dur("interval", cfg.UptimeInterval).Msg("Started uptime probes")
+	}
+}
+
+// openHistory opens the change log fed by the index syncs, or returns nil when the index is disabled
+func openHistory(cfg *config.Config) history.Log {
+	if !cfg.IndexEnabled {
+		return nil
+	}
+	if cfg.HistoryDir != "" {
+		changes, err := openHistoryLog(cfg.HistoryDir)
+		if err == nil {
+			return changes
+		}
+		log.Warn().Err(err).Msg("Failed to open change history, keeping it in memory")
+	}
+	return history.NewMemoryLog(0)
+}
+
+// openHistoryLog opens the persisted change log in dir, creating the directory when needed
+func openHistoryLog(dir string) (history.Log, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create history directory '%s': %w", dir, err)
+	}
+	return history.OpenBoltLog(filepath.Join(dir, "history.db"))
+}
+
+// startIndexers starts a background index sync for every network when the index is enabled.
+// The returned function closes the persisted index stores once the syncs have been stopped.
+func startIndexers(ctx context.Context, cfg *config.Config, networks *network.Registry, changes history.Log) (map[string]*indexer.Indexer, func()) {
+	indexers := make(map[string]*indexer.Indexer)
+	if !cfg.IndexEnabled {
+		return indexers, func() {}
+	}
+
+	var stores []*indexer.Store
+	for _, name := range networks.Names() {
+		n, _ := networks.Get(name)
+
+		opts := indexer.Options{
+			Interval: cfg.IndexInterval,
+			Timeout:  n.Timeout,
+			PageSize: uint64(cfg.IndexPageSize),
+		}
+		if changes != nil {
+			opts.OnSync = history.Recorder(name, changes, cfg.HistoryRetention)
+		}
+		if cfg.IndexDir != "" {
+			store, err := indexer.OpenStore(filepath.Join(cfg.IndexDir, name+".db"))
+			if err != nil {
+				log.Warn().Err(err).Str("network", name).Msg("Failed to open index store, keeping index in memory")
+			} else {
+				opts.Store = store
+				stores = append(stores, store)
+			}
+		}
+
+		idx := indexer.New(name, n.Client, opts)
+		indexers[name] = idx
+		go idx.Run(ctx)
+		log.Info().Str("network", name).Dur("interval", cfg.IndexInterval).Msg("Started index sync")
+	}
+
+	return indexers, func() {
+		for _, store := range stores {
+			if err := store.Close(); err != nil {
+				log.Error().Err(err).Msg("Failed to close index store")
+			}
+		}
+	}
+}
+
+// sortedNetworkNames returns the configured network names in a stable order
+func sortedNetworkNames(cfg *config.Config) []string {
+	names := make([]string, 0, len(cfg.Networks))
+	for name := range cfg.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
@@ -0,0 +1,38 @@
+package schema
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// CreateAuditRegistryToolInputSchema creates the JSON schema for the audit-registry tool input
+func CreateAuditRegistryToolInputSchema() *jsonschema.Schema {
+	one, max := 1.0, 1000.0
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"rules": {
+				Type:        "array",
+				Description: "Rules to run (optional, defaults to every rule)",
+				Items: &jsonschema.Schema{
+					Type: "string",
+					Enum: []any{"invalid-annotations", "non-public-ip", "invalid-port", "duplicate-endpoint", "missing-name", "dangling-provider"},
+				},
+			},
+			"min_severity": {
+				Type:        "string",
+				Description: "Only run rules at least this severe (optional, defaults to 'info')",
+				Enum:        []any{"error", "warning", "info"},
+			},
+			"limit": {
+				Type:        "integer",
+				Description: "Maximum number of findings to list per rule; the count always covers every finding (optional, defaults to 50)",
+				Minimum:     &one,
+				Maximum:     &max,
+			},
+			"network": networkProperty(),
+			"height":  heightProperty(),
+			"source":  sourceProperty(),
+		},
+		AdditionalProperties: &jsonschema.Schema{},
+	}
+}
@@ -0,0 +1,28 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAuditRegistryToolInputSchema(t *testing.T) {
+	schema := CreateAuditRegistryToolInputSchema()
+
+	require.NotNil(t, schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.Len(t, schema.Properties, 6)
+
+	rules := schema.Properties["rules"]
+	require.NotNil(t, rules)
+	assert.Equal(t, "array", rules.Type)
+	assert.Contains(t, rules.Items.Enum, "dangling-provider")
+
+	assert.Equal(t, []any{"error", "warning", "info"}, schema.Properties["min_severity"].Enum)
+	assert.Equal(t, 1.0, *schema.Properties["limit"].Minimum)
+	assert.Equal(t, 1000.0, *schema.Properties["limit"].Maximum)
+
+	assert.Empty(t, schema.Required)
+	assert.NotNil(t, schema.AdditionalProperties)
+}
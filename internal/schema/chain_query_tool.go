@@ -0,0 +1,27 @@
+package schema
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// CreateChainQueryToolInputSchema creates the JSON schema for the chain-query tool input
+func CreateChainQueryToolInputSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"method": {
+				Type:        "string",
+				Description: "Full name of the gRPC query method to call, e.g. 'cosmos.bank.v1beta1.Query/Balance'. Only allowlisted services and methods can be called (required)",
+			},
+			"request": {
+				Type:        "object",
+				Description: "Request message in protobuf JSON form, e.g. {\"address\": \"overlock1...\", \"denom\": \"stake\"} (optional, defaults to an empty request)",
+			},
+			"network": networkProperty(),
+			"height":  heightProperty(),
+			"format":  formatProperty(false),
+		},
+		Required:             []string{"method"},
+		AdditionalProperties: &jsonschema.Schema{},
+	}
+}
@@ -0,0 +1,31 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateChainQueryToolInputSchema(t *testing.T) {
+	schema := CreateChainQueryToolInputSchema()
+
+	require.NotNil(t, schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.Len(t, schema.Properties, 5)
+
+	methodProp := schema.Properties["method"]
+	require.NotNil(t, methodProp)
+	assert.Equal(t, "string", methodProp.Type)
+
+	requestProp := schema.Properties["request"]
+	require.NotNil(t, requestProp)
+	assert.Equal(t, "object", requestProp.Type)
+
+	assert.Contains(t, schema.Properties, "network")
+	assert.Contains(t, schema.Properties, "height")
+	assert.Equal(t, []any{"json", "compact_json", "markdown"}, schema.Properties["format"].Enum)
+
+	assert.Equal(t, []string{"method"}, schema.Required)
+	assert.NotNil(t, schema.AdditionalProperties)
+}
@@ -0,0 +1,17 @@
+package schema
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// CreateChainStatusToolInputSchema creates the JSON schema for the chain-status tool input
+func CreateChainStatusToolInputSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"network": networkProperty(),
+			"format":  formatProperty(false),
+		},
+		AdditionalProperties: &jsonschema.Schema{},
+	}
+}
@@ -0,0 +1,22 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateChainStatusToolInputSchema(t *testing.T) {
+	schema := CreateChainStatusToolInputSchema()
+
+	require.NotNil(t, schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.Len(t, schema.Properties, 2)
+
+	assert.Contains(t, schema.Properties, "network")
+	assert.Equal(t, []any{"json", "compact_json", "markdown"}, schema.Properties["format"].Enum)
+
+	assert.Empty(t, schema.Required)
+	assert.NotNil(t, schema.AdditionalProperties)
+}
@@ -0,0 +1,115 @@
+package schema
+
+import (
+	"strconv"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// networkProperty creates the optional network argument shared by every chain-backed tool
+func networkProperty() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Name of the Overlock network to query (optional, defaults to the server's default network; see list-networks)",
+	}
+}
+
+// heightProperty creates the optional height argument shared by every chain-backed tool
+func heightProperty() *jsonschema.Schema {
+	zero := 0.0
+	return &jsonschema.Schema{
+		Type:        "integer",
+		Description: "Block height to query state at (optional, defaults to the latest block). Pass the block_height of a previous response to keep multi-page walks consistent",
+		Minimum:     &zero,
+	}
+}
+
+// sourceProperty creates the optional source argument for tools that can answer from the local index
+func sourceProperty() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Where to read the data from: 'chain' queries the node (default), 'index' answers from the server's periodically synced local index and reports its staleness",
+		Enum:        []any{"chain", "index"},
+	}
+}
+
+// nameProperty creates the name argument that selects a record by metadata.name instead of ID
+func nameProperty(kind string) *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Select the " + kind + " by metadata.name instead of id, matched exactly or else case-insensitively. When several " + kind + "s share the name, all candidates are returned",
+	}
+}
+
+// uriProperty creates the uri argument that selects a record by its overlock:// resource URI
+func uriProperty(kind string) *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Select the " + kind + " by resource URI instead of id, e.g. overlock://mainnet/" + kind + "s/7. The URI also selects the network",
+	}
+}
+
+// formatProperty creates the optional format argument; csv is only offered for list responses
+func formatProperty(list bool) *jsonschema.Schema {
+	formats := []any{"json", "compact_json", "markdown"}
+	description := "Output format: 'json' (indented, default), 'compact_json' (no whitespace, saves context) or 'markdown' (a key/value table)"
+	if list {
+		formats = append(formats, "csv")
+		description = "Output format: 'json' (indented, default), 'compact_json' (no whitespace, saves context), 'markdown' (a table) or 'csv' (one row per record, nested fields as dotted columns)"
+	}
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: description,
+		Enum:        formats,
+	}
+}
+
+// sinceProperty creates the optional since argument shared by the history tools
+func sinceProperty(defaultHint string) *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Only return changes detected at or after this time: an RFC3339 timestamp or a duration ago such as '24h' (optional, " + defaultHint + ")",
+	}
+}
+
+// untilProperty creates the optional until argument shared by the history tools
+func untilProperty() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Only return changes detected at or before this time: an RFC3339 timestamp or a duration ago such as '1h' (optional, defaults to now)",
+	}
+}
+
+// historyLimitProperty creates the optional limit argument shared by the history tools
+func historyLimitProperty(defaultLimit int) *jsonschema.Schema {
+	one, max := 1.0, 500.0
+	return &jsonschema.Schema{
+		Type:        "integer",
+		Description: "Maximum number of changes to return, newest first (optional, defaults to " + strconv.Itoa(defaultLimit) + ", max 500)",
+		Minimum:     &one,
+		Maximum:     &max,
+	}
+}
+
+// batchIDsProperty creates the required ids argument of the batch lookup tools
+func batchIDsProperty(what string) *jsonschema.Schema {
+	one := 1.0
+	return &jsonschema.Schema{
+		Type:        "array",
+		Description: what + " IDs to retrieve (required, 1 to 100; duplicates are looked up once)",
+		Items: &jsonschema.Schema{
+			Type:    "integer",
+			Minimum: &one,
+		},
+		MinItems: jsonschema.Ptr(1),
+		MaxItems: jsonschema.Ptr(100),
+	}
+}
+
+// expandProperty creates the optional expand argument of the environment tools
+func expandProperty() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "boolean",
+		Description: "Inline the referenced provider record (or say explicitly that it does not exist) and the number of the creator's other environments (optional, defaults to false)",
+	}
+}
@@ -0,0 +1,30 @@
+package schema
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// CreateCompareProvidersToolInputSchema creates the JSON schema for the compare-providers tool input
+func CreateCompareProvidersToolInputSchema() *jsonschema.Schema {
+	one := 1.0
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"ids": {
+				Type:        "array",
+				Description: "Provider IDs to compare (required, 2 to 10 distinct IDs)",
+				Items: &jsonschema.Schema{
+					Type:    "integer",
+					Minimum: &one,
+				},
+				MinItems: jsonschema.Ptr(2),
+				MaxItems: jsonschema.Ptr(10),
+			},
+			"network": networkProperty(),
+			"height":  heightProperty(),
+			"source":  sourceProperty(),
+		},
+		Required:             []string{"ids"},
+		AdditionalProperties: &jsonschema.Schema{},
+	}
+}
@@ -0,0 +1,32 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCompareProvidersToolInputSchema(t *testing.T) {
+	schema := CreateCompareProvidersToolInputSchema()
+
+	require.NotNil(t, schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.Len(t, schema.Properties, 4)
+
+	idsProp := schema.Properties["ids"]
+	require.NotNil(t, idsProp)
+	assert.Equal(t, "array", idsProp.Type)
+	require.NotNil(t, idsProp.Items)
+	assert.Equal(t, "integer", idsProp.Items.Type)
+	assert.Equal(t, 1.0, *idsProp.Items.Minimum)
+	assert.Equal(t, 2, *idsProp.MinItems)
+	assert.Equal(t, 10, *idsProp.MaxItems)
+
+	assert.Contains(t, schema.Properties, "network")
+	assert.Contains(t, schema.Properties, "height")
+	assert.Equal(t, []any{"chain", "index"}, schema.Properties["source"].Enum)
+
+	assert.Equal(t, []string{"ids"}, schema.Required)
+	assert.NotNil(t, schema.AdditionalProperties)
+}
@@ -0,0 +1,31 @@
+package schema
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// CreateDiagnoseEnvironmentToolInputSchema creates the JSON schema for the diagnose-environment tool input
+func CreateDiagnoseEnvironmentToolInputSchema() *jsonschema.Schema {
+	one, minTimeout, maxTimeout := 1.0, 100.0, 10000.0
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"id": {
+				Type:        "integer",
+				Description: "Environment ID to diagnose (required)",
+				Minimum:     &one,
+			},
+			"tcp_timeout_ms": {
+				Type:        "integer",
+				Description: "How long the TCP reachability check waits for the provider endpoint, in milliseconds (optional, defaults to 3000)",
+				Minimum:     &minTimeout,
+				Maximum:     &maxTimeout,
+			},
+			"network": networkProperty(),
+			"height":  heightProperty(),
+			"source":  sourceProperty(),
+		},
+		Required:             []string{"id"},
+		AdditionalProperties: &jsonschema.Schema{},
+	}
+}
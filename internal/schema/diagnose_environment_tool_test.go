@@ -0,0 +1,30 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDiagnoseEnvironmentToolInputSchema(t *testing.T) {
+	schema := CreateDiagnoseEnvironmentToolInputSchema()
+
+	require.NotNil(t, schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.Len(t, schema.Properties, 5)
+
+	idProp := schema.Properties["id"]
+	require.NotNil(t, idProp)
+	assert.Equal(t, "integer", idProp.Type)
+	assert.Equal(t, 1.0, *idProp.Minimum)
+
+	timeoutProp := schema.Properties["tcp_timeout_ms"]
+	require.NotNil(t, timeoutProp)
+	assert.Equal(t, 100.0, *timeoutProp.Minimum)
+	assert.Equal(t, 10000.0, *timeoutProp.Maximum)
+
+	assert.Equal(t, []any{"chain", "index"}, schema.Properties["source"].Enum)
+	assert.Equal(t, []string{"id"}, schema.Required)
+	assert.NotNil(t, schema.AdditionalProperties)
+}
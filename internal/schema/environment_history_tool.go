@@ -0,0 +1,26 @@
+package schema
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// CreateEnvironmentHistoryToolInputSchema creates the JSON schema for the environment-history tool input
+func CreateEnvironmentHistoryToolInputSchema() *jsonschema.Schema {
+	minID := 1.0
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"id": {
+				Type:        "integer",
+				Description: "ID of the environment whose change history to return",
+				Minimum:     &minID,
+			},
+			"network": networkProperty(),
+			"since":   sinceProperty("defaults to the start of the recorded history"),
+			"until":   untilProperty(),
+			"limit":   historyLimitProperty(50),
+		},
+		Required:             []string{"id"},
+		AdditionalProperties: &jsonschema.Schema{},
+	}
+}
@@ -0,0 +1,37 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateEnvironmentHistoryToolInputSchema(t *testing.T) {
+	schema := CreateEnvironmentHistoryToolInputSchema()
+
+	require.NotNil(t, schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.Len(t, schema.Properties, 5)
+
+	idProp := schema.Properties["id"]
+	require.NotNil(t, idProp)
+	assert.Equal(t, "integer", idProp.Type)
+	require.NotNil(t, idProp.Minimum)
+	assert.Equal(t, 1.0, *idProp.Minimum)
+
+	for _, name := range []string{"network", "since", "until"} {
+		prop := schema.Properties[name]
+		require.NotNil(t, prop, name)
+		assert.Equal(t, "string", prop.Type, name)
+	}
+
+	limitProp := schema.Properties["limit"]
+	require.NotNil(t, limitProp)
+	assert.Equal(t, "integer", limitProp.Type)
+	require.NotNil(t, limitProp.Maximum)
+	assert.Equal(t, 500.0, *limitProp.Maximum)
+
+	assert.Equal(t, []string{"id"}, schema.Required)
+	assert.NotNil(t, schema.AdditionalProperties)
+}
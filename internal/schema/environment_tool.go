@@ -13,11 +13,17 @@ func CreateEnvironmentToolInputSchema() *jsonschema.Schema {
 		Properties: map[string]*jsonschema.Schema{
 			"id": {
 				Type:        "integer",
-				Description: "Environment ID to retrieve detailed information for (required)",
+				Description: "Environment ID to retrieve detailed information for (one of id, name or uri is required)",
 				Minimum:     &one,
 			},
+			"name":    nameProperty("environment"),
+			"uri":     uriProperty("environment"),
+			"network": networkProperty(),
+			"height":  heightProperty(),
+			"source":  sourceProperty(),
+			"format":  formatProperty(false),
+			"expand":  expandProperty(),
 		},
-		Required:             []string{"id"},
 		AdditionalProperties: &jsonschema.Schema{},
 	}
 }
@@ -13,16 +13,47 @@ func TestCreateEnvironmentToolInputSchema(t *testing.T) {
 	require.NotNil(t, schema)
 	assert.Equal(t, "object", schema.Type)
 	assert.NotNil(t, schema.Properties)
-	assert.Len(t, schema.Properties, 1)
+	assert.Len(t, schema.Properties, 8)
 
 	idProp := schema.Properties["id"]
 	require.NotNil(t, idProp)
 	assert.Equal(t, "integer", idProp.Type)
-	assert.Equal(t, "Environment ID to retrieve detailed information for (required)", idProp.Description)
+	assert.Equal(t, "Environment ID to retrieve detailed information for (one of id, name or uri is required)", idProp.Description)
 	require.NotNil(t, idProp.Minimum)
 	assert.Equal(t, 1.0, *idProp.Minimum)
 
-	assert.Len(t, schema.Required, 1)
-	assert.Equal(t, "id", schema.Required[0])
+	nameProp := schema.Properties["name"]
+	require.NotNil(t, nameProp)
+	assert.Equal(t, "string", nameProp.Type)
+
+	uriProp := schema.Properties["uri"]
+	require.NotNil(t, uriProp)
+	assert.Equal(t, "string", uriProp.Type)
+	assert.Contains(t, uriProp.Description, "overlock://mainnet/environments/7")
+
+	networkProp := schema.Properties["network"]
+	require.NotNil(t, networkProp)
+	assert.Equal(t, "string", networkProp.Type)
+
+	heightProp := schema.Properties["height"]
+	require.NotNil(t, heightProp)
+	assert.Equal(t, "integer", heightProp.Type)
+
+	sourceProp := schema.Properties["source"]
+	require.NotNil(t, sourceProp)
+	assert.Equal(t, "string", sourceProp.Type)
+	assert.Equal(t, []any{"chain", "index"}, sourceProp.Enum)
+
+	formatProp := schema.Properties["format"]
+	require.NotNil(t, formatProp)
+	assert.Equal(t, "string", formatProp.Type)
+	assert.Equal(t, []any{"json", "compact_json", "markdown"}, formatProp.Enum)
+
+	expandProp := schema.Properties["expand"]
+	require.NotNil(t, expandProp)
+	assert.Equal(t, "boolean", expandProp.Type)
+
+	// id, name and uri are alternatives, so none is required by the schema
+	assert.Empty(t, schema.Required)
 	assert.NotNil(t, schema.AdditionalProperties)
 }
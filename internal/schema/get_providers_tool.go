@@ -28,6 +28,28 @@ func CreateProvidersToolInputSchema() *jsonschema.Schema {
 				Description: "Number of providers to skip for pagination (default: 0)",
 				Minimum:     &zero,
 			},
+			"network": networkProperty(),
+			"height":  heightProperty(),
+			"source":  sourceProperty(),
+			"format":  formatProperty(true),
+			"fields": {
+				Type:        "string",
+				Description: "Comma separated provider fields to return, e.g. 'id,metadata.name,country_code' (optional, defaults to every field)",
+			},
+			"max_tokens": {
+				Type:        "integer",
+				Description: "Approximate token budget for the response, counted as 4 bytes per token (optional, 0 or unset is unlimited). Providers that do not fit are omitted and a continuation_token is returned",
+				Minimum:     &zero,
+			},
+			"max_bytes": {
+				Type:        "integer",
+				Description: "Byte budget for the response (optional, 0 or unset is unlimited). The smaller of max_tokens and max_bytes applies",
+				Minimum:     &zero,
+			},
+			"continuation_token": {
+				Type:        "string",
+				Description: "Token from a truncated response; call again with the same arguments plus this token to get the omitted providers (overrides offset and height)",
+			},
 		},
 		AdditionalProperties: &jsonschema.Schema{},
 	}
@@ -13,7 +13,7 @@ func TestCreateProvidersToolInputSchema(t *testing.T) {
 	require.NotNil(t, schema)
 	assert.Equal(t, "object", schema.Type)
 	assert.NotNil(t, schema.Properties)
-	assert.Len(t, schema.Properties, 3)
+	assert.Len(t, schema.Properties, 11)
 
 	creatorProp := schema.Properties["creator"]
 	require.NotNil(t, creatorProp)
@@ -37,5 +37,41 @@ func TestCreateProvidersToolInputSchema(t *testing.T) {
 	assert.Equal(t, 0.0, *offsetProp.Minimum)
 	assert.Nil(t, offsetProp.Maximum)
 
+	networkProp := schema.Properties["network"]
+	require.NotNil(t, networkProp)
+	assert.Equal(t, "string", networkProp.Type)
+
+	heightProp := schema.Properties["height"]
+	require.NotNil(t, heightProp)
+	assert.Equal(t, "integer", heightProp.Type)
+
+	sourceProp := schema.Properties["source"]
+	require.NotNil(t, sourceProp)
+	assert.Equal(t, "string", sourceProp.Type)
+	assert.Equal(t, []any{"chain", "index"}, sourceProp.Enum)
+	require.NotNil(t, heightProp.Minimum)
+	assert.Equal(t, 0.0, *heightProp.Minimum)
+
+	formatProp := schema.Properties["format"]
+	require.NotNil(t, formatProp)
+	assert.Equal(t, "string", formatProp.Type)
+	assert.Equal(t, []any{"json", "compact_json", "markdown", "csv"}, formatProp.Enum)
+
+	fieldsProp := schema.Properties["fields"]
+	require.NotNil(t, fieldsProp)
+	assert.Equal(t, "string", fieldsProp.Type)
+
+	for _, name := range []string{"max_tokens", "max_bytes"} {
+		prop := schema.Properties[name]
+		require.NotNil(t, prop, name)
+		assert.Equal(t, "integer", prop.Type, name)
+		require.NotNil(t, prop.Minimum, name)
+		assert.Equal(t, 0.0, *prop.Minimum, name)
+	}
+
+	tokenProp := schema.Properties["continuation_token"]
+	require.NotNil(t, tokenProp)
+	assert.Equal(t, "string", tokenProp.Type)
+
 	assert.NotNil(t, schema.AdditionalProperties)
 }
@@ -0,0 +1,15 @@
+package schema
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// CreateListNetworksToolInputSchema creates the JSON schema for the list-networks tool input
+// The tool takes no arguments
+func CreateListNetworksToolInputSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:                 "object",
+		Properties:           map[string]*jsonschema.Schema{},
+		AdditionalProperties: &jsonschema.Schema{},
+	}
+}
@@ -0,0 +1,19 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateListNetworksToolInputSchema(t *testing.T) {
+	schema := CreateListNetworksToolInputSchema()
+
+	require.NotNil(t, schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.NotNil(t, schema.Properties)
+	assert.Empty(t, schema.Properties)
+	assert.Empty(t, schema.Required)
+	assert.NotNil(t, schema.AdditionalProperties)
+}
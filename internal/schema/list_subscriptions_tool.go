@@ -0,0 +1,14 @@
+package schema
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// CreateListSubscriptionsToolInputSchema creates the JSON schema for the list-subscriptions tool input
+func CreateListSubscriptionsToolInputSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:                 "object",
+		Properties:           map[string]*jsonschema.Schema{},
+		AdditionalProperties: &jsonschema.Schema{},
+	}
+}
@@ -0,0 +1,46 @@
+package schema
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// CreateListTransactionsToolInputSchema creates the JSON schema for the list-transactions tool input
+func CreateListTransactionsToolInputSchema() *jsonschema.Schema {
+	one, max := 1.0, 100.0
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"action": {
+				Type:        "string",
+				Description: "Message type the transactions must contain, matched on the message.action event. Bare names refer to Overlock crossplane messages, e.g. 'MsgCreateProvider', 'MsgUpdateEnvironment'; other modules need the full type, e.g. '/cosmos.bank.v1beta1.MsgSend'",
+			},
+			"sender": {
+				Type:        "string",
+				Description: "Bech32 address that sent the messages, matched on the message.sender event; pass the creator of a provider or environment to find its transactions",
+			},
+			"query": {
+				Type:        "string",
+				Description: "Additional CometBFT event query conditions, ANDed with action and sender, e.g. \"tx.height>=1000\". At least one of action, sender or query is required",
+			},
+			"page": {
+				Type:        "integer",
+				Description: "Page number, starting at 1 (optional, default: 1)",
+				Minimum:     &one,
+			},
+			"limit": {
+				Type:        "integer",
+				Description: "Transactions per page (optional, default: 20, max: 100)",
+				Minimum:     &one,
+				Maximum:     &max,
+			},
+			"order": {
+				Type:        "string",
+				Description: "Sort order by height: 'desc' (newest first, default) or 'asc' (oldest first)",
+				Enum:        []any{"desc", "asc"},
+			},
+			"network": networkProperty(),
+			"format":  formatProperty(false),
+		},
+		AdditionalProperties: &jsonschema.Schema{},
+	}
+}
@@ -0,0 +1,36 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateListTransactionsToolInputSchema(t *testing.T) {
+	schema := CreateListTransactionsToolInputSchema()
+
+	require.NotNil(t, schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.Len(t, schema.Properties, 8)
+
+	for _, name := range []string{"action", "sender", "query"} {
+		prop := schema.Properties[name]
+		require.NotNil(t, prop, name)
+		assert.Equal(t, "string", prop.Type)
+	}
+
+	limitProp := schema.Properties["limit"]
+	require.NotNil(t, limitProp)
+	assert.Equal(t, "integer", limitProp.Type)
+	assert.Equal(t, 1.0, *limitProp.Minimum)
+	assert.Equal(t, 100.0, *limitProp.Maximum)
+
+	assert.Equal(t, []any{"desc", "asc"}, schema.Properties["order"].Enum)
+	assert.Contains(t, schema.Properties, "page")
+	assert.Contains(t, schema.Properties, "network")
+	assert.Contains(t, schema.Properties, "format")
+
+	assert.Empty(t, schema.Required)
+	assert.NotNil(t, schema.AdditionalProperties)
+}
@@ -0,0 +1,46 @@
+package schema
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// CreateNetworkStatsToolInputSchema creates the JSON schema for the network-stats tool input
+func CreateNetworkStatsToolInputSchema() *jsonschema.Schema {
+	zero, max := 0.0, 1000.0
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"network": networkProperty(),
+			"height":  heightProperty(),
+			"source":  sourceProperty(),
+			"provider_group_by": {
+				Type:        "array",
+				Description: "Provider fields to count providers by (optional, defaults to all of them; pass an empty array for totals only)",
+				Items: &jsonschema.Schema{
+					Type: "string",
+					Enum: []any{"country_code", "environment_type", "availability", "creator"},
+				},
+			},
+			"environment_group_by": {
+				Type:        "array",
+				Description: "Environment fields to count environments by (optional, defaults to all of them; pass an empty array for totals only)",
+				Items: &jsonschema.Schema{
+					Type: "string",
+					Enum: []any{"provider", "creator"},
+				},
+			},
+			"bucket": {
+				Type:        "string",
+				Description: "Bucket size of the provider registrations-over-time histogram (optional, defaults to 'month')",
+				Enum:        []any{"day", "week", "month", "year"},
+			},
+			"top": {
+				Type:        "integer",
+				Description: "Most frequent values to list per group; the remaining records are summed into 'other' (optional, defaults to 25, 0 lists every value)",
+				Minimum:     &zero,
+				Maximum:     &max,
+			},
+		},
+		AdditionalProperties: &jsonschema.Schema{},
+	}
+}
@@ -0,0 +1,31 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateNetworkStatsToolInputSchema(t *testing.T) {
+	schema := CreateNetworkStatsToolInputSchema()
+
+	require.NotNil(t, schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.Len(t, schema.Properties, 7)
+
+	providerGroupBy := schema.Properties["provider_group_by"]
+	require.NotNil(t, providerGroupBy)
+	assert.Equal(t, "array", providerGroupBy.Type)
+	assert.Equal(t, []any{"country_code", "environment_type", "availability", "creator"}, providerGroupBy.Items.Enum)
+
+	environmentGroupBy := schema.Properties["environment_group_by"]
+	require.NotNil(t, environmentGroupBy)
+	assert.Equal(t, []any{"provider", "creator"}, environmentGroupBy.Items.Enum)
+
+	assert.Equal(t, []any{"day", "week", "month", "year"}, schema.Properties["bucket"].Enum)
+	assert.Equal(t, "integer", schema.Properties["top"].Type)
+
+	assert.Empty(t, schema.Required)
+	assert.NotNil(t, schema.AdditionalProperties)
+}
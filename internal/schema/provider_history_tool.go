@@ -0,0 +1,26 @@
+package schema
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// CreateProviderHistoryToolInputSchema creates the JSON schema for the provider-history tool input
+func CreateProviderHistoryToolInputSchema() *jsonschema.Schema {
+	minID := 1.0
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"id": {
+				Type:        "integer",
+				Description: "ID of the provider whose change history to return",
+				Minimum:     &minID,
+			},
+			"network": networkProperty(),
+			"since":   sinceProperty("defaults to the start of the recorded history"),
+			"until":   untilProperty(),
+			"limit":   historyLimitProperty(50),
+		},
+		Required:             []string{"id"},
+		AdditionalProperties: &jsonschema.Schema{},
+	}
+}
@@ -0,0 +1,31 @@
+package schema
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// CreateProviderUptimeToolInputSchema creates the JSON schema for the provider-uptime tool input
+func CreateProviderUptimeToolInputSchema() *jsonschema.Schema {
+	minID := 1.0
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"id": {
+				Type:        "integer",
+				Description: "ID of the provider whose uptime to report",
+				Minimum:     &minID,
+			},
+			"network": networkProperty(),
+			"since": {
+				Type:        "string",
+				Description: "Start of the reporting window: an RFC3339 timestamp or a duration ago such as '168h' (optional, defaults to '24h')",
+			},
+			"until": {
+				Type:        "string",
+				Description: "End of the reporting window: an RFC3339 timestamp or a duration ago such as '1h' (optional, defaults to now)",
+			},
+		},
+		Required:             []string{"id"},
+		AdditionalProperties: &jsonschema.Schema{},
+	}
+}
@@ -0,0 +1,31 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateProviderUptimeToolInputSchema(t *testing.T) {
+	schema := CreateProviderUptimeToolInputSchema()
+
+	require.NotNil(t, schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.Len(t, schema.Properties, 4)
+
+	idProp := schema.Properties["id"]
+	require.NotNil(t, idProp)
+	assert.Equal(t, "integer", idProp.Type)
+	require.NotNil(t, idProp.Minimum)
+	assert.Equal(t, 1.0, *idProp.Minimum)
+
+	for _, name := range []string{"network", "since", "until"} {
+		prop := schema.Properties[name]
+		require.NotNil(t, prop, name)
+		assert.Equal(t, "string", prop.Type, name)
+	}
+
+	assert.Equal(t, []string{"id"}, schema.Required)
+	assert.NotNil(t, schema.AdditionalProperties)
+}
@@ -0,0 +1,32 @@
+package schema
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// CreateRecentChangesToolInputSchema creates the JSON schema for the recent-changes tool input
+func CreateRecentChangesToolInputSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"network": {
+				Type:        "string",
+				Description: "Only return changes on this network (optional, defaults to all indexed networks)",
+			},
+			"kind": {
+				Type:        "string",
+				Description: "Only return changes to this kind of record (optional)",
+				Enum:        []any{"provider", "environment"},
+			},
+			"type": {
+				Type:        "string",
+				Description: "Only return this type of change (optional)",
+				Enum:        []any{"created", "updated", "removed"},
+			},
+			"since": sinceProperty("defaults to '168h'"),
+			"until": untilProperty(),
+			"limit": historyLimitProperty(100),
+		},
+		AdditionalProperties: &jsonschema.Schema{},
+	}
+}
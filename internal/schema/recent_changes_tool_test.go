@@ -0,0 +1,32 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateRecentChangesToolInputSchema(t *testing.T) {
+	schema := CreateRecentChangesToolInputSchema()
+
+	require.NotNil(t, schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.Len(t, schema.Properties, 6)
+
+	kindProp := schema.Properties["kind"]
+	require.NotNil(t, kindProp)
+	assert.Equal(t, "string", kindProp.Type)
+	assert.Equal(t, []any{"provider", "environment"}, kindProp.Enum)
+
+	typeProp := schema.Properties["type"]
+	require.NotNil(t, typeProp)
+	assert.Equal(t, []any{"created", "updated", "removed"}, typeProp.Enum)
+
+	limitProp := schema.Properties["limit"]
+	require.NotNil(t, limitProp)
+	assert.Equal(t, "integer", limitProp.Type)
+
+	assert.Empty(t, schema.Required)
+	assert.NotNil(t, schema.AdditionalProperties)
+}
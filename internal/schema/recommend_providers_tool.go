@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// CreateRecommendProvidersToolInputSchema creates the JSON schema for the recommend-providers tool input
+func CreateRecommendProvidersToolInputSchema() *jsonschema.Schema {
+	zero, one, max := 0.0, 1.0, 50.0
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"countries": {
+				Type:        "array",
+				Description: "Country codes the environment may be placed in, e.g. ['DE', 'FR'] (optional, any country when omitted)",
+				Items:       &jsonschema.Schema{Type: "string"},
+			},
+			"environment_type": {
+				Type:        "string",
+				Description: "Environment type the provider must offer, e.g. 'production' (optional)",
+			},
+			"annotations": {
+				Type:                 "object",
+				Description:          "Annotation values the provider must carry, e.g. {\"region\": \"eu-central-1\"} (optional)",
+				AdditionalProperties: &jsonschema.Schema{Types: []string{"string", "number", "boolean"}},
+			},
+			"max_environments": {
+				Type:        "integer",
+				Description: "Maximum number of environments a provider may already host (optional, no limit when omitted)",
+				Minimum:     &zero,
+			},
+			"include_unavailable": {
+				Type:        "boolean",
+				Description: "Rank providers that do not report themselves available, with a penalty, instead of excluding them (optional, defaults to false)",
+			},
+			"limit": {
+				Type:        "integer",
+				Description: "Number of candidates to return (optional, defaults to 5, max 50)",
+				Minimum:     &one,
+				Maximum:     &max,
+			},
+			"network": networkProperty(),
+			"height":  heightProperty(),
+			"source":  sourceProperty(),
+		},
+		AdditionalProperties: &jsonschema.Schema{},
+	}
+}
@@ -0,0 +1,36 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateRecommendProvidersToolInputSchema(t *testing.T) {
+	schema := CreateRecommendProvidersToolInputSchema()
+
+	require.NotNil(t, schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.Len(t, schema.Properties, 9)
+
+	countries := schema.Properties["countries"]
+	require.NotNil(t, countries)
+	assert.Equal(t, "array", countries.Type)
+	assert.Equal(t, "string", countries.Items.Type)
+
+	annotations := schema.Properties["annotations"]
+	require.NotNil(t, annotations)
+	assert.Equal(t, "object", annotations.Type)
+	assert.Equal(t, []string{"string", "number", "boolean"}, annotations.AdditionalProperties.Types)
+
+	assert.Equal(t, 0.0, *schema.Properties["max_environments"].Minimum)
+	assert.Equal(t, "boolean", schema.Properties["include_unavailable"].Type)
+	assert.Equal(t, 50.0, *schema.Properties["limit"].Maximum)
+	assert.Contains(t, schema.Properties, "network")
+	assert.Contains(t, schema.Properties, "height")
+	assert.Contains(t, schema.Properties, "source")
+
+	assert.Empty(t, schema.Required)
+	assert.NotNil(t, schema.AdditionalProperties)
+}
@@ -0,0 +1,23 @@
+package schema
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// CreateShowAccountToolInputSchema creates the JSON schema for the show-account tool input
+func CreateShowAccountToolInputSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"address": {
+				Type:        "string",
+				Description: "Bech32 Overlock account address to look up, e.g. overlock1...; the creator field of any provider or environment can be passed as is (required)",
+			},
+			"network": networkProperty(),
+			"height":  heightProperty(),
+			"format":  formatProperty(false),
+		},
+		Required:             []string{"address"},
+		AdditionalProperties: &jsonschema.Schema{},
+	}
+}
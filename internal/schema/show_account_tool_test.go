@@ -0,0 +1,27 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateShowAccountToolInputSchema(t *testing.T) {
+	schema := CreateShowAccountToolInputSchema()
+
+	require.NotNil(t, schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.Len(t, schema.Properties, 4)
+
+	addressProp := schema.Properties["address"]
+	require.NotNil(t, addressProp)
+	assert.Equal(t, "string", addressProp.Type)
+
+	assert.Contains(t, schema.Properties, "network")
+	assert.Contains(t, schema.Properties, "height")
+	assert.Equal(t, []any{"json", "compact_json", "markdown"}, schema.Properties["format"].Enum)
+
+	assert.Equal(t, []string{"address"}, schema.Required)
+	assert.NotNil(t, schema.AdditionalProperties)
+}
@@ -0,0 +1,24 @@
+package schema
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// CreateShowCreatorToolInputSchema creates the JSON schema for the show-creator tool input
+func CreateShowCreatorToolInputSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"creator": {
+				Type:        "string",
+				Description: "Bech32 Overlock account address whose providers and environments to retrieve, e.g. overlock1... (required)",
+			},
+			"network": networkProperty(),
+			"height":  heightProperty(),
+			"source":  sourceProperty(),
+			"expand":  expandProperty(),
+		},
+		Required:             []string{"creator"},
+		AdditionalProperties: &jsonschema.Schema{},
+	}
+}
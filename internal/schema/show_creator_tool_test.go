@@ -0,0 +1,28 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateShowCreatorToolInputSchema(t *testing.T) {
+	schema := CreateShowCreatorToolInputSchema()
+
+	require.NotNil(t, schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.Len(t, schema.Properties, 5)
+
+	creatorProp := schema.Properties["creator"]
+	require.NotNil(t, creatorProp)
+	assert.Equal(t, "string", creatorProp.Type)
+
+	assert.Contains(t, schema.Properties, "network")
+	assert.Contains(t, schema.Properties, "height")
+	assert.Equal(t, []any{"chain", "index"}, schema.Properties["source"].Enum)
+	assert.Equal(t, "boolean", schema.Properties["expand"].Type)
+
+	assert.Equal(t, []string{"creator"}, schema.Required)
+	assert.NotNil(t, schema.AdditionalProperties)
+}
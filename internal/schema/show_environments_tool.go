@@ -0,0 +1,21 @@
+package schema
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// CreateShowEnvironmentsToolInputSchema creates the JSON schema for the show-environments tool input
+func CreateShowEnvironmentsToolInputSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"ids":     batchIDsProperty("Environment"),
+			"network": networkProperty(),
+			"height":  heightProperty(),
+			"source":  sourceProperty(),
+			"expand":  expandProperty(),
+		},
+		Required:             []string{"ids"},
+		AdditionalProperties: &jsonschema.Schema{},
+	}
+}
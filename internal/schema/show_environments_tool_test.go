@@ -0,0 +1,33 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateShowEnvironmentsToolInputSchema(t *testing.T) {
+	schema := CreateShowEnvironmentsToolInputSchema()
+
+	require.NotNil(t, schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.Len(t, schema.Properties, 5)
+
+	idsProp := schema.Properties["ids"]
+	require.NotNil(t, idsProp)
+	assert.Equal(t, "array", idsProp.Type)
+	require.NotNil(t, idsProp.Items)
+	assert.Equal(t, "integer", idsProp.Items.Type)
+	assert.Equal(t, 1.0, *idsProp.Items.Minimum)
+	assert.Equal(t, 1, *idsProp.MinItems)
+	assert.Equal(t, 100, *idsProp.MaxItems)
+
+	assert.Contains(t, schema.Properties, "network")
+	assert.Contains(t, schema.Properties, "height")
+	assert.Equal(t, []any{"chain", "index"}, schema.Properties["source"].Enum)
+	assert.Equal(t, "boolean", schema.Properties["expand"].Type)
+
+	assert.Equal(t, []string{"ids"}, schema.Required)
+	assert.NotNil(t, schema.AdditionalProperties)
+}
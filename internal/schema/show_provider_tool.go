@@ -13,11 +13,16 @@ func CreateProviderToolInputSchema() *jsonschema.Schema {
 		Properties: map[string]*jsonschema.Schema{
 			"id": {
 				Type:        "integer",
-				Description: "Provider ID to retrieve detailed information for (required)",
+				Description: "Provider ID to retrieve detailed information for (one of id, name or uri is required)",
 				Minimum:     &one,
 			},
+			"name":    nameProperty("provider"),
+			"uri":     uriProperty("provider"),
+			"network": networkProperty(),
+			"height":  heightProperty(),
+			"source":  sourceProperty(),
+			"format":  formatProperty(false),
 		},
-		Required:             []string{"id"},
 		AdditionalProperties: &jsonschema.Schema{},
 	}
 }
\ No newline at end of file
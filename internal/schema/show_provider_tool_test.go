@@ -18,12 +18,33 @@ func TestCreateProviderToolInputSchema(t *testing.T) {
 	
 	idSchema := schema.Properties["id"]
 	assert.Equal(t, "integer", idSchema.Type)
-	assert.Equal(t, "Provider ID to retrieve detailed information for (required)", idSchema.Description)
+	assert.Equal(t, "Provider ID to retrieve detailed information for (one of id, name or uri is required)", idSchema.Description)
 	assert.Equal(t, 1.0, *idSchema.Minimum)
 
-	// Check required fields
-	assert.Contains(t, schema.Required, "id")
-	assert.Len(t, schema.Required, 1)
+	// Check that the alternative name and uri properties exist
+	assert.Contains(t, schema.Properties, "name")
+	assert.Equal(t, "string", schema.Properties["name"].Type)
+	assert.Contains(t, schema.Properties, "uri")
+	assert.Contains(t, schema.Properties["uri"].Description, "overlock://mainnet/providers/7")
+
+	// Check that the optional network property exists
+	assert.Contains(t, schema.Properties, "network")
+	assert.Equal(t, "string", schema.Properties["network"].Type)
+
+	// Check that the optional height property exists
+	assert.Contains(t, schema.Properties, "height")
+	assert.Equal(t, "integer", schema.Properties["height"].Type)
+
+	// Check that the optional source property exists
+	assert.Contains(t, schema.Properties, "source")
+	assert.Equal(t, []any{"chain", "index"}, schema.Properties["source"].Enum)
+
+	// Check that the optional format property exists, without csv for a single record
+	assert.Contains(t, schema.Properties, "format")
+	assert.Equal(t, []any{"json", "compact_json", "markdown"}, schema.Properties["format"].Enum)
+
+	// id, name and uri are alternatives, so none is required by the schema
+	assert.Empty(t, schema.Required)
 	
 	// Check additional properties
 	assert.NotNil(t, schema.AdditionalProperties)
@@ -0,0 +1,20 @@
+package schema
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// CreateShowProvidersToolInputSchema creates the JSON schema for the show-providers tool input
+func CreateShowProvidersToolInputSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"ids":     batchIDsProperty("Provider"),
+			"network": networkProperty(),
+			"height":  heightProperty(),
+			"source":  sourceProperty(),
+		},
+		Required:             []string{"ids"},
+		AdditionalProperties: &jsonschema.Schema{},
+	}
+}
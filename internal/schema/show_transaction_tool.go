@@ -0,0 +1,22 @@
+package schema
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// CreateShowTransactionToolInputSchema creates the JSON schema for the show-transaction tool input
+func CreateShowTransactionToolInputSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"hash": {
+				Type:        "string",
+				Description: "Hex encoded transaction hash, 64 characters, with or without a 0x prefix (required)",
+			},
+			"network": networkProperty(),
+			"format":  formatProperty(false),
+		},
+		Required:             []string{"hash"},
+		AdditionalProperties: &jsonschema.Schema{},
+	}
+}
@@ -0,0 +1,26 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateShowTransactionToolInputSchema(t *testing.T) {
+	schema := CreateShowTransactionToolInputSchema()
+
+	require.NotNil(t, schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.Len(t, schema.Properties, 3)
+
+	hashProp := schema.Properties["hash"]
+	require.NotNil(t, hashProp)
+	assert.Equal(t, "string", hashProp.Type)
+
+	assert.Contains(t, schema.Properties, "network")
+	assert.Equal(t, []any{"json", "compact_json", "markdown"}, schema.Properties["format"].Enum)
+
+	assert.Equal(t, []string{"hash"}, schema.Required)
+	assert.NotNil(t, schema.AdditionalProperties)
+}
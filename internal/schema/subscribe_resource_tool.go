@@ -0,0 +1,35 @@
+package schema
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// CreateSubscribeResourceToolInputSchema creates the JSON schema for the subscribe-resource tool input
+func CreateSubscribeResourceToolInputSchema() *jsonschema.Schema {
+	minID := 1.0
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"uri": {
+				Type:        "string",
+				Description: "Resource to watch, e.g. overlock://mainnet/providers/7 or overlock://mainnet/environments for the whole collection (use either uri or kind)",
+			},
+			"kind": {
+				Type:        "string",
+				Description: "What to watch when no uri is given: a single provider or environment (requires id) or the providers or environments collection",
+				Enum:        []any{"provider", "environment", "providers", "environments"},
+			},
+			"id": {
+				Type:        "integer",
+				Description: "ID of the watched provider or environment (required with kind 'provider' or 'environment')",
+				Minimum:     &minID,
+			},
+			"network": networkProperty(),
+			"min_interval": {
+				Type:        "string",
+				Description: "Minimum time between polls of the resource, e.g. '30s' or '5m' (optional, defaults to '30s'; raised to the server's minimum)",
+			},
+		},
+		AdditionalProperties: &jsonschema.Schema{},
+	}
+}
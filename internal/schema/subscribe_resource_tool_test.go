@@ -0,0 +1,33 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSubscribeResourceToolInputSchema(t *testing.T) {
+	schema := CreateSubscribeResourceToolInputSchema()
+
+	require.NotNil(t, schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.Len(t, schema.Properties, 5)
+
+	kindProp := schema.Properties["kind"]
+	require.NotNil(t, kindProp)
+	assert.Equal(t, []any{"provider", "environment", "providers", "environments"}, kindProp.Enum)
+
+	idProp := schema.Properties["id"]
+	require.NotNil(t, idProp)
+	assert.Equal(t, "integer", idProp.Type)
+
+	for _, name := range []string{"uri", "network", "min_interval"} {
+		prop := schema.Properties[name]
+		require.NotNil(t, prop, name)
+		assert.Equal(t, "string", prop.Type, name)
+	}
+
+	assert.Empty(t, schema.Required)
+	assert.NotNil(t, schema.AdditionalProperties)
+}
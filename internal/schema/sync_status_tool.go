@@ -0,0 +1,19 @@
+package schema
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// CreateSyncStatusToolInputSchema creates the JSON schema for the sync-status tool input
+func CreateSyncStatusToolInputSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"network": {
+				Type:        "string",
+				Description: "Only report the index of this network (optional, defaults to all indexed networks)",
+			},
+		},
+		AdditionalProperties: &jsonschema.Schema{},
+	}
+}
@@ -0,0 +1,23 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSyncStatusToolInputSchema(t *testing.T) {
+	schema := CreateSyncStatusToolInputSchema()
+
+	require.NotNil(t, schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.Len(t, schema.Properties, 1)
+
+	networkProp := schema.Properties["network"]
+	require.NotNil(t, networkProp)
+	assert.Equal(t, "string", networkProp.Type)
+
+	assert.Empty(t, schema.Required)
+	assert.NotNil(t, schema.AdditionalProperties)
+}
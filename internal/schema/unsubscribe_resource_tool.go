@@ -0,0 +1,20 @@
+package schema
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// CreateUnsubscribeResourceToolInputSchema creates the JSON schema for the unsubscribe-resource tool input
+func CreateUnsubscribeResourceToolInputSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"subscription": {
+				Type:        "string",
+				Description: "ID of the subscription returned by subscribe-resource, or the watched resource URI",
+			},
+		},
+		Required:             []string{"subscription"},
+		AdditionalProperties: &jsonschema.Schema{},
+	}
+}
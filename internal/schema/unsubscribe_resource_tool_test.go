@@ -0,0 +1,23 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateUnsubscribeResourceToolInputSchema(t *testing.T) {
+	schema := CreateUnsubscribeResourceToolInputSchema()
+
+	require.NotNil(t, schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.Len(t, schema.Properties, 1)
+
+	subscriptionProp := schema.Properties["subscription"]
+	require.NotNil(t, subscriptionProp)
+	assert.Equal(t, "string", subscriptionProp.Type)
+
+	assert.Equal(t, []string{"subscription"}, schema.Required)
+	assert.NotNil(t, schema.AdditionalProperties)
+}
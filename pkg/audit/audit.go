@@ -0,0 +1,171 @@
+package audit
+
+import (
+	"sort"
+
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+)
+
+// Severity ranks how serious a finding is
+type Severity string
+
+// Severities, from most to least serious
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Severities lists every severity, from most to least serious
+var Severities = []string{string(SeverityError), string(SeverityWarning), string(SeverityInfo)}
+
+// rank orders severities, most serious first
+func (s Severity) rank() int {
+	switch s {
+	case SeverityError:
+		return 0
+	case SeverityWarning:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// AtLeast reports whether s is at least as serious as min
+func (s Severity) AtLeast(min Severity) bool {
+	return s.rank() <= min.rank()
+}
+
+// Record kinds a finding refers to
+const (
+	KindProvider    = "provider"
+	KindEnvironment = "environment"
+)
+
+// Records are the registry records an audit scans
+type Records struct {
+	Providers    []overlockv1beta1.Provider
+	Environments []overlockv1beta1.Environment
+	Truncated    bool // not every record could be read
+}
+
+// Finding is a record that breaks a rule
+type Finding struct {
+	Kind    string `json:"kind"`
+	Id      uint64 `json:"id"`
+	Message string `json:"message"`
+}
+
+// Rule is a single data-quality check. Add a rule by appending it to the list returned by Rules.
+type Rule struct {
+	Name        string
+	Severity    Severity
+	Description string
+	// NeedsAllRecords skips the rule when the records are truncated, because missing
+	// records would turn into false findings
+	NeedsAllRecords bool
+	Check           func(records Records) []Finding
+}
+
+// RuleReport holds the findings of one rule
+type RuleReport struct {
+	Rule        string    `json:"rule"`
+	Severity    Severity  `json:"severity"`
+	Description string    `json:"description"`
+	Count       int       `json:"count"`
+	Findings    []Finding `json:"findings"`
+	Truncated   bool      `json:"truncated,omitempty"` // more findings than the limit
+}
+
+// Scanned counts the records an audit read
+type Scanned struct {
+	Providers    int `json:"providers"`
+	Environments int `json:"environments"`
+}
+
+// Report is the result of an audit
+type Report struct {
+	Scanned    Scanned          `json:"scanned"`
+	BySeverity map[Severity]int `json:"by_severity"` // findings per severity
+	Rules      []RuleReport     `json:"rules"`       // rules with findings, most serious first
+	Passed     []string         `json:"passed"`      // rules without findings
+	Skipped    []string         `json:"skipped"`     // rules that need every record while the records are truncated
+}
+
+// Run checks the records against every rule, keeping at most limit findings per rule
+// (all of them when limit is not positive)
+func Run(records Records, rules []Rule, limit int) Report {
+	report := Report{
+		Scanned:    Scanned{Providers: len(records.Providers), Environments: len(records.Environments)},
+		BySeverity: make(map[Severity]int),
+		Rules:      []RuleReport{},
+		Passed:     []string{},
+		Skipped:    []string{},
+	}
+	for _, rule := range rules {
+		if rule.NeedsAllRecords && records.Truncated {
+			report.Skipped = append(report.Skipped, rule.Name)
+			continue
+		}
+		findings := rule.Check(records)
+		if len(findings) == 0 {
+			report.Passed = append(report.Passed, rule.Name)
+			continue
+		}
+		sort.SliceStable(findings, func(a, b int) bool {
+			if findings[a].Kind != findings[b].Kind {
+				return findings[a].Kind > findings[b].Kind // providers first
+			}
+			return findings[a].Id < findings[b].Id
+		})
+		ruleReport := RuleReport{
+			Rule:        rule.Name,
+			Severity:    rule.Severity,
+			Description: rule.Description,
+			Count:       len(findings),
+			Findings:    findings,
+		}
+		if limit > 0 && len(findings) > limit {
+			ruleReport.Findings = findings[:limit]
+			ruleReport.Truncated = true
+		}
+		report.BySeverity[rule.Severity] += len(findings)
+		report.Rules = append(report.Rules, ruleReport)
+	}
+	sort.SliceStable(report.Rules, func(a, b int) bool {
+		if report.Rules[a].Severity != report.Rules[b].Severity {
+			return report.Rules[a].Severity.rank() < report.Rules[b].Severity.rank()
+		}
+		return report.Rules[a].Rule < report.Rules[b].Rule
+	})
+	return report
+}
+
+// Select returns the rules with the given names that are at least as serious as min,
+// keeping the order of rules; every rule qualifies when names is empty
+func Select(rules []Rule, names []string, min Severity) []Rule {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+	var selected []Rule
+	for _, rule := range rules {
+		if len(names) > 0 && !wanted[rule.Name] {
+			continue
+		}
+		if !rule.Severity.AtLeast(min) {
+			continue
+		}
+		selected = append(selected, rule)
+	}
+	return selected
+}
+
+// Names returns the names of the rules
+func Names(rules []Rule) []string {
+	names := make([]string, len(rules))
+	for i, rule := range rules {
+		names[i] = rule.Name
+	}
+	return names
+}
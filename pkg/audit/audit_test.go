@@ -0,0 +1,107 @@
+package audit
+
+import (
+	"testing"
+
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRecords() Records {
+	return Records{
+		Providers: []overlockv1beta1.Provider{
+			{Id: 1, Ip: "203.0.113.10", Port: 8080, Metadata: &overlockv1beta1.Metadata{Name: "good", Annotations: `{"region":"eu"}`}},
+			{Id: 2, Ip: "10.0.0.2", Port: 0, Metadata: &overlockv1beta1.Metadata{Name: "private", Annotations: "region=eu"}},
+			{Id: 3, Ip: "127.0.0.1", Port: 9000},
+			{Id: 4, Ip: "203.0.113.10", Port: 8080, Metadata: &overlockv1beta1.Metadata{Name: "copy"}},
+			{Id: 5, Ip: "provider.example.com", Port: 443, Metadata: &overlockv1beta1.Metadata{Name: "hostname"}},
+		},
+		Environments: []overlockv1beta1.Environment{
+			{Id: 1001, Provider: 1, Metadata: &overlockv1beta1.Metadata{Name: "env"}},
+			{Id: 1002, Provider: 42, Metadata: &overlockv1beta1.Metadata{Annotations: "{"}},
+		},
+	}
+}
+
+func ruleReport(t *testing.T, report Report, name string) RuleReport {
+	for _, r := range report.Rules {
+		if r.Rule == name {
+			return r
+		}
+	}
+	require.Failf(t, "rule not reported", "no findings for %s", name)
+	return RuleReport{}
+}
+
+func TestRun(t *testing.T) {
+	report := Run(testRecords(), Rules(), 0)
+
+	assert.Equal(t, Scanned{Providers: 5, Environments: 2}, report.Scanned)
+	assert.Empty(t, report.Passed)
+	assert.Empty(t, report.Skipped)
+
+	annotations := ruleReport(t, report, RuleInvalidAnnotations)
+	assert.Equal(t, SeverityError, annotations.Severity)
+	require.Len(t, annotations.Findings, 2)
+	// Providers are listed before environments
+	assert.Equal(t, Finding{Kind: KindProvider, Id: 2, Message: `annotations "region=eu" are not a JSON object`}, annotations.Findings[0])
+	assert.Equal(t, uint64(1002), annotations.Findings[1].Id)
+
+	ips := ruleReport(t, report, RuleNonPublicIP)
+	require.Len(t, ips.Findings, 2)
+	assert.Equal(t, "IP 10.0.0.2 is a private address", ips.Findings[0].Message)
+	assert.Equal(t, "IP 127.0.0.1 is a loopback address", ips.Findings[1].Message)
+
+	ports := ruleReport(t, report, RuleInvalidPort)
+	assert.Equal(t, 1, ports.Count)
+
+	duplicates := ruleReport(t, report, RuleDuplicateEndpoint)
+	require.Len(t, duplicates.Findings, 2)
+	assert.Equal(t, "endpoint 203.0.113.10:8080 is also registered by provider 4", duplicates.Findings[0].Message)
+
+	names := ruleReport(t, report, RuleMissingName)
+	assert.Equal(t, 2, names.Count)
+
+	dangling := ruleReport(t, report, RuleDanglingProvider)
+	assert.Equal(t, []Finding{{Kind: KindEnvironment, Id: 1002, Message: "provider 42 does not exist"}}, dangling.Findings)
+
+	assert.Equal(t, map[Severity]int{SeverityError: 6, SeverityWarning: 4}, report.BySeverity)
+	// Errors are listed before warnings
+	assert.Equal(t, SeverityError, report.Rules[0].Severity)
+	assert.Equal(t, SeverityWarning, report.Rules[len(report.Rules)-1].Severity)
+}
+
+func TestRun_LimitAndTruncatedRecords(t *testing.T) {
+	records := testRecords()
+	records.Truncated = true
+
+	report := Run(records, Rules(), 1)
+
+	assert.Equal(t, []string{RuleDanglingProvider}, report.Skipped)
+	names := ruleReport(t, report, RuleMissingName)
+	assert.Equal(t, 2, names.Count)
+	assert.Len(t, names.Findings, 1)
+	assert.True(t, names.Truncated)
+}
+
+func TestRun_CustomRule(t *testing.T) {
+	rule := Rule{
+		Name:     "no-providers",
+		Severity: SeverityInfo,
+		Check: func(records Records) []Finding {
+			return nil
+		},
+	}
+
+	report := Run(testRecords(), []Rule{rule}, 0)
+
+	assert.Equal(t, []string{"no-providers"}, report.Passed)
+	assert.Empty(t, report.Rules)
+}
+
+func TestSelect(t *testing.T) {
+	assert.Equal(t, Names(Rules()), Names(Select(Rules(), nil, SeverityInfo)))
+	assert.Equal(t, []string{RuleInvalidAnnotations, RuleNonPublicIP, RuleInvalidPort, RuleDanglingProvider}, Names(Select(Rules(), nil, SeverityError)))
+	assert.Equal(t, []string{RuleInvalidPort}, Names(Select(Rules(), []string{RuleInvalidPort, RuleMissingName}, SeverityError)))
+}
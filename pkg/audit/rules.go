@@ -0,0 +1,179 @@
+package audit
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"overlock-mcp-server/pkg/recommend"
+
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+)
+
+// Built-in rule names
+const (
+	RuleInvalidAnnotations = "invalid-annotations"
+	RuleNonPublicIP        = "non-public-ip"
+	RuleInvalidPort        = "invalid-port"
+	RuleDuplicateEndpoint  = "duplicate-endpoint"
+	RuleMissingName        = "missing-name"
+	RuleDanglingProvider   = "dangling-provider"
+)
+
+// Rules returns the built-in rule set
+func Rules() []Rule {
+	return []Rule{
+		{
+			Name:        RuleInvalidAnnotations,
+			Severity:    SeverityError,
+			Description: "Annotations are set but are not a JSON object",
+			Check:       checkAnnotations,
+		},
+		{
+			Name:        RuleNonPublicIP,
+			Severity:    SeverityError,
+			Description: "Provider IP is private, loopback, link-local or unspecified and cannot be reached from the internet",
+			Check:       checkPublicIP,
+		},
+		{
+			Name:        RuleInvalidPort,
+			Severity:    SeverityError,
+			Description: "Provider port is 0",
+			Check:       checkPort,
+		},
+		{
+			Name:        RuleDuplicateEndpoint,
+			Severity:    SeverityWarning,
+			Description: "Several providers register the same ip:port endpoint",
+			Check:       checkDuplicateEndpoints,
+		},
+		{
+			Name:        RuleMissingName,
+			Severity:    SeverityWarning,
+			Description: "Provider or environment has no metadata name",
+			Check:       checkNames,
+		},
+		{
+			Name:            RuleDanglingProvider,
+			Severity:        SeverityError,
+			Description:     "Environment refers to a provider ID that does not exist",
+			NeedsAllRecords: true,
+			Check:           checkProviderReferences,
+		},
+	}
+}
+
+func checkAnnotations(records Records) []Finding {
+	var findings []Finding
+	check := func(kind string, id uint64, metadata *overlockv1beta1.Metadata) {
+		if _, err := recommend.ParseAnnotations(metadata); err != nil {
+			findings = append(findings, Finding{Kind: kind, Id: id, Message: fmt.Sprintf("annotations %q are not a JSON object", metadata.Annotations)})
+		}
+	}
+	for _, provider := range records.Providers {
+		check(KindProvider, provider.Id, provider.Metadata)
+	}
+	for _, environment := range records.Environments {
+		check(KindEnvironment, environment.Id, environment.Metadata)
+	}
+	return findings
+}
+
+func checkPublicIP(records Records) []Finding {
+	var findings []Finding
+	for _, provider := range records.Providers {
+		if kind := NonPublicKind(provider.Ip); kind != "" {
+			findings = append(findings, Finding{Kind: KindProvider, Id: provider.Id, Message: fmt.Sprintf("IP %s is a %s address", provider.Ip, kind)})
+		}
+	}
+	return findings
+}
+
+// NonPublicKind names the kind of address ip is when it cannot be reached from the internet,
+// such as "private" or "loopback". It returns "" for public IPs and for values that are not
+// IPs, like host names.
+func NonPublicKind(ip string) string {
+	parsed := net.ParseIP(ip)
+	switch {
+	case parsed == nil:
+		return ""
+	case parsed.IsLoopback():
+		return "loopback"
+	case parsed.IsPrivate():
+		return "private"
+	case parsed.IsLinkLocalUnicast():
+		return "link-local"
+	case parsed.IsUnspecified():
+		return "unspecified"
+	default:
+		return ""
+	}
+}
+
+func checkPort(records Records) []Finding {
+	var findings []Finding
+	for _, provider := range records.Providers {
+		if provider.Port == 0 {
+			findings = append(findings, Finding{Kind: KindProvider, Id: provider.Id, Message: "port is 0"})
+		}
+	}
+	return findings
+}
+
+func checkDuplicateEndpoints(records Records) []Finding {
+	byEndpoint := make(map[string][]uint64)
+	for _, provider := range records.Providers {
+		if provider.Ip == "" || provider.Port == 0 {
+			continue
+		}
+		endpoint := net.JoinHostPort(provider.Ip, fmt.Sprint(provider.Port))
+		byEndpoint[endpoint] = append(byEndpoint[endpoint], provider.Id)
+	}
+	var findings []Finding
+	for endpoint, ids := range byEndpoint {
+		if len(ids) < 2 {
+			continue
+		}
+		sort.Slice(ids, func(a, b int) bool { return ids[a] < ids[b] })
+		for _, id := range ids {
+			var others []string
+			for _, other := range ids {
+				if other != id {
+					others = append(others, fmt.Sprint(other))
+				}
+			}
+			findings = append(findings, Finding{Kind: KindProvider, Id: id, Message: fmt.Sprintf("endpoint %s is also registered by provider %s", endpoint, strings.Join(others, ", "))})
+		}
+	}
+	return findings
+}
+
+func checkNames(records Records) []Finding {
+	var findings []Finding
+	for _, provider := range records.Providers {
+		if provider.Metadata == nil || strings.TrimSpace(provider.Metadata.Name) == "" {
+			findings = append(findings, Finding{Kind: KindProvider, Id: provider.Id, Message: "metadata name is empty"})
+		}
+	}
+	for _, environment := range records.Environments {
+		if environment.Metadata == nil || strings.TrimSpace(environment.Metadata.Name) == "" {
+			findings = append(findings, Finding{Kind: KindEnvironment, Id: environment.Id, Message: "metadata name is empty"})
+		}
+	}
+	return findings
+}
+
+func checkProviderReferences(records Records) []Finding {
+	providers := make(map[uint64]bool, len(records.Providers))
+	for _, provider := range records.Providers {
+		providers[provider.Id] = true
+	}
+	var findings []Finding
+	for _, environment := range records.Environments {
+		if !providers[environment.Provider] {
+			findings = append(findings, Finding{Kind: KindEnvironment, Id: environment.Id, Message: fmt.Sprintf("provider %d does not exist", environment.Provider)})
+		}
+	}
+	return findings
+}
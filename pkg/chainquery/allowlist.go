@@ -0,0 +1,80 @@
+package chainquery
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultAllowlist lists the read-only query services callable when no allowlist is configured.
+// The tendermint service is listed by method to leave out ABCIQuery, which reads the raw state
+// of any module and runs transaction simulations.
+var DefaultAllowlist = []string{
+	"cosmos.auth.v1beta1.Query",
+	"cosmos.bank.v1beta1.Query",
+	"cosmos.base.tendermint.v1beta1.Service/GetBlockByHeight",
+	"cosmos.base.tendermint.v1beta1.Service/GetLatestBlock",
+	"cosmos.base.tendermint.v1beta1.Service/GetLatestValidatorSet",
+	"cosmos.base.tendermint.v1beta1.Service/GetNodeInfo",
+	"cosmos.base.tendermint.v1beta1.Service/GetSyncing",
+	"cosmos.base.tendermint.v1beta1.Service/GetValidatorSetByHeight",
+	"cosmos.distribution.v1beta1.Query",
+	"cosmos.gov.v1.Query",
+	"cosmos.mint.v1beta1.Query",
+	"cosmos.slashing.v1beta1.Query",
+	"cosmos.staking.v1beta1.Query",
+	"cosmos.upgrade.v1beta1.Query",
+	"overlock.crossplane.v1beta1.Query",
+}
+
+// Allowlist restricts the methods the chain-query tool may call.
+// An entry is either a whole service such as "cosmos.bank.v1beta1.Query"
+// (or "cosmos.bank.v1beta1.Query/*") or a single method such as
+// "cosmos.bank.v1beta1.Query/Balance".
+type Allowlist struct {
+	services map[string]bool
+	methods  map[string]bool
+}
+
+// ParseAllowlist builds an allowlist from its entries
+func ParseAllowlist(entries []string) (Allowlist, error) {
+	a := Allowlist{services: make(map[string]bool), methods: make(map[string]bool)}
+	for _, entry := range entries {
+		entry = strings.TrimPrefix(strings.TrimSpace(entry), "/")
+		if entry == "" {
+			continue
+		}
+		service, method, hasMethod := strings.Cut(entry, "/")
+		if service == "" || strings.ContainsAny(service, " *") || strings.Contains(method, "/") {
+			return Allowlist{}, fmt.Errorf("invalid allowlist entry '%s': expected <package.Service> or <package.Service>/<Method>", entry)
+		}
+		switch {
+		case !hasMethod || method == "*":
+			a.services[service] = true
+		case method == "":
+			return Allowlist{}, fmt.Errorf("invalid allowlist entry '%s': missing method after '/'", entry)
+		default:
+			a.methods[service+"/"+method] = true
+		}
+	}
+	return a, nil
+}
+
+// Allows reports whether the method, named <package.Service>/<Method>, may be called
+func (a Allowlist) Allows(fullMethod string) bool {
+	service, _, _ := strings.Cut(fullMethod, "/")
+	return a.services[service] || a.methods[fullMethod]
+}
+
+// Entries returns the allowlist entries in sorted order, services as <package.Service>/*
+func (a Allowlist) Entries() []string {
+	entries := make([]string, 0, len(a.services)+len(a.methods))
+	for service := range a.services {
+		entries = append(entries, service+"/*")
+	}
+	for method := range a.methods {
+		entries = append(entries, method)
+	}
+	sort.Strings(entries)
+	return entries
+}
@@ -0,0 +1,90 @@
+// Package chainquery calls arbitrary gRPC query methods of a node by name, encoding
+// requests and responses as JSON through dynamically resolved message descriptors.
+package chainquery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// SplitMethod splits a method name into its service and method parts. It accepts
+// "cosmos.bank.v1beta1.Query/Balance", with or without a leading slash, as well as
+// the dotted "cosmos.bank.v1beta1.Query.Balance".
+func SplitMethod(name string) (service, method string, err error) {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "/")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		service, method = name[:i], name[i+1:]
+	} else if i := strings.LastIndex(name, "."); i >= 0 {
+		service, method = name[:i], name[i+1:]
+	}
+	if service == "" || method == "" || strings.ContainsAny(service, "/ ") || !strings.Contains(service, ".") {
+		return "", "", fmt.Errorf("invalid method '%s': expected <package.Service>/<Method>, e.g. cosmos.bank.v1beta1.Query/Balance", name)
+	}
+	return service, method, nil
+}
+
+// FullMethod returns the canonical <package.Service>/<Method> form of a method name
+func FullMethod(name string) (string, error) {
+	service, method, err := SplitMethod(name)
+	if err != nil {
+		return "", err
+	}
+	return service + "/" + method, nil
+}
+
+// NewRequest decodes the protobuf JSON form of a method's request message.
+// Only unary methods are supported.
+func NewRequest(method Method, request json.RawMessage) (proto.Message, error) {
+	descriptor := method.Descriptor
+	if descriptor.IsStreamingClient() || descriptor.IsStreamingServer() {
+		return nil, &RequestError{Err: fmt.Errorf("method '%s' is streaming; only unary methods can be called", descriptor.FullName())}
+	}
+	in := dynamicpb.NewMessage(descriptor.Input())
+	if len(request) > 0 {
+		unmarshal := protojson.UnmarshalOptions{Resolver: method.Types}
+		if err := unmarshal.Unmarshal(request, in); err != nil {
+			return nil, &RequestError{Err: err}
+		}
+	}
+	return in, nil
+}
+
+// Invoke calls a unary method with a request built by NewRequest, returning the
+// response as protobuf JSON with the original proto field names
+func Invoke(ctx context.Context, conn grpc.ClientConnInterface, method Method, in proto.Message, opts ...grpc.CallOption) (json.RawMessage, error) {
+	descriptor := method.Descriptor
+	out := dynamicpb.NewMessage(descriptor.Output())
+	fullMethod := fmt.Sprintf("/%s/%s", descriptor.Parent().FullName(), descriptor.Name())
+	if err := conn.Invoke(ctx, fullMethod, in, out, opts...); err != nil {
+		return nil, err
+	}
+
+	marshal := protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true, Resolver: method.Types}
+	encoded, err := marshal.Marshal(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the %s response: %w", descriptor.Output().FullName(), err)
+	}
+	return encoded, nil
+}
+
+// RequestError reports arguments that do not match the method's request message
+type RequestError struct {
+	Err error
+}
+
+// Error implements error
+func (e *RequestError) Error() string {
+	return "invalid request: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying decoding error
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
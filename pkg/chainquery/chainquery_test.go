@@ -0,0 +1,137 @@
+package chainquery
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// newTestConn serves the health service, with server reflection, over an in-memory listener
+func newTestConn(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("overlock", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestSplitMethod(t *testing.T) {
+	for _, name := range []string{
+		"cosmos.bank.v1beta1.Query/Balance",
+		"/cosmos.bank.v1beta1.Query/Balance",
+		"cosmos.bank.v1beta1.Query.Balance",
+	} {
+		full, err := FullMethod(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, "cosmos.bank.v1beta1.Query/Balance", full)
+	}
+
+	for _, name := range []string{"", "Balance", "Query/Balance", "cosmos.bank.v1beta1.Query/", "a/b/c"} {
+		_, err := FullMethod(name)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestAllowlist(t *testing.T) {
+	allowlist, err := ParseAllowlist([]string{"cosmos.bank.v1beta1.Query", " /cosmos.auth.v1beta1.Query/Account ", "cosmos.gov.v1.Query/*", ""})
+	require.NoError(t, err)
+
+	assert.True(t, allowlist.Allows("cosmos.bank.v1beta1.Query/Balance"))
+	assert.True(t, allowlist.Allows("cosmos.auth.v1beta1.Query/Account"))
+	assert.False(t, allowlist.Allows("cosmos.auth.v1beta1.Query/Accounts"))
+	assert.True(t, allowlist.Allows("cosmos.gov.v1.Query/Proposal"))
+	assert.False(t, allowlist.Allows("cosmos.tx.v1beta1.Service/BroadcastTx"))
+	assert.Equal(t, []string{"cosmos.auth.v1beta1.Query/Account", "cosmos.bank.v1beta1.Query/*", "cosmos.gov.v1.Query/*"}, allowlist.Entries())
+
+	for _, entry := range []string{"cosmos.bank.v1beta1.Query/", "cosmos.*", "a/b/c"} {
+		_, err := ParseAllowlist([]string{entry})
+		assert.Error(t, err, entry)
+	}
+
+	defaults, err := ParseAllowlist(DefaultAllowlist)
+	require.NoError(t, err)
+	assert.True(t, defaults.Allows("cosmos.base.tendermint.v1beta1.Service/GetLatestBlock"))
+	assert.False(t, defaults.Allows("cosmos.base.tendermint.v1beta1.Service/ABCIQuery"))
+}
+
+func TestRegistryResolver(t *testing.T) {
+	conn := newTestConn(t)
+
+	method, err := NewRegistryResolver().FindMethod(context.Background(), conn, "grpc.health.v1.Health/Check")
+	require.NoError(t, err)
+
+	request, err := NewRequest(method, json.RawMessage(`{"service":"overlock"}`))
+	require.NoError(t, err)
+	response, err := Invoke(context.Background(), conn, method, request)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"status":"SERVING"}`, string(response))
+
+	_, err = NewRegistryResolver().FindMethod(context.Background(), conn, "grpc.health.v1.Health/Nope")
+	assert.ErrorIs(t, err, ErrUnknownMethod)
+}
+
+func TestReflectionResolver(t *testing.T) {
+	conn := newTestConn(t)
+	resolver := NewReflectionResolver()
+
+	method, err := resolver.FindMethod(context.Background(), conn, "grpc.health.v1.Health/Check")
+	require.NoError(t, err)
+	assert.Equal(t, "grpc.health.v1.HealthCheckRequest", string(method.Descriptor.Input().FullName()))
+
+	request, err := NewRequest(method, json.RawMessage(`{"service":"unknown"}`))
+	require.NoError(t, err)
+	response, err := Invoke(context.Background(), conn, method, request)
+	assert.Error(t, err, "the health service rejects unknown services")
+	assert.Nil(t, response)
+
+	_, err = NewRequest(method, json.RawMessage(`{"no_such_field":1}`))
+	var requestErr *RequestError
+	assert.ErrorAs(t, err, &requestErr)
+
+	_, err = resolver.FindMethod(context.Background(), conn, "cosmos.bank.v1beta1.Query/Balance")
+	assert.ErrorIs(t, err, ErrUnknownMethod)
+
+	// Streaming methods cannot be called
+	watch, err := resolver.FindMethod(context.Background(), conn, "grpc.health.v1.Health/Watch")
+	require.NoError(t, err)
+	_, err = NewRequest(watch, nil)
+	assert.ErrorAs(t, err, &requestErr)
+	assert.ErrorContains(t, err, "streaming")
+}
+
+func TestResolvers_FallBack(t *testing.T) {
+	conn := newTestConn(t)
+	resolvers := Resolvers{&RegistryResolver{Files: new(protoregistry.Files), Types: new(protoregistry.Types)}, NewReflectionResolver()}
+
+	method, err := resolvers.FindMethod(context.Background(), conn, "grpc.health.v1.Health/Check")
+	require.NoError(t, err)
+
+	request, err := NewRequest(method, json.RawMessage(`{"service":"overlock"}`))
+	require.NoError(t, err)
+	response, err := Invoke(context.Background(), conn, method, request)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"status":"SERVING"}`, string(response))
+}
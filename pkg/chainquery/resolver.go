@@ -0,0 +1,240 @@
+package chainquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// ErrUnknownMethod is returned when no resolver knows the requested method
+var ErrUnknownMethod = errors.New("unknown method")
+
+// TypeResolver resolves the message types packed in google.protobuf.Any fields
+type TypeResolver interface {
+	protoregistry.MessageTypeResolver
+	protoregistry.ExtensionTypeResolver
+}
+
+// Method is a resolved gRPC method together with the types needed to encode its messages
+type Method struct {
+	Descriptor protoreflect.MethodDescriptor
+	Types      TypeResolver
+}
+
+// Resolver finds the descriptor of a method named <package.Service>/<Method>
+type Resolver interface {
+	FindMethod(ctx context.Context, conn grpc.ClientConnInterface, fullMethod string) (Method, error)
+}
+
+// Resolvers tries each resolver in turn, returning the first method found. When none
+// finds it, a failure other than ErrUnknownMethod (such as an unreachable node) is
+// reported in preference, since the method may exist after all.
+type Resolvers []Resolver
+
+// FindMethod implements Resolver
+func (r Resolvers) FindMethod(ctx context.Context, conn grpc.ClientConnInterface, fullMethod string) (Method, error) {
+	var unknown []error
+	var failure error
+	for _, resolver := range r {
+		method, err := resolver.FindMethod(ctx, conn, fullMethod)
+		switch {
+		case err == nil:
+			return method, nil
+		case errors.Is(err, ErrUnknownMethod):
+			unknown = append(unknown, err)
+		default:
+			failure = err
+		}
+	}
+	if failure != nil {
+		return Method{}, failure
+	}
+	if len(unknown) == 0 {
+		return Method{}, fmt.Errorf("%w '%s'", ErrUnknownMethod, fullMethod)
+	}
+	return Method{}, errors.Join(unknown...)
+}
+
+// RegistryResolver resolves methods from descriptors compiled into the binary
+type RegistryResolver struct {
+	Files *protoregistry.Files
+	Types *protoregistry.Types
+}
+
+// NewRegistryResolver creates a resolver over the global protobuf registry
+func NewRegistryResolver() *RegistryResolver {
+	return &RegistryResolver{Files: protoregistry.GlobalFiles, Types: protoregistry.GlobalTypes}
+}
+
+// FindMethod implements Resolver
+func (r *RegistryResolver) FindMethod(_ context.Context, _ grpc.ClientConnInterface, fullMethod string) (Method, error) {
+	service, name, err := SplitMethod(fullMethod)
+	if err != nil {
+		return Method{}, err
+	}
+	descriptor, err := findMethod(r.Files, service, name)
+	if err != nil {
+		return Method{}, err
+	}
+	return Method{Descriptor: descriptor, Types: r.Types}, nil
+}
+
+// ReflectionResolver resolves methods through the node's gRPC server reflection service.
+// The descriptors of each service are fetched once per connection and cached.
+type ReflectionResolver struct {
+	mu       sync.Mutex
+	services map[reflectionKey]*reflectedService
+}
+
+// reflectionKey identifies a service on one connection
+type reflectionKey struct {
+	conn    grpc.ClientConnInterface
+	service string
+}
+
+// reflectedService holds the descriptors fetched for one service
+type reflectedService struct {
+	files *protoregistry.Files
+	types *dynamicpb.Types
+}
+
+// NewReflectionResolver creates a resolver using gRPC server reflection
+func NewReflectionResolver() *ReflectionResolver {
+	return &ReflectionResolver{services: make(map[reflectionKey]*reflectedService)}
+}
+
+// FindMethod implements Resolver
+func (r *ReflectionResolver) FindMethod(ctx context.Context, conn grpc.ClientConnInterface, fullMethod string) (Method, error) {
+	service, name, err := SplitMethod(fullMethod)
+	if err != nil {
+		return Method{}, err
+	}
+
+	key := reflectionKey{conn: conn, service: service}
+	r.mu.Lock()
+	reflected, ok := r.services[key]
+	r.mu.Unlock()
+	if !ok {
+		files, err := reflectFiles(ctx, conn, service)
+		if err != nil {
+			return Method{}, err
+		}
+		reflected = &reflectedService{files: files, types: dynamicpb.NewTypes(files)}
+		r.mu.Lock()
+		r.services[key] = reflected
+		r.mu.Unlock()
+	}
+
+	descriptor, err := findMethod(reflected.files, service, name)
+	if err != nil {
+		return Method{}, err
+	}
+	return Method{Descriptor: descriptor, Types: reflected.types}, nil
+}
+
+// reflectFiles fetches the file defining service and all of its dependencies
+func reflectFiles(ctx context.Context, conn grpc.ClientConnInterface, service string) (*protoregistry.Files, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("server reflection: %w", err)
+	}
+	defer func() { _ = stream.CloseSend() }()
+
+	fetched := make(map[string]*descriptorpb.FileDescriptorProto)
+	fetch := func(req *rpb.ServerReflectionRequest) error {
+		if err := stream.Send(req); err != nil {
+			return fmt.Errorf("server reflection: %w", err)
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			return fmt.Errorf("server reflection: %w", err)
+		}
+		if reflectErr := resp.GetErrorResponse(); reflectErr != nil {
+			return fmt.Errorf("%w '%s': server reflection: %s", ErrUnknownMethod, service, reflectErr.GetErrorMessage())
+		}
+		for _, raw := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			file := &descriptorpb.FileDescriptorProto{}
+			if err := proto.Unmarshal(raw, file); err != nil {
+				return fmt.Errorf("server reflection: invalid file descriptor: %w", err)
+			}
+			fetched[file.GetName()] = file
+		}
+		return nil
+	}
+
+	err = fetch(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: service},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Servers may send the dependencies along with the file; request the ones they did not
+	for missing := missingDependencies(fetched); len(missing) > 0; missing = missingDependencies(fetched) {
+		for _, name := range missing {
+			// A dependency the server cannot provide is left out; unresolvable imports are tolerated below
+			_ = fetch(&rpb.ServerReflectionRequest{
+				MessageRequest: &rpb.ServerReflectionRequest_FileByFilename{FileByFilename: name},
+			})
+			if _, ok := fetched[name]; !ok {
+				fetched[name] = nil
+			}
+		}
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, file := range fetched {
+		if file != nil {
+			set.File = append(set.File, file)
+		}
+	}
+	files, err := protodesc.FileOptions{AllowUnresolvable: true}.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("server reflection: %w", err)
+	}
+	return files, nil
+}
+
+// missingDependencies lists the imports of the fetched files that were not fetched yet
+func missingDependencies(fetched map[string]*descriptorpb.FileDescriptorProto) []string {
+	var missing []string
+	seen := make(map[string]bool)
+	for _, file := range fetched {
+		for _, dependency := range file.GetDependency() {
+			if _, ok := fetched[dependency]; !ok && !seen[dependency] {
+				seen[dependency] = true
+				missing = append(missing, dependency)
+			}
+		}
+	}
+	return missing
+}
+
+// findMethod looks a method up in a set of files
+func findMethod(files *protoregistry.Files, service, name string) (protoreflect.MethodDescriptor, error) {
+	descriptor, err := files.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, fmt.Errorf("%w '%s/%s': service not found", ErrUnknownMethod, service, name)
+	}
+	serviceDescriptor, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%w '%s/%s': '%s' is not a service", ErrUnknownMethod, service, name, service)
+	}
+	method := serviceDescriptor.Methods().ByName(protoreflect.Name(name))
+	if method == nil {
+		return nil, fmt.Errorf("%w '%s/%s': the service has no such method", ErrUnknownMethod, service, name)
+	}
+	return method, nil
+}
@@ -0,0 +1,175 @@
+package compare
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"overlock-mcp-server/pkg/recommend"
+
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+)
+
+// Sections fields are grouped into
+const (
+	SectionMetadata     = "metadata"
+	SectionAnnotations  = "annotations"
+	SectionLocation     = "location"
+	SectionAvailability = "availability"
+	SectionRegistration = "registration"
+	SectionEnvironments = "environments"
+)
+
+// Field is one field compared across providers
+type Field struct {
+	Section string        `json:"section"`
+	Name    string        `json:"field"`
+	Values  []interface{} `json:"values"` // one value per provider, in the order of Comparison.Providers; null when unset
+	Differs bool          `json:"differs"`
+}
+
+// Comparison is a field-by-field comparison of providers
+type Comparison struct {
+	Providers []uint64 `json:"providers"`
+	Fields    []Field  `json:"fields"`
+	Differing []string `json:"differing"` // names of the fields whose values differ
+}
+
+// Providers compares the providers field by field. hosted holds the number of environments
+// each provider hosts; registration ages are measured at now.
+func Providers(providers []overlockv1beta1.Provider, hosted map[uint64]int, now time.Time) Comparison {
+	comparison := Comparison{
+		Providers: make([]uint64, len(providers)),
+		Fields:    []Field{},
+		Differing: []string{},
+	}
+	for i, provider := range providers {
+		comparison.Providers[i] = provider.Id
+	}
+
+	addValues := func(section, name string, values []interface{}) {
+		field := Field{Section: section, Name: name, Values: values, Differs: differs(values)}
+		comparison.Fields = append(comparison.Fields, field)
+		if field.Differs {
+			comparison.Differing = append(comparison.Differing, name)
+		}
+	}
+	add := func(section, name string, value func(provider overlockv1beta1.Provider) interface{}) {
+		values := make([]interface{}, len(providers))
+		for i, provider := range providers {
+			values[i] = value(provider)
+		}
+		addValues(section, name, values)
+	}
+
+	add(SectionMetadata, "name", func(p overlockv1beta1.Provider) interface{} {
+		if p.Metadata == nil {
+			return nil
+		}
+		return optional(p.Metadata.Name)
+	})
+	add(SectionMetadata, "creator", func(p overlockv1beta1.Provider) interface{} { return optional(p.Creator) })
+
+	annotations := make([]map[string]string, len(providers))
+	keys := make(map[string]bool)
+	var unparsable bool
+	for i, provider := range providers {
+		parsed, err := recommend.ParseAnnotations(provider.Metadata)
+		if err != nil {
+			unparsable = true
+			continue
+		}
+		annotations[i] = parsed
+		for key := range parsed {
+			keys[key] = true
+		}
+	}
+	for _, key := range sortedKeys(keys) {
+		values := make([]interface{}, len(providers))
+		for i := range providers {
+			if value, ok := annotations[i][key]; ok {
+				values[i] = value
+			}
+		}
+		addValues(SectionAnnotations, "annotations."+key, values)
+	}
+	if unparsable {
+		// Keep the raw annotations when some of them are not a JSON object
+		add(SectionAnnotations, "annotations", func(p overlockv1beta1.Provider) interface{} {
+			if p.Metadata == nil {
+				return nil
+			}
+			return optional(p.Metadata.Annotations)
+		})
+	}
+
+	add(SectionLocation, "country_code", func(p overlockv1beta1.Provider) interface{} { return optional(p.CountryCode) })
+	add(SectionLocation, "ip", func(p overlockv1beta1.Provider) interface{} { return optional(p.Ip) })
+	add(SectionLocation, "port", func(p overlockv1beta1.Provider) interface{} {
+		if p.Port == 0 {
+			return nil
+		}
+		return p.Port
+	})
+
+	add(SectionAvailability, "availability", func(p overlockv1beta1.Provider) interface{} { return optional(p.Availability) })
+	add(SectionAvailability, "environment_type", func(p overlockv1beta1.Provider) interface{} { return optional(p.EnvironmentType) })
+
+	add(SectionRegistration, "register_time", func(p overlockv1beta1.Provider) interface{} {
+		if p.RegisterTime == nil {
+			return nil
+		}
+		return p.RegisterTime.UTC().Format(time.RFC3339)
+	})
+	add(SectionRegistration, "registration_age", func(p overlockv1beta1.Provider) interface{} {
+		if p.RegisterTime == nil {
+			return nil
+		}
+		return Age(now.Sub(*p.RegisterTime))
+	})
+
+	add(SectionEnvironments, "hosted_environments", func(p overlockv1beta1.Provider) interface{} { return hosted[p.Id] })
+
+	return comparison
+}
+
+// Age renders a duration in whole days, or in hours below one day
+func Age(d time.Duration) string {
+	switch {
+	case d < 0:
+		return "in the future"
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// optional turns an empty string into a null value
+func optional(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// differs reports whether the values are not all equal
+func differs(values []interface{}) bool {
+	for _, value := range values[min(1, len(values)):] {
+		if !reflect.DeepEqual(values[0], value) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
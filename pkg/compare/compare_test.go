@@ -0,0 +1,76 @@
+package compare
+
+import (
+	"testing"
+	"time"
+
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func field(t *testing.T, comparison Comparison, name string) Field {
+	for _, f := range comparison.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	require.Failf(t, "field not found", "no field %s", name)
+	return Field{}
+}
+
+func TestProviders(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	registeredAt := now.Add(-30 * 24 * time.Hour)
+	providers := []overlockv1beta1.Provider{
+		{
+			Id: 1, Creator: "overlock1abc", CountryCode: "DE", Availability: "available", Ip: "10.0.0.1", Port: 8080,
+			Metadata:     &overlockv1beta1.Metadata{Name: "alpha", Annotations: `{"region":"eu-central-1","gpu":true}`},
+			RegisterTime: &registeredAt,
+		},
+		{
+			Id: 2, Creator: "overlock1abc", CountryCode: "FR", Availability: "available", Ip: "10.0.0.2", Port: 8080,
+			Metadata: &overlockv1beta1.Metadata{Name: "beta", Annotations: `{"region":"eu-west-3"}`},
+		},
+	}
+
+	comparison := Providers(providers, map[uint64]int{1: 3}, now)
+
+	assert.Equal(t, []uint64{1, 2}, comparison.Providers)
+	assert.Equal(t, []string{"name", "annotations.gpu", "annotations.region", "country_code", "ip", "register_time", "registration_age", "hosted_environments"}, comparison.Differing)
+
+	creator := field(t, comparison, "creator")
+	assert.Equal(t, SectionMetadata, creator.Section)
+	assert.False(t, creator.Differs)
+	assert.Equal(t, []interface{}{"overlock1abc", "overlock1abc"}, creator.Values)
+
+	gpu := field(t, comparison, "annotations.gpu")
+	assert.Equal(t, SectionAnnotations, gpu.Section)
+	assert.Equal(t, []interface{}{"true", nil}, gpu.Values)
+
+	assert.False(t, field(t, comparison, "port").Differs)
+	assert.False(t, field(t, comparison, "environment_type").Differs)
+	assert.Equal(t, []interface{}{"30d", nil}, field(t, comparison, "registration_age").Values)
+	assert.Equal(t, []interface{}{"2025-05-02T00:00:00Z", nil}, field(t, comparison, "register_time").Values)
+	assert.Equal(t, []interface{}{3, 0}, field(t, comparison, "hosted_environments").Values)
+}
+
+func TestProviders_UnparsableAnnotations(t *testing.T) {
+	providers := []overlockv1beta1.Provider{
+		{Id: 1, Metadata: &overlockv1beta1.Metadata{Annotations: "region=eu"}},
+		{Id: 2, Metadata: &overlockv1beta1.Metadata{Annotations: `{"region":"eu"}`}},
+	}
+
+	comparison := Providers(providers, nil, time.Now())
+
+	assert.Equal(t, []interface{}{nil, "eu"}, field(t, comparison, "annotations.region").Values)
+	raw := field(t, comparison, "annotations")
+	assert.True(t, raw.Differs)
+	assert.Equal(t, []interface{}{"region=eu", `{"region":"eu"}`}, raw.Values)
+}
+
+func TestAge(t *testing.T) {
+	assert.Equal(t, "5h", Age(5*time.Hour+10*time.Minute))
+	assert.Equal(t, "2d", Age(50*time.Hour))
+	assert.Equal(t, "in the future", Age(-time.Minute))
+}
@@ -4,14 +4,56 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// DefaultNetworkName is the name given to the single network configured through OVERLOCK_GRPC_URL
+const DefaultNetworkName = "default"
+
 // Config holds the application configuration
 type Config struct {
 	// API Configuration
 	OverlockGRPCURL string // gRPC endpoint URL
 	APITimeout      time.Duration
+	StaleMaxAge     time.Duration // how old a last-known-good response may be when the node is unreachable, 0 disables
+
+	// ChainIDCheckInterval is the time between chain-id verifications of networks with an expected chain-id
+	ChainIDCheckInterval time.Duration
+
+	// Network Configuration
+	Networks       map[string]NetworkConfig // named networks, keyed by name
+	DefaultNetwork string                   // network used when a tool call does not name one
+
+	// Index Configuration
+	IndexEnabled  bool          // periodically sync providers and environments into a local index
+	IndexInterval time.Duration // time between index syncs
+	IndexPageSize int           // records requested per page while syncing
+	IndexDir      string        // directory for the persisted index, empty keeps it in memory only
+
+	// History Configuration
+	HistoryRetention time.Duration // how long detected changes are kept, 0 keeps them forever
+	HistoryDir       string        // directory for the persisted change history, defaults to IndexDir or the user cache directory
+
+	// Uptime Configuration
+	UptimeEnabled     bool          // periodically TCP-probe every provider endpoint
+	UptimeInterval    time.Duration // time between probe rounds
+	UptimeTimeout     time.Duration // how long a single probe may take
+	UptimeConcurrency int           // probes running at once
+	UptimeRetention   time.Duration // how long probe samples are kept, 0 keeps them forever
+	UptimeDir         string        // directory for the persisted probe samples, defaults like HistoryDir
+
+	// Probe Configuration
+	ProbePrivateAddresses bool // let TCP probes reach loopback, private and link-local provider endpoints
+
+	// Watch Configuration
+	WatchMinInterval      time.Duration // smallest polling interval a resource subscription can use
+	WatchMaxSubscriptions int           // maximum subscriptions per session
+
+	// Chain Query Configuration
+	ChainQueryAllowlist []string // services and methods the chain-query tool may call, empty uses the built-in read-only list
 
 	// Server Configuration
 	HTTPAddr string
@@ -20,14 +62,39 @@ type Config struct {
 	Debug bool
 }
 
+// NetworkConfig holds the connection settings for a single Overlock network
+type NetworkConfig struct {
+	Name            string
+	GRPCURL         string
+	APITimeout      time.Duration
+	ExpectedChainID string // chain-id the node must report, the network is refused on a mismatch or until it is verified; empty accepts any chain
+
+	// TLS Configuration
+	TLS                   bool
+	TLSServerName         string // overrides the server name used to verify the certificate
+	TLSCAFile             string // PEM bundle used instead of the system roots
+	TLSInsecureSkipVerify bool
+}
+
 // LoadConfig loads configuration from environment variables
 func LoadConfig() (*Config, error) {
 	config := &Config{
 		// Default values
-		OverlockGRPCURL: "localhost:9090", // gRPC endpoint
-		APITimeout:      30 * time.Second,
-		HTTPAddr:        "127.0.0.1:8080",
-		Debug:           false,
+		OverlockGRPCURL:       "localhost:9090", // gRPC endpoint
+		APITimeout:            30 * time.Second,
+		StaleMaxAge:           10 * time.Minute,
+		ChainIDCheckInterval:  30 * time.Second,
+		IndexInterval:         5 * time.Minute,
+		IndexPageSize:         100,
+		HistoryRetention:      90 * 24 * time.Hour,
+		UptimeInterval:        time.Minute,
+		UptimeTimeout:         3 * time.Second,
+		UptimeConcurrency:     16,
+		UptimeRetention:       30 * 24 * time.Hour,
+		WatchMinInterval:      10 * time.Second,
+		WatchMaxSubscriptions: 50,
+		HTTPAddr:              "127.0.0.1:8080",
+		Debug:                 false,
 	}
 
 	// Load from environment variables
@@ -43,6 +110,130 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	if maxAge := os.Getenv("OVERLOCK_STALE_MAX_AGE"); maxAge != "" {
+		if d, err := time.ParseDuration(maxAge); err == nil {
+			config.StaleMaxAge = d
+		} else {
+			log.Printf("Warning: Invalid OVERLOCK_STALE_MAX_AGE '%s', using default %v: %v", maxAge, config.StaleMaxAge, err)
+		}
+	}
+
+	if interval := os.Getenv("OVERLOCK_CHAIN_ID_CHECK_INTERVAL"); interval != "" {
+		if d, err := time.ParseDuration(interval); err == nil {
+			config.ChainIDCheckInterval = d
+		} else {
+			log.Printf("Warning: Invalid OVERLOCK_CHAIN_ID_CHECK_INTERVAL '%s', using default %v: %v", interval, config.ChainIDCheckInterval, err)
+		}
+	}
+
+	if enabled := os.Getenv("OVERLOCK_INDEX_ENABLED"); enabled == "true" {
+		config.IndexEnabled = true
+	}
+
+	if interval := os.Getenv("OVERLOCK_INDEX_INTERVAL"); interval != "" {
+		if d, err := time.ParseDuration(interval); err == nil {
+			config.IndexInterval = d
+		} else {
+			log.Printf("Warning: Invalid OVERLOCK_INDEX_INTERVAL '%s', using default %v: %v", interval, config.IndexInterval, err)
+		}
+	}
+
+	if pageSize := os.Getenv("OVERLOCK_INDEX_PAGE_SIZE"); pageSize != "" {
+		if n, err := strconv.Atoi(pageSize); err == nil {
+			config.IndexPageSize = n
+		} else {
+			log.Printf("Warning: Invalid OVERLOCK_INDEX_PAGE_SIZE '%s', using default %d: %v", pageSize, config.IndexPageSize, err)
+		}
+	}
+
+	if dir := os.Getenv("OVERLOCK_INDEX_DIR"); dir != "" {
+		config.IndexDir = dir
+	}
+
+	if retention := os.Getenv("OVERLOCK_HISTORY_RETENTION"); retention != "" {
+		if d, err := time.ParseDuration(retention); err == nil {
+			config.HistoryRetention = d
+		} else {
+			log.Printf("Warning: Invalid OVERLOCK_HISTORY_RETENTION '%s', using default %v: %v", retention, config.HistoryRetention, err)
+		}
+	}
+
+	// Change history and uptime samples are persisted next to the index, or in the user cache directory
+	defaultDir := config.IndexDir
+	if defaultDir == "" {
+		if cacheDir, err := os.UserCacheDir(); err == nil {
+			defaultDir = filepath.Join(cacheDir, "overlock-mcp-server")
+		}
+	}
+
+	config.HistoryDir = defaultDir
+	if dir := os.Getenv("OVERLOCK_HISTORY_DIR"); dir != "" {
+		config.HistoryDir = dir
+	}
+
+	config.UptimeDir = defaultDir
+	if dir := os.Getenv("OVERLOCK_UPTIME_DIR"); dir != "" {
+		config.UptimeDir = dir
+	}
+
+	if enabled := os.Getenv("OVERLOCK_UPTIME_ENABLED"); enabled == "true" {
+		config.UptimeEnabled = true
+	}
+
+	if interval := os.Getenv("OVERLOCK_UPTIME_INTERVAL"); interval != "" {
+		if d, err := time.ParseDuration(interval); err == nil {
+			config.UptimeInterval = d
+		} else {
+			log.Printf("Warning: Invalid OVERLOCK_UPTIME_INTERVAL '%s', using default %v: %v", interval, config.UptimeInterval, err)
+		}
+	}
+
+	if timeout := os.Getenv("OVERLOCK_UPTIME_TIMEOUT"); timeout != "" {
+		if d, err := time.ParseDuration(timeout); err == nil {
+			config.UptimeTimeout = d
+		} else {
+			log.Printf("Warning: Invalid OVERLOCK_UPTIME_TIMEOUT '%s', using default %v: %v", timeout, config.UptimeTimeout, err)
+		}
+	}
+
+	if concurrency := os.Getenv("OVERLOCK_UPTIME_CONCURRENCY"); concurrency != "" {
+		if n, err := strconv.Atoi(concurrency); err == nil {
+			config.UptimeConcurrency = n
+		} else {
+			log.Printf("Warning: Invalid OVERLOCK_UPTIME_CONCURRENCY '%s', using default %d: %v", concurrency, config.UptimeConcurrency, err)
+		}
+	}
+
+	if retention := os.Getenv("OVERLOCK_UPTIME_RETENTION"); retention != "" {
+		if d, err := time.ParseDuration(retention); err == nil {
+			config.UptimeRetention = d
+		} else {
+			log.Printf("Warning: Invalid OVERLOCK_UPTIME_RETENTION '%s', using default %v: %v", retention, config.UptimeRetention, err)
+		}
+	}
+
+	if enabled := os.Getenv("OVERLOCK_PROBE_PRIVATE_ADDRESSES"); enabled == "true" {
+		config.ProbePrivateAddresses = true
+	}
+
+	if interval := os.Getenv("OVERLOCK_WATCH_MIN_INTERVAL"); interval != "" {
+		if d, err := time.ParseDuration(interval); err == nil {
+			config.WatchMinInterval = d
+		} else {
+			log.Printf("Warning: Invalid OVERLOCK_WATCH_MIN_INTERVAL '%s', using default %v: %v", interval, config.WatchMinInterval, err)
+		}
+	}
+
+	if maxSubscriptions := os.Getenv("OVERLOCK_WATCH_MAX_SUBSCRIPTIONS"); maxSubscriptions != "" {
+		if n, err := strconv.Atoi(maxSubscriptions); err == nil {
+			config.WatchMaxSubscriptions = n
+		} else {
+			log.Printf("Warning: Invalid OVERLOCK_WATCH_MAX_SUBSCRIPTIONS '%s', using default %d: %v", maxSubscriptions, config.WatchMaxSubscriptions, err)
+		}
+	}
+
+	config.ChainQueryAllowlist = splitList(os.Getenv("OVERLOCK_CHAIN_QUERY_ALLOWLIST"))
+
 	if addr := os.Getenv("MCP_HTTP_ADDR"); addr != "" {
 		config.HTTPAddr = addr
 	}
@@ -51,6 +242,8 @@ func LoadConfig() (*Config, error) {
 		config.Debug = true
 	}
 
+	config.loadNetworks()
+
 	// Validate required configuration
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("configuration validation failed: %w", err)
@@ -59,6 +252,55 @@ func LoadConfig() (*Config, error) {
 	return config, nil
 }
 
+// loadNetworks reads the named networks from OVERLOCK_NETWORKS.
+// Each name in the comma separated list is configured through variables
+// prefixed with OVERLOCK_NETWORK_<NAME>_, e.g. OVERLOCK_NETWORK_TESTNET_GRPC_URL.
+// Without OVERLOCK_NETWORKS a single network named "default" is built from
+// OVERLOCK_GRPC_URL and OVERLOCK_API_TIMEOUT.
+func (c *Config) loadNetworks() {
+	c.Networks = make(map[string]NetworkConfig)
+
+	names := splitList(os.Getenv("OVERLOCK_NETWORKS"))
+	if len(names) == 0 {
+		c.Networks[DefaultNetworkName] = NetworkConfig{
+			Name:            DefaultNetworkName,
+			GRPCURL:         c.OverlockGRPCURL,
+			APITimeout:      c.APITimeout,
+			ExpectedChainID: os.Getenv("OVERLOCK_EXPECTED_CHAIN_ID"),
+			TLS:             os.Getenv("OVERLOCK_GRPC_TLS") == "true",
+		}
+		c.DefaultNetwork = DefaultNetworkName
+		return
+	}
+
+	for _, name := range names {
+		prefix := "OVERLOCK_NETWORK_" + envName(name) + "_"
+		network := NetworkConfig{
+			Name:                  name,
+			GRPCURL:               os.Getenv(prefix + "GRPC_URL"),
+			APITimeout:            c.APITimeout,
+			ExpectedChainID:       os.Getenv(prefix + "EXPECTED_CHAIN_ID"),
+			TLS:                   os.Getenv(prefix+"TLS") == "true",
+			TLSServerName:         os.Getenv(prefix + "TLS_SERVER_NAME"),
+			TLSCAFile:             os.Getenv(prefix + "TLS_CA_FILE"),
+			TLSInsecureSkipVerify: os.Getenv(prefix+"TLS_INSECURE_SKIP_VERIFY") == "true",
+		}
+		if timeout := os.Getenv(prefix + "API_TIMEOUT"); timeout != "" {
+			if d, err := time.ParseDuration(timeout); err == nil {
+				network.APITimeout = d
+			} else {
+				log.Printf("Warning: Invalid %sAPI_TIMEOUT '%s', using default %v: %v", prefix, timeout, network.APITimeout, err)
+			}
+		}
+		c.Networks[name] = network
+	}
+
+	c.DefaultNetwork = names[0]
+	if name := os.Getenv("OVERLOCK_DEFAULT_NETWORK"); name != "" {
+		c.DefaultNetwork = name
+	}
+}
+
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
 	if c.OverlockGRPCURL == "" {
@@ -70,5 +312,67 @@ func (c *Config) Validate() error {
 	if c.APITimeout <= 0 {
 		return fmt.Errorf("OVERLOCK_API_TIMEOUT must be positive")
 	}
+	if c.StaleMaxAge < 0 {
+		return fmt.Errorf("OVERLOCK_STALE_MAX_AGE must not be negative")
+	}
+	if c.ChainIDCheckInterval <= 0 {
+		return fmt.Errorf("OVERLOCK_CHAIN_ID_CHECK_INTERVAL must be positive")
+	}
+	if c.IndexEnabled && c.IndexInterval <= 0 {
+		return fmt.Errorf("OVERLOCK_INDEX_INTERVAL must be positive")
+	}
+	if c.IndexEnabled && (c.IndexPageSize <= 0 || c.IndexPageSize > 1000) {
+		return fmt.Errorf("OVERLOCK_INDEX_PAGE_SIZE must be between 1 and 1000")
+	}
+	if c.HistoryRetention < 0 {
+		return fmt.Errorf("OVERLOCK_HISTORY_RETENTION must not be negative")
+	}
+	if c.UptimeEnabled && c.UptimeInterval <= 0 {
+		return fmt.Errorf("OVERLOCK_UPTIME_INTERVAL must be positive")
+	}
+	if c.UptimeEnabled && c.UptimeTimeout <= 0 {
+		return fmt.Errorf("OVERLOCK_UPTIME_TIMEOUT must be positive")
+	}
+	if c.UptimeEnabled && c.UptimeConcurrency <= 0 {
+		return fmt.Errorf("OVERLOCK_UPTIME_CONCURRENCY must be positive")
+	}
+	if c.UptimeRetention < 0 {
+		return fmt.Errorf("OVERLOCK_UPTIME_RETENTION must not be negative")
+	}
+	if c.WatchMinInterval <= 0 {
+		return fmt.Errorf("OVERLOCK_WATCH_MIN_INTERVAL must be positive")
+	}
+	if c.WatchMaxSubscriptions <= 0 {
+		return fmt.Errorf("OVERLOCK_WATCH_MAX_SUBSCRIPTIONS must be positive")
+	}
+	for name, network := range c.Networks {
+		if network.GRPCURL == "" {
+			return fmt.Errorf("OVERLOCK_NETWORK_%s_GRPC_URL is required", envName(name))
+		}
+		if network.APITimeout <= 0 {
+			return fmt.Errorf("OVERLOCK_NETWORK_%s_API_TIMEOUT must be positive", envName(name))
+		}
+	}
+	if len(c.Networks) > 0 {
+		if _, ok := c.Networks[c.DefaultNetwork]; !ok {
+			return fmt.Errorf("OVERLOCK_DEFAULT_NETWORK '%s' is not listed in OVERLOCK_NETWORKS", c.DefaultNetwork)
+		}
+	}
 	return nil
 }
+
+// splitList splits a comma separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// envName converts a network name into its environment variable form
+func envName(name string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+}
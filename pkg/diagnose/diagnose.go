@@ -0,0 +1,242 @@
+package diagnose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"overlock-mcp-server/pkg/audit"
+	"overlock-mcp-server/pkg/recommend"
+
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+)
+
+// Status is the outcome of a check
+type Status string
+
+// Check outcomes, from best to worst. Skip marks a check that did not run, because an
+// earlier one failed or probing the address is disabled.
+const (
+	StatusPass Status = "pass"
+	StatusSkip Status = "skip"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Check names, in the order they run
+const (
+	CheckEnvironment  = "environment_exists"
+	CheckName         = "environment_name"
+	CheckAnnotations  = "environment_annotations"
+	CheckProvider     = "provider_exists"
+	CheckAvailability = "provider_availability"
+	CheckEndpoint     = "provider_endpoint"
+	CheckReachability = "provider_reachable"
+)
+
+// Check is one item of the checklist
+type Check struct {
+	Name    string `json:"check"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+}
+
+// Report is the checklist of an environment diagnosis
+type Report struct {
+	EnvironmentId uint64                       `json:"environment_id"`
+	Status        Status                       `json:"status"` // worst status of any check
+	Summary       string                       `json:"summary"`
+	Checks        []Check                      `json:"checks"`
+	Environment   *overlockv1beta1.Environment `json:"environment,omitempty"`
+	Provider      *overlockv1beta1.Provider    `json:"provider,omitempty"`
+}
+
+// Prober opens a TCP connection to address and reports how long it took
+type Prober func(ctx context.Context, address string) (time.Duration, error)
+
+// ErrNonPublicAddress is returned by a public prober for endpoints that resolve to a
+// loopback, private, link-local or unspecified address
+var ErrNonPublicAddress = errors.New("address is not publicly routable")
+
+// TCPProber returns a prober that gives up after timeout
+func TCPProber(timeout time.Duration) Prober {
+	return dialProber(timeout, nil)
+}
+
+// PublicTCPProber returns a prober that gives up after timeout and refuses to connect to
+// non-public addresses, so on-chain endpoints cannot be used to probe the server's own network.
+// The check runs on the resolved address, so hostnames pointing at such addresses are refused too.
+func PublicTCPProber(timeout time.Duration) Prober {
+	return dialProber(timeout, func(_, address string, _ syscall.RawConn) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+		if kind := audit.NonPublicKind(host); kind != "" {
+			return fmt.Errorf("%w: %s is a %s address", ErrNonPublicAddress, host, kind)
+		}
+		return nil
+	})
+}
+
+// dialProber returns a prober dialing with the given control function, which runs before connecting
+func dialProber(timeout time.Duration, control func(network, address string, c syscall.RawConn) error) Prober {
+	return func(ctx context.Context, address string) (time.Duration, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		start := time.Now()
+		dialer := net.Dialer{Control: control}
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return 0, err
+		}
+		conn.Close()
+		return time.Since(start), nil
+	}
+}
+
+// Lookup is the outcome of reading a record: found, missing (nil record, nil error) or failed
+type Lookup[T any] struct {
+	Record *T
+	Err    error
+}
+
+// Environment runs the checklist for an environment. provider is only read when the
+// environment was found; probe is only called when the provider has a usable endpoint, and a
+// probe refusing a non-public address skips the reachability check.
+func Environment(ctx context.Context, id uint64, environment Lookup[overlockv1beta1.Environment], provider func() Lookup[overlockv1beta1.Provider], probe Prober) Report {
+	report := Report{EnvironmentId: id, Checks: []Check{}}
+	add := func(name string, status Status, format string, args ...interface{}) {
+		report.Checks = append(report.Checks, Check{Name: name, Status: status, Message: fmt.Sprintf(format, args...)})
+	}
+	skipRest := func(reason string, names ...string) {
+		for _, name := range names {
+			add(name, StatusSkip, "%s", reason)
+		}
+	}
+
+	switch {
+	case environment.Err != nil:
+		add(CheckEnvironment, StatusFail, "Environment %d could not be read: %v", id, environment.Err)
+		skipRest("Skipped because the environment could not be read", CheckName, CheckAnnotations, CheckProvider, CheckAvailability, CheckEndpoint, CheckReachability)
+		return report.finish()
+	case environment.Record == nil:
+		add(CheckEnvironment, StatusFail, "Environment %d does not exist", id)
+		skipRest("Skipped because the environment does not exist", CheckName, CheckAnnotations, CheckProvider, CheckAvailability, CheckEndpoint, CheckReachability)
+		return report.finish()
+	}
+	env := environment.Record
+	report.Environment = env
+	add(CheckEnvironment, StatusPass, "Environment %d exists", id)
+
+	if env.Metadata == nil || strings.TrimSpace(env.Metadata.Name) == "" {
+		add(CheckName, StatusWarn, "Environment has no metadata name")
+	} else {
+		add(CheckName, StatusPass, "Environment is named '%s'", env.Metadata.Name)
+	}
+
+	if annotations, err := recommend.ParseAnnotations(env.Metadata); err != nil {
+		add(CheckAnnotations, StatusFail, "Annotations are not a valid JSON object: %v", err)
+	} else {
+		add(CheckAnnotations, StatusPass, "Annotations are valid (%d keys)", len(annotations))
+	}
+
+	resolved := provider()
+	switch {
+	case resolved.Err != nil:
+		add(CheckProvider, StatusFail, "Provider %d could not be read: %v", env.Provider, resolved.Err)
+		skipRest("Skipped because the provider could not be read", CheckAvailability, CheckEndpoint, CheckReachability)
+		return report.finish()
+	case resolved.Record == nil:
+		add(CheckProvider, StatusFail, "Provider %d referenced by this environment does not exist", env.Provider)
+		skipRest("Skipped because the provider does not exist", CheckAvailability, CheckEndpoint, CheckReachability)
+		return report.finish()
+	}
+	p := resolved.Record
+	report.Provider = p
+	add(CheckProvider, StatusPass, "Provider %d exists", p.Id)
+
+	switch p.Availability {
+	case recommend.Available:
+		add(CheckAvailability, StatusPass, "Provider reports itself available")
+	case "":
+		add(CheckAvailability, StatusWarn, "Provider does not report its availability")
+	default:
+		add(CheckAvailability, StatusFail, "Provider reports availability '%s'", p.Availability)
+	}
+
+	switch {
+	case p.Ip == "" || p.Port == 0:
+		add(CheckEndpoint, StatusFail, "Provider has no usable endpoint (ip '%s', port %d)", p.Ip, p.Port)
+		skipRest("Skipped because the provider has no usable endpoint", CheckReachability)
+		return report.finish()
+	case audit.NonPublicKind(p.Ip) != "":
+		add(CheckEndpoint, StatusWarn, "Provider IP %s is a %s address and may not be reachable from outside its network", p.Ip, audit.NonPublicKind(p.Ip))
+	default:
+		add(CheckEndpoint, StatusPass, "Provider endpoint is %s", net.JoinHostPort(p.Ip, fmt.Sprint(p.Port)))
+	}
+
+	address := net.JoinHostPort(p.Ip, fmt.Sprint(p.Port))
+	if elapsed, err := probe(ctx, address); errors.Is(err, ErrNonPublicAddress) {
+		add(CheckReachability, StatusSkip, "Skipped because probing non-public addresses is disabled (%v)", err)
+	} else if err != nil {
+		add(CheckReachability, StatusFail, "TCP connection to %s failed: %v", address, err)
+	} else {
+		add(CheckReachability, StatusPass, "TCP connection to %s succeeded in %s", address, elapsed.Round(time.Millisecond))
+	}
+	return report.finish()
+}
+
+// finish sets the overall status and the plain-language summary
+func (r Report) finish() Report {
+	r.Status = StatusPass
+	var problems, skipped []string
+	reachable := false
+	for _, check := range r.Checks {
+		if rank(check.Status) > rank(r.Status) {
+			r.Status = check.Status
+		}
+		switch check.Status {
+		case StatusWarn, StatusFail:
+			problems = append(problems, check.Message)
+		case StatusSkip:
+			skipped = append(skipped, check.Name)
+		case StatusPass:
+			reachable = reachable || check.Name == CheckReachability
+		}
+	}
+
+	// Only a successful probe shows the provider accepts connections; checks skipped
+	// because of an earlier failure are explained by that failure
+	switch {
+	case len(problems) == 0 && reachable:
+		r.Summary = fmt.Sprintf("Environment %d looks healthy: its provider %d is available and accepts TCP connections.", r.EnvironmentId, r.Provider.Id)
+	case len(problems) == 0:
+		r.Summary = fmt.Sprintf("Environment %d was not fully checked. Not checked: %s.", r.EnvironmentId, strings.Join(skipped, ", "))
+	case r.Status == StatusWarn && reachable:
+		r.Summary = fmt.Sprintf("Environment %d works but needs attention. %s.", r.EnvironmentId, strings.Join(problems, ". "))
+	case r.Status == StatusWarn:
+		r.Summary = fmt.Sprintf("Environment %d needs attention. %s. Not checked: %s.", r.EnvironmentId, strings.Join(problems, ". "), strings.Join(skipped, ", "))
+	default:
+		r.Summary = fmt.Sprintf("Environment %d has problems. %s.", r.EnvironmentId, strings.Join(problems, ". "))
+	}
+	return r
+}
+
+// rank orders statuses from best to worst
+func rank(status Status) int {
+	switch status {
+	case StatusFail:
+		return 3
+	case StatusWarn:
+		return 2
+	case StatusSkip:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,166 @@
+package diagnose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func reachable(ctx context.Context, address string) (time.Duration, error) {
+	return 12 * time.Millisecond, nil
+}
+
+func statuses(report Report) map[string]Status {
+	result := make(map[string]Status)
+	for _, check := range report.Checks {
+		result[check.Name] = check.Status
+	}
+	return result
+}
+
+func providerLookup(provider *overlockv1beta1.Provider, err error) func() Lookup[overlockv1beta1.Provider] {
+	return func() Lookup[overlockv1beta1.Provider] {
+		return Lookup[overlockv1beta1.Provider]{Record: provider, Err: err}
+	}
+}
+
+func TestEnvironment_Healthy(t *testing.T) {
+	environment := &overlockv1beta1.Environment{Id: 7, Provider: 3, Metadata: &overlockv1beta1.Metadata{Name: "web", Annotations: `{"tier":"gold"}`}}
+	provider := &overlockv1beta1.Provider{Id: 3, Availability: "available", Ip: "203.0.113.10", Port: 8080}
+
+	var probed string
+	report := Environment(context.Background(), 7, Lookup[overlockv1beta1.Environment]{Record: environment}, providerLookup(provider, nil), func(ctx context.Context, address string) (time.Duration, error) {
+		probed = address
+		return reachable(ctx, address)
+	})
+
+	assert.Equal(t, StatusPass, report.Status)
+	assert.Equal(t, "203.0.113.10:8080", probed)
+	require.Len(t, report.Checks, 7)
+	for _, check := range report.Checks {
+		assert.Equal(t, StatusPass, check.Status, check.Name)
+	}
+	assert.Equal(t, "Environment 7 looks healthy: its provider 3 is available and accepts TCP connections.", report.Summary)
+	assert.Equal(t, provider, report.Provider)
+}
+
+func TestEnvironment_Problems(t *testing.T) {
+	environment := &overlockv1beta1.Environment{Id: 7, Provider: 3, Metadata: &overlockv1beta1.Metadata{Annotations: "tier=gold"}}
+	provider := &overlockv1beta1.Provider{Id: 3, Availability: "maintenance", Ip: "10.0.0.5", Port: 8080}
+
+	report := Environment(context.Background(), 7, Lookup[overlockv1beta1.Environment]{Record: environment}, providerLookup(provider, nil), func(ctx context.Context, address string) (time.Duration, error) {
+		return 0, errors.New("i/o timeout")
+	})
+
+	assert.Equal(t, StatusFail, report.Status)
+	assert.Equal(t, map[string]Status{
+		CheckEnvironment:  StatusPass,
+		CheckName:         StatusWarn,
+		CheckAnnotations:  StatusFail,
+		CheckProvider:     StatusPass,
+		CheckAvailability: StatusFail,
+		CheckEndpoint:     StatusWarn,
+		CheckReachability: StatusFail,
+	}, statuses(report))
+	assert.Contains(t, report.Summary, "Environment 7 has problems.")
+	assert.Contains(t, report.Summary, "Provider reports availability 'maintenance'")
+	assert.Contains(t, report.Summary, "TCP connection to 10.0.0.5:8080 failed: i/o timeout")
+}
+
+func TestEnvironment_MissingRecords(t *testing.T) {
+	report := Environment(context.Background(), 7, Lookup[overlockv1beta1.Environment]{}, nil, nil)
+	assert.Equal(t, StatusFail, report.Status)
+	assert.Equal(t, StatusFail, statuses(report)[CheckEnvironment])
+	assert.Equal(t, StatusSkip, statuses(report)[CheckReachability])
+	assert.Equal(t, "Environment 7 has problems. Environment 7 does not exist.", report.Summary)
+
+	environment := &overlockv1beta1.Environment{Id: 7, Provider: 3, Metadata: &overlockv1beta1.Metadata{Name: "web"}}
+	report = Environment(context.Background(), 7, Lookup[overlockv1beta1.Environment]{Record: environment}, providerLookup(nil, nil), nil)
+	assert.Equal(t, StatusFail, statuses(report)[CheckProvider])
+	assert.Equal(t, StatusSkip, statuses(report)[CheckAvailability])
+	assert.Contains(t, report.Summary, "Provider 3 referenced by this environment does not exist")
+
+	report = Environment(context.Background(), 7, Lookup[overlockv1beta1.Environment]{Record: environment}, providerLookup(&overlockv1beta1.Provider{Id: 3, Availability: "available"}, nil), nil)
+	assert.Equal(t, StatusFail, statuses(report)[CheckEndpoint])
+	assert.Equal(t, StatusSkip, statuses(report)[CheckReachability])
+}
+
+func TestTCPProber(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			conn.Close()
+		}
+	}()
+
+	_, err = TCPProber(time.Second)(context.Background(), address)
+	assert.NoError(t, err)
+
+	listener.Close()
+	_, err = TCPProber(time.Second)(context.Background(), address)
+	assert.Error(t, err)
+}
+
+func TestPublicTCPProber(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	accepted := make(chan struct{}, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			accepted <- struct{}{}
+			conn.Close()
+		}
+	}()
+
+	for _, address := range []string{listener.Addr().String(), "10.0.0.5:8080", "169.254.169.254:80", "localhost:" + fmt.Sprint(listener.Addr().(*net.TCPAddr).Port)} {
+		_, err = PublicTCPProber(time.Second)(context.Background(), address)
+		assert.ErrorIs(t, err, ErrNonPublicAddress, address)
+	}
+	select {
+	case <-accepted:
+		t.Fatal("public prober connected to a loopback address")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestEnvironment_NonPublicEndpointNotProbed(t *testing.T) {
+	environment := &overlockv1beta1.Environment{Id: 7, Provider: 3, Metadata: &overlockv1beta1.Metadata{Name: "web"}}
+	provider := &overlockv1beta1.Provider{Id: 3, Availability: "available", Ip: "169.254.169.254", Port: 80}
+
+	report := Environment(context.Background(), 7, Lookup[overlockv1beta1.Environment]{Record: environment}, providerLookup(provider, nil), PublicTCPProber(time.Second))
+
+	assert.Equal(t, StatusWarn, report.Status)
+	assert.Equal(t, StatusWarn, statuses(report)[CheckEndpoint])
+	assert.Equal(t, StatusSkip, statuses(report)[CheckReachability])
+	assert.Contains(t, report.Checks[len(report.Checks)-1].Message, "probing non-public addresses is disabled")
+	assert.Contains(t, report.Summary, "Environment 7 needs attention.")
+	assert.Contains(t, report.Summary, "Not checked: provider_reachable.")
+}
+
+func TestEnvironment_HostnameResolvingToPrivateAddressNotHealthy(t *testing.T) {
+	environment := &overlockv1beta1.Environment{Id: 7, Provider: 3, Metadata: &overlockv1beta1.Metadata{Name: "web"}}
+	provider := &overlockv1beta1.Provider{Id: 3, Availability: "available", Ip: "provider.example.com", Port: 8080}
+
+	// The endpoint check passes on the hostname, the probe is refused once it resolves
+	report := Environment(context.Background(), 7, Lookup[overlockv1beta1.Environment]{Record: environment}, providerLookup(provider, nil), func(ctx context.Context, address string) (time.Duration, error) {
+		return 0, fmt.Errorf("%w: 10.0.0.5 is a private address", ErrNonPublicAddress)
+	})
+
+	assert.Equal(t, StatusSkip, report.Status)
+	assert.Equal(t, StatusPass, statuses(report)[CheckEndpoint])
+	assert.Equal(t, StatusSkip, statuses(report)[CheckReachability])
+	assert.Equal(t, "Environment 7 was not fully checked. Not checked: provider_reachable.", report.Summary)
+	assert.NotContains(t, report.Summary, "accepts TCP connections")
+}
@@ -0,0 +1,330 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"overlock-mcp-server/pkg/network"
+	"overlock-mcp-server/pkg/render"
+
+	"github.com/Oudwins/zog"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// accountMaxBalancePages bounds the number of balance pages read for a single account
+const accountMaxBalancePages = 10
+
+// AccountInput represents the input parameters for the show-account tool
+type AccountInput struct {
+	Address string `json:"address,omitempty"`
+	Network string `json:"network,omitempty"`
+	Height  int64  `json:"height,omitempty"`
+	Format  string `json:"format,omitempty"`
+}
+
+// DenomUnit is one unit a denomination can be displayed in
+type DenomUnit struct {
+	Denom    string   `json:"denom"`
+	Exponent uint32   `json:"exponent"`
+	Aliases  []string `json:"aliases,omitempty"`
+}
+
+// DenomMetadata describes a denomination as registered with the bank module
+type DenomMetadata struct {
+	Name        string      `json:"name,omitempty"`
+	Symbol      string      `json:"symbol,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Display     string      `json:"display,omitempty"`
+	DenomUnits  []DenomUnit `json:"denom_units,omitempty"`
+}
+
+// AccountBalance is the amount an account holds of one denomination. The display amount
+// is only set when the denomination has metadata naming its display unit.
+type AccountBalance struct {
+	Denom         string         `json:"denom"`
+	Amount        string         `json:"amount"`
+	DisplayAmount string         `json:"display_amount,omitempty"`
+	DisplayDenom  string         `json:"display_denom,omitempty"`
+	Metadata      *DenomMetadata `json:"metadata,omitempty"`
+}
+
+// AccountResponse is the response of the show-account tool. Addresses that never
+// received funds have no account on chain and are reported with exists false.
+type AccountResponse struct {
+	Address       string           `json:"address"`
+	Exists        bool             `json:"exists"`
+	Type          string           `json:"type,omitempty"`
+	AccountNumber uint64           `json:"account_number"`
+	Sequence      uint64           `json:"sequence"`
+	ModuleName    string           `json:"module_name,omitempty"`
+	Balances      []AccountBalance `json:"balances"`
+	Truncated     bool             `json:"truncated,omitempty"`
+}
+
+// AccountHandler handles the show-account tool requests
+type AccountHandler struct {
+	chainBackend
+	interfaces codectypes.InterfaceRegistry
+}
+
+// NewAccountHandler creates an account handler querying the auth and bank modules over conn
+func NewAccountHandler(conn grpc.ClientConnInterface, timeout time.Duration) *AccountHandler {
+	backend := newChainBackend("blockchain-client-account", nil, timeout)
+	backend.conn = conn
+	return &AccountHandler{
+		chainBackend: backend,
+		interfaces:   newAccountInterfaceRegistry(),
+	}
+}
+
+// NewAccountHandlerForNetworks creates an account handler serving every configured network
+func NewAccountHandlerForNetworks(networks *network.Registry) *AccountHandler {
+	return &AccountHandler{
+		chainBackend: newChainBackendForNetworks("blockchain-client-account", networks),
+		interfaces:   newAccountInterfaceRegistry(),
+	}
+}
+
+// newAccountInterfaceRegistry registers the account types the auth module can return
+func newAccountInterfaceRegistry() codectypes.InterfaceRegistry {
+	registry := codectypes.NewInterfaceRegistry()
+	authtypes.RegisterInterfaces(registry)
+	vestingtypes.RegisterInterfaces(registry)
+	return registry
+}
+
+// Handle processes the show-account tool call
+func (h *AccountHandler) Handle(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	// Create a logger with request context
+	logger := log.With().
+		Str("tool", "show-account").
+		Str("request_id", fmt.Sprintf("%p", params)).
+		Logger()
+
+	start := time.Now()
+	logger.Info().Msg("Processing show-account request")
+
+	// Define validation schema using Zog
+	schema := zog.Struct(zog.Shape{
+		"address": zog.String().Trim().Required(),
+		"network": zog.String().Default(""),
+		"height":  zog.Int64().GTE(0).Default(0),
+		"format":  zog.String().OneOf(render.RecordFormats).Default(render.FormatJSON),
+	})
+
+	// Validate input parameters
+	var input AccountInput
+	arguments := params.Arguments
+	if arguments == nil {
+		arguments = make(map[string]interface{})
+	}
+
+	logger.Debug().Interface("arguments", arguments).Msg("Validating input arguments")
+	// Parse and validate the arguments
+	errs := schema.Parse(arguments, &input)
+	if errs != nil {
+		logger.Error().Interface("errors", errs).Msg("Input validation failed")
+		return invalidInput(errs), nil
+	}
+
+	if err := ValidateAddress(input.Address); err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("address", "bech32_address", input.Address, err.Error()), nil
+	}
+
+	target, err := h.resolve(input.Network)
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("network", "known network", input.Network, err.Error()), nil
+	}
+
+	logger.Debug().Interface("parsed_input", input).Msg("Input validation successful")
+
+	// Check if the gRPC connection is available
+	if target.conn == nil {
+		logger.Error().Msg("gRPC connection is not available")
+		return target.unavailableResult(), nil
+	}
+
+	logger.Info().
+		Str("network", target.network).
+		Int64("height", input.Height).
+		Str("address", input.Address).
+		Msg("Fetching account from blockchain")
+
+	response := &AccountResponse{Address: input.Address, Balances: []AccountBalance{}}
+
+	// Fetch the account using circuit breaker protection
+	result, info, err := target.invoke(ctx, input.Height, func(ctx context.Context, conn grpc.ClientConnInterface, opts ...grpc.CallOption) (interface{}, error) {
+		return authtypes.NewQueryClient(conn).Account(ctx, &authtypes.QueryAccountRequest{Address: input.Address}, opts...)
+	})
+	if err != nil && status.Code(err) != codes.NotFound {
+		return target.failureResult(logger, err), nil
+	}
+	if err == nil {
+		accountResponse, ok := result.(*authtypes.QueryAccountResponse)
+		if !ok || accountResponse == nil || accountResponse.Account == nil {
+			return target.invalidResponseResult(logger), nil
+		}
+		if err := h.describeAccount(accountResponse.Account, response); err != nil {
+			logger.Error().Err(err).Msg("Failed to decode account")
+			return target.invalidResponseResult(logger), nil
+		}
+	}
+
+	// Read the balances at the height the account was read at, so both describe the same block
+	height := info.blockHeight
+	balances, truncated, err := h.balances(ctx, target, input.Address, height)
+	if err != nil {
+		return target.failureResult(logger, err), nil
+	}
+	response.Truncated = truncated
+
+	for _, coin := range balances {
+		balance := AccountBalance{Denom: coin.Denom, Amount: coin.Amount.String()}
+		metadata, err := h.denomMetadata(ctx, target, coin.Denom, height)
+		if err != nil {
+			return target.failureResult(logger, err), nil
+		}
+		if metadata != nil {
+			balance.Metadata = newDenomMetadata(metadata)
+			if exponent, ok := displayExponent(metadata); ok {
+				balance.DisplayDenom = metadata.Display
+				balance.DisplayAmount = shiftDecimal(balance.Amount, exponent)
+			}
+		}
+		response.Balances = append(response.Balances, balance)
+	}
+
+	logger.Info().
+		Bool("exists", response.Exists).
+		Int("balance_count", len(response.Balances)).
+		Bool("truncated", response.Truncated).
+		Int64("block_height", info.blockHeight).
+		Dur("duration", time.Since(start)).
+		Msg("Successfully fetched account")
+
+	toolResult, err := info.formattedResult(response, input.Format)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to marshal response")
+		return nil, fmt.Errorf("failed to marshal account response: %w", err)
+	}
+	return toolResult, nil
+}
+
+// describeAccount fills the response with the number, sequence and type of a packed account
+func (h *AccountHandler) describeAccount(packed *codectypes.Any, response *AccountResponse) error {
+	var account sdk.AccountI
+	if err := h.interfaces.UnpackAny(packed, &account); err != nil {
+		return fmt.Errorf("unsupported account type '%s': %w", packed.TypeUrl, err)
+	}
+	response.Exists = true
+	response.Type = strings.TrimPrefix(packed.TypeUrl, "/")
+	response.AccountNumber = account.GetAccountNumber()
+	response.Sequence = account.GetSequence()
+	if module, ok := account.(sdk.ModuleAccountI); ok {
+		response.ModuleName = module.GetName()
+	}
+	return nil
+}
+
+// balances reads every balance of an account, up to accountMaxBalancePages pages
+func (h *AccountHandler) balances(ctx context.Context, target *chainTarget, address string, height int64) (sdk.Coins, bool, error) {
+	var coins sdk.Coins
+	var nextKey []byte
+	for page := 0; page < accountMaxBalancePages; page++ {
+		req := &banktypes.QueryAllBalancesRequest{Address: address, Pagination: &query.PageRequest{Key: nextKey}}
+		result, _, err := target.invoke(ctx, height, func(ctx context.Context, conn grpc.ClientConnInterface, opts ...grpc.CallOption) (interface{}, error) {
+			return banktypes.NewQueryClient(conn).AllBalances(ctx, req, opts...)
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		resp, ok := result.(*banktypes.QueryAllBalancesResponse)
+		if !ok || resp == nil {
+			return nil, false, fmt.Errorf("unexpected balances response %T", result)
+		}
+		coins = append(coins, resp.Balances...)
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			return coins, false, nil
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+	return coins, true, nil
+}
+
+// denomMetadata reads the bank metadata of a denomination, returning nil when none is registered
+func (h *AccountHandler) denomMetadata(ctx context.Context, target *chainTarget, denom string, height int64) (*banktypes.Metadata, error) {
+	result, _, err := target.invoke(ctx, height, func(ctx context.Context, conn grpc.ClientConnInterface, opts ...grpc.CallOption) (interface{}, error) {
+		return banktypes.NewQueryClient(conn).DenomMetadata(ctx, &banktypes.QueryDenomMetadataRequest{Denom: denom}, opts...)
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp, ok := result.(*banktypes.QueryDenomMetadataResponse)
+	if !ok || resp == nil {
+		return nil, fmt.Errorf("unexpected denom metadata response %T", result)
+	}
+	return &resp.Metadata, nil
+}
+
+// newDenomMetadata converts bank metadata to its response form
+func newDenomMetadata(metadata *banktypes.Metadata) *DenomMetadata {
+	converted := &DenomMetadata{
+		Name:        metadata.Name,
+		Symbol:      metadata.Symbol,
+		Description: metadata.Description,
+		Display:     metadata.Display,
+	}
+	for _, unit := range metadata.DenomUnits {
+		if unit == nil {
+			continue
+		}
+		converted.DenomUnits = append(converted.DenomUnits, DenomUnit{Denom: unit.Denom, Exponent: unit.Exponent, Aliases: unit.Aliases})
+	}
+	return converted
+}
+
+// displayExponent returns the exponent of the metadata's display unit
+func displayExponent(metadata *banktypes.Metadata) (uint32, bool) {
+	if metadata.Display == "" {
+		return 0, false
+	}
+	for _, unit := range metadata.DenomUnits {
+		if unit != nil && unit.Denom == metadata.Display {
+			return unit.Exponent, true
+		}
+	}
+	return 0, false
+}
+
+// shiftDecimal divides an integer amount by 10^exponent, e.g. "1500000" with exponent 6 is "1.5"
+func shiftDecimal(amount string, exponent uint32) string {
+	if exponent == 0 {
+		return amount
+	}
+	digits := int(exponent)
+	if len(amount) <= digits {
+		amount = strings.Repeat("0", digits-len(amount)+1) + amount
+	}
+	whole, fraction := amount[:len(amount)-digits], strings.TrimRight(amount[len(amount)-digits:], "0")
+	if fraction == "" {
+		return whole
+	}
+	return whole + "." + fraction
+}
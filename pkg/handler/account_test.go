@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"overlock-mcp-server/pkg/network"
+
+	"cosmossdk.io/math"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/sony/gobreaker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// fakeAuthServer serves a single account
+type fakeAuthServer struct {
+	authtypes.UnimplementedQueryServer
+	account *codectypes.Any
+}
+
+func (s *fakeAuthServer) Account(_ context.Context, req *authtypes.QueryAccountRequest) (*authtypes.QueryAccountResponse, error) {
+	if s.account == nil {
+		return nil, status.Errorf(codes.NotFound, "account %s not found", req.Address)
+	}
+	return &authtypes.QueryAccountResponse{Account: s.account}, nil
+}
+
+// fakeBankServer serves fixed balances and metadata for the denominations it knows
+type fakeBankServer struct {
+	banktypes.UnimplementedQueryServer
+	balances sdk.Coins
+	metadata map[string]banktypes.Metadata
+}
+
+func (s *fakeBankServer) AllBalances(_ context.Context, _ *banktypes.QueryAllBalancesRequest) (*banktypes.QueryAllBalancesResponse, error) {
+	return &banktypes.QueryAllBalancesResponse{Balances: s.balances}, nil
+}
+
+func (s *fakeBankServer) DenomMetadata(_ context.Context, req *banktypes.QueryDenomMetadataRequest) (*banktypes.QueryDenomMetadataResponse, error) {
+	metadata, ok := s.metadata[req.Denom]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "client metadata for denom %s", req.Denom)
+	}
+	return &banktypes.QueryDenomMetadataResponse{Metadata: metadata}, nil
+}
+
+// newAccountTestHandler serves the auth and bank query services over an in-memory listener
+func newAccountTestHandler(t *testing.T, auth *fakeAuthServer, bank *fakeBankServer) *AccountHandler {
+	t.Helper()
+	conn := newBufconnConn(t, func(server *grpc.Server) {
+		authtypes.RegisterQueryServer(server, auth)
+		banktypes.RegisterQueryServer(server, bank)
+	})
+	return NewAccountHandler(conn, 5*time.Second)
+}
+
+func decodeAccount(t *testing.T, result *mcp.CallToolResult) AccountResponse {
+	t.Helper()
+	require.NotNil(t, result)
+	require.False(t, result.IsError)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	var response AccountResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response), textContent.Text)
+	return response
+}
+
+func TestAccountHandler_Handle(t *testing.T) {
+	address := testAddress(t, AddressPrefix, 1)
+	account, err := codectypes.NewAnyWithValue(&authtypes.BaseAccount{Address: address, AccountNumber: 7, Sequence: 42})
+	require.NoError(t, err)
+	handler := newAccountTestHandler(t, &fakeAuthServer{account: account}, &fakeBankServer{
+		balances: sdk.NewCoins(sdk.NewCoin("ibc/27394FB0", math.NewInt(5)), sdk.NewCoin("uovl", math.NewInt(1500000))),
+		metadata: map[string]banktypes.Metadata{
+			"uovl": {
+				Name:       "Overlock",
+				Symbol:     "OVL",
+				Base:       "uovl",
+				Display:    "ovl",
+				DenomUnits: []*banktypes.DenomUnit{{Denom: "uovl"}, {Denom: "ovl", Exponent: 6}},
+			},
+		},
+	})
+
+	params := &mcp.CallToolParams{
+		Name:      "show-account",
+		Arguments: map[string]interface{}{"address": address},
+	}
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	response := decodeAccount(t, result)
+	assert.Equal(t, address, response.Address)
+	assert.True(t, response.Exists)
+	assert.Equal(t, "cosmos.auth.v1beta1.BaseAccount", response.Type)
+	assert.Equal(t, uint64(7), response.AccountNumber)
+	assert.Equal(t, uint64(42), response.Sequence)
+	require.Len(t, response.Balances, 2)
+
+	assert.Equal(t, "ibc/27394FB0", response.Balances[0].Denom)
+	assert.Equal(t, "5", response.Balances[0].Amount)
+	assert.Nil(t, response.Balances[0].Metadata)
+	assert.Empty(t, response.Balances[0].DisplayAmount)
+
+	assert.Equal(t, "uovl", response.Balances[1].Denom)
+	assert.Equal(t, "1500000", response.Balances[1].Amount)
+	assert.Equal(t, "1.5", response.Balances[1].DisplayAmount)
+	assert.Equal(t, "ovl", response.Balances[1].DisplayDenom)
+	require.NotNil(t, response.Balances[1].Metadata)
+	assert.Equal(t, "OVL", response.Balances[1].Metadata.Symbol)
+	assert.Len(t, response.Balances[1].Metadata.DenomUnits, 2)
+}
+
+func TestAccountHandler_Handle_DenomsWithoutMetadata(t *testing.T) {
+	address := testAddress(t, AddressPrefix, 1)
+	account, err := codectypes.NewAnyWithValue(&authtypes.BaseAccount{Address: address})
+	require.NoError(t, err)
+	handler := newAccountTestHandler(t, &fakeAuthServer{account: account}, &fakeBankServer{
+		balances: sdk.NewCoins(
+			sdk.NewCoin("ibc/0A1B2C3D", math.NewInt(1)),
+			sdk.NewCoin("ibc/27394FB0", math.NewInt(2)),
+			sdk.NewCoin("ibc/9F8E7D6C", math.NewInt(3)),
+			sdk.NewCoin("ibc/C4CFF46F", math.NewInt(4)),
+		),
+	})
+
+	params := &mcp.CallToolParams{
+		Name:      "show-account",
+		Arguments: map[string]interface{}{"address": address},
+	}
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	response := decodeAccount(t, result)
+	require.Len(t, response.Balances, 4)
+	for _, balance := range response.Balances {
+		assert.Nil(t, balance.Metadata)
+	}
+	// Missing metadata is a normal answer and must not open the breaker
+	assert.Equal(t, gobreaker.StateClosed, handler.circuitBreaker.State())
+}
+
+func TestAccountHandler_Handle_ModuleAccount(t *testing.T) {
+	address := testAddress(t, AddressPrefix, 1)
+	account, err := codectypes.NewAnyWithValue(authtypes.NewEmptyModuleAccount("fee_collector"))
+	require.NoError(t, err)
+	handler := newAccountTestHandler(t, &fakeAuthServer{account: account}, &fakeBankServer{})
+
+	params := &mcp.CallToolParams{
+		Name:      "show-account",
+		Arguments: map[string]interface{}{"address": address},
+	}
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	response := decodeAccount(t, result)
+	assert.True(t, response.Exists)
+	assert.Equal(t, "cosmos.auth.v1beta1.ModuleAccount", response.Type)
+	assert.Equal(t, "fee_collector", response.ModuleName)
+	assert.Empty(t, response.Balances)
+}
+
+func TestAccountHandler_Handle_AccountNotFound(t *testing.T) {
+	address := testAddress(t, AddressPrefix, 1)
+	handler := newAccountTestHandler(t, &fakeAuthServer{}, &fakeBankServer{})
+
+	params := &mcp.CallToolParams{
+		Name:      "show-account",
+		Arguments: map[string]interface{}{"address": address},
+	}
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	response := decodeAccount(t, result)
+	assert.False(t, response.Exists)
+	assert.Empty(t, response.Type)
+	assert.NotNil(t, response.Balances)
+	assert.Empty(t, response.Balances)
+}
+
+func TestAccountHandler_Handle_InvalidAddress(t *testing.T) {
+	handler := NewAccountHandler(nil, 5*time.Second)
+
+	tests := []struct {
+		name    string
+		address interface{}
+	}{
+		{name: "not bech32", address: "not-an-address"},
+		{name: "wrong prefix", address: testAddress(t, "cosmos", 1)},
+		{name: "missing", address: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arguments := map[string]interface{}{}
+			if tt.address != nil {
+				arguments["address"] = tt.address
+			}
+			params := &mcp.CallToolParams{Name: "show-account", Arguments: arguments}
+
+			result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, params)
+
+			require.NoError(t, err)
+			failure := decodeValidation(t, result)
+			require.Len(t, failure.Problems, 1)
+			assert.Equal(t, "address", failure.Problems[0].Field)
+		})
+	}
+}
+
+func TestAccountHandler_Handle_NoConnection(t *testing.T) {
+	address := testAddress(t, AddressPrefix, 1)
+	handler := NewAccountHandler(nil, 5*time.Second)
+
+	params := &mcp.CallToolParams{
+		Name:      "show-account",
+		Arguments: map[string]interface{}{"address": address},
+	}
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "gRPC connection to blockchain is not available")
+}
+
+func TestShiftDecimal(t *testing.T) {
+	assert.Equal(t, "1.5", shiftDecimal("1500000", 6))
+	assert.Equal(t, "0.000001", shiftDecimal("1", 6))
+	assert.Equal(t, "2", shiftDecimal("2000000", 6))
+	assert.Equal(t, "0", shiftDecimal("0", 6))
+	assert.Equal(t, "42", shiftDecimal("42", 0))
+}
+
+func TestAccountHandler_Handle_RefusedNetwork(t *testing.T) {
+	conn, err := grpc.NewClient("passthrough:///unused", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	mainnet := &network.Network{Name: "mainnet", Conn: conn, Timeout: 5 * time.Second}
+	mainnet.Refuse(network.ErrChainIDMismatch)
+	handler := NewAccountHandlerForNetworks(network.NewRegistry("mainnet", mainnet))
+
+	params := &mcp.CallToolParams{
+		Name:      "show-account",
+		Arguments: map[string]interface{}{"address": testAddress(t, AddressPrefix, 1)},
+	}
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "gRPC connection to blockchain is not available")
+}
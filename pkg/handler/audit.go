@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"overlock-mcp-server/pkg/audit"
+	"overlock-mcp-server/pkg/network"
+
+	"github.com/Oudwins/zog"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/rs/zerolog/log"
+)
+
+// AuditInput represents the input parameters for the audit-registry tool
+type AuditInput struct {
+	Rules       []string `json:"rules,omitempty"`
+	MinSeverity string   `json:"min_severity,omitempty" zog:"min_severity"`
+	Limit       int      `json:"limit,omitempty"`
+	Network     string   `json:"network,omitempty"`
+	Height      int64    `json:"height,omitempty"`
+	Source      string   `json:"source,omitempty"`
+}
+
+// AuditResponse is the response of the audit-registry tool
+type AuditResponse struct {
+	audit.Report
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// AuditHandler handles the audit-registry tool requests
+type AuditHandler struct {
+	chainBackend
+	rules []audit.Rule
+}
+
+// NewAuditHandler creates a new registry audit handler
+func NewAuditHandler(chainClient overlockv1beta1.QueryClient, timeout time.Duration) *AuditHandler {
+	return &AuditHandler{
+		chainBackend: newChainBackend("blockchain-client-audit", chainClient, timeout),
+		rules:        audit.Rules(),
+	}
+}
+
+// NewAuditHandlerForNetworks creates a registry audit handler serving every configured network
+func NewAuditHandlerForNetworks(networks *network.Registry) *AuditHandler {
+	return &AuditHandler{
+		chainBackend: newChainBackendForNetworks("blockchain-client-audit", networks),
+		rules:        audit.Rules(),
+	}
+}
+
+// Handle processes the audit-registry tool call
+func (h *AuditHandler) Handle(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	// Create a logger with request context
+	logger := log.With().
+		Str("tool", "audit-registry").
+		Str("request_id", fmt.Sprintf("%p", params)).
+		Logger()
+
+	start := time.Now()
+	logger.Info().Msg("Processing audit-registry request")
+
+	// Define validation schema using Zog
+	schema := zog.Struct(zog.Shape{
+		"rules":       zog.Slice(zog.String().OneOf(audit.Names(h.rules))),
+		"minSeverity": zog.String().OneOf(audit.Severities).Default(string(audit.SeverityInfo)),
+		"limit":       zog.Int().GTE(1).LTE(1000).Default(50),
+		"network":     zog.String().Default(""),
+		"height":      zog.Int64().GTE(0).Default(0),
+		"source":      zog.String().OneOf([]string{sourceChain, sourceIndex}).Default(sourceChain),
+	})
+
+	// Validate input parameters
+	var input AuditInput
+	arguments := params.Arguments
+	if arguments == nil {
+		arguments = make(map[string]interface{})
+	}
+
+	logger.Debug().Interface("arguments", arguments).Msg("Validating input arguments")
+	// Parse and validate the arguments
+	errs := schema.Parse(arguments, &input)
+	if errs != nil {
+		logger.Error().Interface("errors", errs).Msg("Input validation failed")
+		return invalidInput(errs), nil
+	}
+
+	target, err := h.resolve(input.Network)
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("network", "known network", input.Network, err.Error()), nil
+	}
+
+	rules := audit.Select(h.rules, input.Rules, audit.Severity(input.MinSeverity))
+	logger.Info().
+		Str("network", target.network).
+		Int64("height", input.Height).
+		Strs("rules", audit.Names(rules)).
+		Msg("Auditing registry")
+
+	var records listing
+	var info queryInfo
+	if input.Source == sourceIndex {
+		snapshot, indexInfo, unavailable := target.snapshot(logger)
+		if unavailable != nil {
+			return unavailable, nil
+		}
+		info = indexInfo
+		records = listing{providers: snapshot.Providers, environments: snapshot.Environments}
+	} else {
+		// Check if chain client is available
+		if target.chainClient == nil {
+			logger.Error().Msg("gRPC client is not available")
+			return target.unavailableResult(), nil
+		}
+		records, info, err = target.listAll(ctx, input.Height, "", fullListMaxPages)
+		if err != nil {
+			return target.failureResult(logger, err), nil
+		}
+	}
+
+	report := audit.Run(audit.Records{
+		Providers:    records.providers,
+		Environments: records.environments,
+		Truncated:    records.truncated,
+	}, rules, input.Limit)
+
+	logger.Info().
+		Int("rules_with_findings", len(report.Rules)).
+		Interface("by_severity", report.BySeverity).
+		Int64("block_height", info.blockHeight).
+		Dur("duration", time.Since(start)).
+		Msg("Successfully audited registry")
+
+	toolResult, err := info.jsonResult(AuditResponse{Report: report, Truncated: records.truncated})
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to marshal response")
+		return nil, fmt.Errorf("failed to marshal audit response: %w", err)
+	}
+	return toolResult, nil
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"overlock-mcp-server/pkg/audit"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeAudit(t *testing.T, result *mcp.CallToolResult) AuditResponse {
+	require.NotNil(t, result)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	var response AuditResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	return response
+}
+
+func newAuditClient() *MockQueryClient {
+	client := &MockQueryClient{}
+	client.On("ListProvider", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryListProviderResponse{
+		Providers: []overlockv1beta1.Provider{
+			{Id: 1, Ip: "203.0.113.10", Port: 8080, Metadata: &overlockv1beta1.Metadata{Name: "good"}},
+			{Id: 2, Ip: "192.168.1.5", Port: 0},
+		},
+		Pagination: &query.PageResponse{},
+	}, nil)
+	client.On("ListEnvironment", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryListEnvironmentResponse{
+		Environments: []overlockv1beta1.Environment{{Id: 1001, Provider: 7, Metadata: &overlockv1beta1.Metadata{Name: "env"}}},
+		Pagination:   &query.PageResponse{},
+	}, nil)
+	return client
+}
+
+func TestAuditHandler_Handle(t *testing.T) {
+	handler := NewAuditHandler(newAuditClient(), 30*time.Second)
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "audit-registry",
+		Arguments: map[string]interface{}{},
+	})
+
+	require.NoError(t, err)
+	response := decodeAudit(t, result)
+	assert.Equal(t, audit.Scanned{Providers: 2, Environments: 1}, response.Scanned)
+	assert.Equal(t, map[audit.Severity]int{audit.SeverityError: 3, audit.SeverityWarning: 1}, response.BySeverity)
+	assert.ElementsMatch(t, []string{audit.RuleInvalidAnnotations, audit.RuleDuplicateEndpoint}, response.Passed)
+
+	var reported []string
+	for _, rule := range response.Rules {
+		reported = append(reported, rule.Rule)
+	}
+	assert.Equal(t, []string{audit.RuleDanglingProvider, audit.RuleInvalidPort, audit.RuleNonPublicIP, audit.RuleMissingName}, reported)
+}
+
+func TestAuditHandler_Handle_SelectedRules(t *testing.T) {
+	handler := NewAuditHandler(newAuditClient(), 30*time.Second)
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name: "audit-registry",
+		Arguments: map[string]interface{}{
+			"rules":        []interface{}{"invalid-port", "missing-name"},
+			"min_severity": "error",
+		},
+	})
+
+	require.NoError(t, err)
+	response := decodeAudit(t, result)
+	require.Len(t, response.Rules, 1)
+	assert.Equal(t, audit.RuleInvalidPort, response.Rules[0].Rule)
+	assert.Equal(t, []audit.Finding{{Kind: audit.KindProvider, Id: 2, Message: "port is 0"}}, response.Rules[0].Findings)
+}
+
+func TestAuditHandler_Handle_InvalidArguments(t *testing.T) {
+	handler := NewAuditHandler(&MockQueryClient{}, 30*time.Second)
+
+	for name, arguments := range map[string]map[string]interface{}{
+		"unknown rule":     {"rules": []interface{}{"no-such-rule"}},
+		"unknown severity": {"min_severity": "critical"},
+		"limit too small":  {"limit": 0},
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+				Name:      "audit-registry",
+				Arguments: arguments,
+			})
+			require.NoError(t, err)
+			assert.Contains(t, decodeValidation(t, result).Error, "validation failed")
+		})
+	}
+}
+
+func TestAuditHandler_Handle_InvalidListingPage(t *testing.T) {
+	client := &MockQueryClient{}
+	client.On("ListProvider", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryListProviderResponse{
+		Providers:  []overlockv1beta1.Provider{{Id: 1, Ip: "203.0.113.10", Port: 8080}},
+		Pagination: &query.PageResponse{},
+	}, nil)
+	client.On("ListEnvironment", mock.Anything, mock.Anything).Return((*overlockv1beta1.QueryListEnvironmentResponse)(nil), nil)
+	handler := NewAuditHandler(client, 30*time.Second)
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "audit-registry",
+		Arguments: map[string]interface{}{},
+	})
+
+	// A partial listing must not be audited as if it were complete
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "Received invalid response from blockchain service")
+}
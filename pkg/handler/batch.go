@@ -0,0 +1,324 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Oudwins/zog"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxBatchIDs bounds the number of IDs a single batch lookup may request
+const maxBatchIDs = 100
+
+// batchWorkers bounds the number of concurrent chain calls of a single batch lookup
+const batchWorkers = 8
+
+// BatchShowInput represents the input parameters for the show-providers and show-environments tools
+type BatchShowInput struct {
+	Ids     []int  `json:"ids,omitempty"`
+	Network string `json:"network,omitempty"`
+	Height  int64  `json:"height,omitempty"`
+	Source  string `json:"source,omitempty"`
+	Expand  bool   `json:"expand,omitempty"`
+}
+
+// BatchError is a lookup in a batch that failed
+type BatchError struct {
+	Id    uint64 `json:"id"`
+	Error string `json:"error"`
+}
+
+// BatchShowResponse is the response of the show-providers and show-environments tools
+type BatchShowResponse struct {
+	Requested int           `json:"requested"`
+	Found     []interface{} `json:"found"`
+	NotFound  []uint64      `json:"not_found"`
+	Errors    []BatchError  `json:"errors"`
+}
+
+// batchLookup fetches a single record by ID; a nil record means it does not exist
+type batchLookup func(ctx context.Context, target *chainTarget, id uint64, height int64) (interface{}, queryInfo, error)
+
+// batchExpander replaces the records found by a batch lookup with their expanded form
+type batchExpander func(ctx context.Context, related relatedRecords, found []interface{}) []interface{}
+
+// batchOutcome is the result of looking up one ID
+type batchOutcome struct {
+	id     uint64
+	record interface{}
+	info   queryInfo
+	err    error
+}
+
+// HandleShowBatch processes the show-providers tool call
+func (h *ProvidersHandler) HandleShowBatch(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	return h.handleBatch(ctx, "show-providers", params, lookupProvider, func(snapshot indexLookup, id uint64) (interface{}, bool) {
+		provider, ok := snapshot.Provider(id)
+		return &provider, ok
+	}, nil)
+}
+
+// HandleShowBatch processes the show-environments tool call
+func (h *EnvironmentHandler) HandleShowBatch(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	return h.handleBatch(ctx, "show-environments", params, lookupEnvironment, func(snapshot indexLookup, id uint64) (interface{}, bool) {
+		environment, ok := snapshot.Environment(id)
+		return &environment, ok
+	}, expandFound)
+}
+
+// lookupProvider fetches a single provider from the chain
+func lookupProvider(ctx context.Context, target *chainTarget, id uint64, height int64) (interface{}, queryInfo, error) {
+	req := &overlockv1beta1.QueryShowProviderRequest{Id: id}
+	result, info, err := target.executeCached(ctx, cacheKey("ShowProvider", height, req), height, func(ctx context.Context, client overlockv1beta1.QueryClient, opts ...grpc.CallOption) (interface{}, error) {
+		return client.ShowProvider(ctx, req, opts...)
+	})
+	if err != nil {
+		return nil, info, err
+	}
+	response, ok := result.(*overlockv1beta1.QueryShowProviderResponse)
+	if !ok || response == nil {
+		return nil, info, fmt.Errorf("invalid response from blockchain service")
+	}
+	if response.Provider == nil {
+		return nil, info, nil
+	}
+	return response.Provider, info, nil
+}
+
+// lookupEnvironment fetches a single environment from the chain
+func lookupEnvironment(ctx context.Context, target *chainTarget, id uint64, height int64) (interface{}, queryInfo, error) {
+	req := &overlockv1beta1.QueryShowEnvironmentRequest{Id: id}
+	result, info, err := target.executeCached(ctx, cacheKey("ShowEnvironment", height, req), height, func(ctx context.Context, client overlockv1beta1.QueryClient, opts ...grpc.CallOption) (interface{}, error) {
+		return client.ShowEnvironment(ctx, req, opts...)
+	})
+	if err != nil {
+		return nil, info, err
+	}
+	response, ok := result.(*overlockv1beta1.QueryShowEnvironmentResponse)
+	if !ok || response == nil {
+		return nil, info, fmt.Errorf("invalid response from blockchain service")
+	}
+	if response.Environment == nil {
+		return nil, info, nil
+	}
+	return response.Environment, info, nil
+}
+
+// expandFound inlines the related records into every environment found by a batch lookup
+func expandFound(ctx context.Context, related relatedRecords, found []interface{}) []interface{} {
+	environments := make([]*overlockv1beta1.Environment, len(found))
+	for i, record := range found {
+		environments[i], _ = record.(*overlockv1beta1.Environment)
+	}
+	expanded := make([]interface{}, len(found))
+	for i, environment := range expandEnvironments(ctx, related, environments) {
+		expanded[i] = environment
+	}
+	return expanded
+}
+
+// indexLookup is the part of an index snapshot a batch lookup reads from
+type indexLookup interface {
+	Provider(id uint64) (overlockv1beta1.Provider, bool)
+	Environment(id uint64) (overlockv1beta1.Environment, bool)
+}
+
+// handleBatch validates a batch lookup and answers it from the chain or the local index
+// When expand is set, the tool accepts an expand argument and applies it to the found records.
+func (b *chainBackend) handleBatch(ctx context.Context, tool string, params *mcp.CallToolParams, lookup batchLookup, fromIndex func(snapshot indexLookup, id uint64) (interface{}, bool), expand batchExpander) (*mcp.CallToolResult, error) {
+	// Create a logger with request context
+	logger := log.With().
+		Str("tool", tool).
+		Str("request_id", fmt.Sprintf("%p", params)).
+		Logger()
+
+	start := time.Now()
+	logger.Info().Msgf("Processing %s request", tool)
+
+	// Define validation schema using Zog
+	shape := zog.Shape{
+		"ids":     zog.Slice(zog.Int().GTE(1)).Required().Min(1).Max(maxBatchIDs),
+		"network": zog.String().Default(""),
+		"height":  zog.Int64().GTE(0).Default(0),
+		"source":  zog.String().OneOf([]string{sourceChain, sourceIndex}).Default(sourceChain),
+	}
+	if expand != nil {
+		shape["expand"] = zog.Bool().Default(false)
+	}
+	schema := zog.Struct(shape)
+
+	// Validate input parameters
+	var input BatchShowInput
+	arguments := params.Arguments
+	if arguments == nil {
+		arguments = make(map[string]interface{})
+	}
+
+	logger.Debug().Interface("arguments", arguments).Msg("Validating input arguments")
+	// Parse and validate the arguments
+	errs := schema.Parse(arguments, &input)
+	if errs != nil {
+		logger.Error().Interface("errors", errs).Msg("Input validation failed")
+		return invalidInput(errs), nil
+	}
+	if len(input.Ids) == 0 {
+		logger.Error().Msg("IDs are required")
+		return requiredArgument("ids", "at least one ID is required"), nil
+	}
+
+	target, err := b.resolve(input.Network)
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("network", "known network", input.Network, err.Error()), nil
+	}
+
+	ids := uniqueIDs(input.Ids)
+	logger.Info().
+		Str("network", target.network).
+		Int64("height", input.Height).
+		Int("id_count", len(ids)).
+		Msg("Fetching batch from blockchain")
+
+	var outcomes []batchOutcome
+	var related relatedRecords
+	info := target.info()
+	if input.Source == sourceIndex {
+		snapshot, indexInfo, unavailable := target.snapshot(logger)
+		if unavailable != nil {
+			return unavailable, nil
+		}
+		info = indexInfo
+		related = snapshotRecords{snapshot: snapshot}
+		for _, id := range ids {
+			outcome := batchOutcome{id: id}
+			if record, ok := fromIndex(snapshot, id); ok {
+				outcome.record = record
+			}
+			outcomes = append(outcomes, outcome)
+		}
+	} else {
+		// Check if chain client is available
+		if target.chainClient == nil {
+			logger.Error().Msg("gRPC client is not available")
+			return target.unavailableResult(), nil
+		}
+		outcomes = runBatch(ctx, target, ids, input.Height, lookup)
+		info = mergeBatchInfo(info, outcomes)
+		related = chainRecords{target: target, height: info.blockHeight}
+	}
+
+	response := BatchShowResponse{
+		Requested: len(ids),
+		Found:     []interface{}{},
+		NotFound:  []uint64{},
+		Errors:    []BatchError{},
+	}
+	for _, outcome := range outcomes {
+		switch {
+		case outcome.err != nil && status.Code(outcome.err) == codes.NotFound:
+			response.NotFound = append(response.NotFound, outcome.id)
+		case outcome.err != nil:
+			response.Errors = append(response.Errors, BatchError{Id: outcome.id, Error: outcome.err.Error()})
+		case outcome.record == nil:
+			response.NotFound = append(response.NotFound, outcome.id)
+		default:
+			response.Found = append(response.Found, outcome.record)
+		}
+	}
+	if input.Expand && len(response.Found) > 0 {
+		response.Found = expand(ctx, related, response.Found)
+	}
+
+	logger.Info().
+		Int("found", len(response.Found)).
+		Int("not_found", len(response.NotFound)).
+		Int("errors", len(response.Errors)).
+		Dur("duration", time.Since(start)).
+		Msg("Successfully processed batch")
+
+	toolResult, err := info.jsonResult(response)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to marshal response")
+		return nil, fmt.Errorf("failed to marshal %s response: %w", tool, err)
+	}
+	return toolResult, nil
+}
+
+// runBatch looks up every ID with at most batchWorkers concurrent calls, returning the
+// outcomes in the order of ids. Without a height, the first lookup runs alone and the rest
+// are pinned to the height it was served at, so the whole batch reflects one block.
+func runBatch(ctx context.Context, target *chainTarget, ids []uint64, height int64, lookup batchLookup) []batchOutcome {
+	outcomes := make([]batchOutcome, len(ids))
+	first := 0
+	if height == 0 && len(ids) > 0 {
+		record, info, err := lookup(ctx, target, ids[0], 0)
+		outcomes[0] = batchOutcome{id: ids[0], record: record, info: info, err: err}
+		if info.staleReason == "" {
+			height = info.blockHeight
+		}
+		first = 1
+	}
+	rest := ids[first:]
+	forEachBounded(len(rest), func(i int) {
+		record, info, err := lookup(ctx, target, rest[i], height)
+		outcomes[first+i] = batchOutcome{id: rest[i], record: record, info: info, err: err}
+	})
+	return outcomes
+}
+
+// forEachBounded calls fn for every index below n with at most batchWorkers calls running at once
+func forEachBounded(n int, fn func(i int)) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(batchWorkers, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// mergeBatchInfo combines the query info of the batch's lookups: the highest block height
+// any lookup was served at, and the oldest stale response if any lookup was served stale
+func mergeBatchInfo(info queryInfo, outcomes []batchOutcome) queryInfo {
+	for _, outcome := range outcomes {
+		if outcome.info.blockHeight > info.blockHeight {
+			info.blockHeight = outcome.info.blockHeight
+		}
+		if outcome.info.staleReason != "" && (info.staleReason == "" || outcome.info.cachedAt.Before(info.cachedAt)) {
+			info.staleReason = outcome.info.staleReason
+			info.cachedAt = outcome.info.cachedAt
+		}
+	}
+	return info
+}
+
+// uniqueIDs converts the requested IDs, dropping duplicates and keeping the requested order
+func uniqueIDs(ids []int) []uint64 {
+	seen := make(map[int]bool, len(ids))
+	unique := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, uint64(id))
+	}
+	return unique
+}
@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"overlock-mcp-server/pkg/indexer"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/sony/gobreaker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// batchTestResponse mirrors BatchShowResponse with the found records decoded as providers
+type batchTestResponse struct {
+	Requested int                        `json:"requested"`
+	Found     []overlockv1beta1.Provider `json:"found"`
+	NotFound  []uint64                   `json:"not_found"`
+	Errors    []BatchError               `json:"errors"`
+}
+
+func decodeBatch(t *testing.T, result *mcp.CallToolResult) batchTestResponse {
+	require.NotNil(t, result)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	var response batchTestResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	return response
+}
+
+func TestProvidersHandler_HandleShowBatch(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewProvidersHandler(mockClient, 30*time.Second)
+
+	mockClient.On("ShowProvider", mock.AnythingOfType("*context.timerCtx"), &overlockv1beta1.QueryShowProviderRequest{Id: 1}).
+		Return(&overlockv1beta1.QueryShowProviderResponse{Provider: &overlockv1beta1.Provider{Id: 1}}, nil)
+	mockClient.On("ShowProvider", mock.AnythingOfType("*context.timerCtx"), &overlockv1beta1.QueryShowProviderRequest{Id: 2}).
+		Return(&overlockv1beta1.QueryShowProviderResponse{}, status.Error(codes.NotFound, "provider not found"))
+	mockClient.On("ShowProvider", mock.AnythingOfType("*context.timerCtx"), &overlockv1beta1.QueryShowProviderRequest{Id: 3}).
+		Return(&overlockv1beta1.QueryShowProviderResponse{}, nil)
+	mockClient.On("ShowProvider", mock.AnythingOfType("*context.timerCtx"), &overlockv1beta1.QueryShowProviderRequest{Id: 4}).
+		Return(&overlockv1beta1.QueryShowProviderResponse{}, errors.New("connection reset"))
+
+	params := &mcp.CallToolParams{
+		Name:      "show-providers",
+		Arguments: map[string]interface{}{"ids": []interface{}{1, 2, 3, 4, 1}},
+	}
+
+	result, err := handler.HandleShowBatch(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	response := decodeBatch(t, result)
+	assert.Equal(t, 4, response.Requested)
+	require.Len(t, response.Found, 1)
+	assert.Equal(t, uint64(1), response.Found[0].Id)
+	assert.Equal(t, []uint64{2, 3}, response.NotFound)
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, uint64(4), response.Errors[0].Id)
+	assert.Equal(t, "connection reset", response.Errors[0].Error)
+
+	// Duplicate IDs are looked up once
+	mockClient.AssertNumberOfCalls(t, "ShowProvider", 4)
+}
+
+func TestProvidersHandler_HandleShowBatch_Validation(t *testing.T) {
+	handler := NewProvidersHandler(&MockQueryClient{}, 30*time.Second)
+
+	tooMany := make([]interface{}, maxBatchIDs+1)
+	for i := range tooMany {
+		tooMany[i] = i + 1
+	}
+
+	for name, arguments := range map[string]map[string]interface{}{
+		"missing ids": {},
+		"empty ids":   {"ids": []interface{}{}},
+		"invalid id":  {"ids": []interface{}{0}},
+		"too many":    {"ids": tooMany},
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := handler.HandleShowBatch(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+				Name:      "show-providers",
+				Arguments: arguments,
+			})
+			require.NoError(t, err)
+			assert.Contains(t, decodeValidation(t, result).Error, "validation failed")
+		})
+	}
+}
+
+func TestProvidersHandler_HandleShowBatch_MissingIDsKeepBreakerClosed(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewProvidersHandler(mockClient, 30*time.Second)
+
+	for _, id := range []uint64{1, 2, 3} {
+		mockClient.On("ShowProvider", mock.AnythingOfType("*context.timerCtx"), &overlockv1beta1.QueryShowProviderRequest{Id: id}).
+			Return(&overlockv1beta1.QueryShowProviderResponse{}, status.Error(codes.NotFound, "provider not found"))
+	}
+
+	params := &mcp.CallToolParams{
+		Name:      "show-providers",
+		Arguments: map[string]interface{}{"ids": []interface{}{1, 2, 3}},
+	}
+
+	result, err := handler.HandleShowBatch(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 3}, decodeBatch(t, result).NotFound)
+	assert.Equal(t, gobreaker.StateClosed, handler.circuitBreaker.State())
+	assert.Zero(t, handler.circuitBreaker.Counts().ConsecutiveFailures)
+}
+
+// concurrencyClient records the highest number of concurrent ShowEnvironment calls
+type concurrencyClient struct {
+	overlockv1beta1.QueryClient
+	active  atomic.Int64
+	highest atomic.Int64
+}
+
+func (c *concurrencyClient) ShowEnvironment(ctx context.Context, req *overlockv1beta1.QueryShowEnvironmentRequest, opts ...grpc.CallOption) (*overlockv1beta1.QueryShowEnvironmentResponse, error) {
+	active := c.active.Add(1)
+	defer c.active.Add(-1)
+	for {
+		highest := c.highest.Load()
+		if active <= highest || c.highest.CompareAndSwap(highest, active) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+	return &overlockv1beta1.QueryShowEnvironmentResponse{Environment: &overlockv1beta1.Environment{Id: req.Id}}, nil
+}
+
+func TestEnvironmentHandler_HandleShowBatch_BoundedConcurrency(t *testing.T) {
+	client := &concurrencyClient{}
+	handler := NewEnvironmentHandler(client, 30*time.Second)
+
+	ids := make([]interface{}, 40)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+
+	result, err := handler.HandleShowBatch(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "show-environments",
+		Arguments: map[string]interface{}{"ids": ids},
+	})
+
+	require.NoError(t, err)
+	response := decodeBatch(t, result)
+	assert.Equal(t, 40, response.Requested)
+	require.Len(t, response.Found, 40)
+	// Results keep the requested order
+	assert.Equal(t, uint64(1), response.Found[0].Id)
+	assert.Equal(t, uint64(40), response.Found[39].Id)
+	assert.LessOrEqual(t, client.highest.Load(), int64(batchWorkers))
+	assert.Greater(t, client.highest.Load(), int64(1))
+}
+
+// advancingHeightClient produces a new block on every unpinned ShowProvider call and
+// records the height each call asked for
+type advancingHeightClient struct {
+	overlockv1beta1.QueryClient
+	mu        sync.Mutex
+	latest    int64
+	requested []string
+}
+
+func (c *advancingHeightClient) ShowProvider(ctx context.Context, req *overlockv1beta1.QueryShowProviderRequest, opts ...grpc.CallOption) (*overlockv1beta1.QueryShowProviderResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	requested := ""
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get("x-cosmos-block-height")) > 0 {
+		requested = md.Get("x-cosmos-block-height")[0]
+	}
+	c.requested = append(c.requested, requested)
+	served := requested
+	if served == "" {
+		c.latest++
+		served = strconv.FormatInt(c.latest, 10)
+	}
+	for _, opt := range opts {
+		if header, ok := opt.(grpc.HeaderCallOption); ok {
+			*header.HeaderAddr = metadata.Pairs("x-cosmos-block-height", served)
+		}
+	}
+	return &overlockv1beta1.QueryShowProviderResponse{Provider: &overlockv1beta1.Provider{Id: req.Id}}, nil
+}
+
+func TestProvidersHandler_HandleShowBatch_PinsLatestHeight(t *testing.T) {
+	client := &advancingHeightClient{latest: 100}
+	handler := NewProvidersHandler(client, 30*time.Second)
+
+	result, err := handler.HandleShowBatch(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "show-providers",
+		Arguments: map[string]interface{}{"ids": []interface{}{1, 2, 3, 4}},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, decodeBatch(t, result).Found, 4)
+	assert.Equal(t, int64(101), result.Meta["block_height"])
+	// Only the first lookup reads the latest block, the rest are pinned to it
+	assert.Equal(t, []string{"", "101", "101", "101"}, client.requested)
+}
+
+func TestProvidersHandler_HandleShowBatch_CircuitBreakerOpen(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewProvidersHandler(mockClient, 30*time.Second)
+	handler.circuitBreaker = openBreaker(t)
+
+	result, err := handler.HandleShowBatch(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "show-providers",
+		Arguments: map[string]interface{}{"ids": []interface{}{1, 2}},
+	})
+
+	require.NoError(t, err)
+	response := decodeBatch(t, result)
+	assert.Empty(t, response.Found)
+	assert.Len(t, response.Errors, 2)
+	mockClient.AssertNotCalled(t, "ShowProvider", mock.Anything, mock.Anything)
+}
+
+func TestEnvironmentHandler_HandleShowBatch_FromIndex(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewEnvironmentHandler(mockClient, 30*time.Second)
+	handler.AttachIndexers(map[string]*indexer.Indexer{"default": newSyncedIndexer(t)})
+
+	result, err := handler.HandleShowBatch(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "show-environments",
+		Arguments: map[string]interface{}{"ids": []interface{}{1001, 1002}, "source": "index"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "index", result.Meta["source"])
+	response := decodeBatch(t, result)
+	require.Len(t, response.Found, 1)
+	assert.Equal(t, uint64(1001), response.Found[0].Id)
+	assert.Equal(t, []uint64{1002}, response.NotFound)
+	mockClient.AssertNotCalled(t, "ShowEnvironment", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"overlock-mcp-server/pkg/render"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// bytesPerToken approximates how many bytes of rendered output make up one model token
+const bytesPerToken = 4
+
+// listOptions shapes how a list response is rendered
+type listOptions struct {
+	format   string
+	fields   []string // dotted record paths to keep, empty keeps every field
+	maxBytes int      // budget for the rendered text, 0 is unlimited
+	// continuation builds the token that resumes the listing after the first returned records
+	continuation func(returned int) string
+}
+
+// continuation is the decoded form of a continuation token
+type continuation struct {
+	Offset int   `json:"offset"`
+	Height int64 `json:"height,omitempty"` // block height the first call was answered at, keeps the walk consistent
+}
+
+// encodeContinuation builds an opaque continuation token
+func encodeContinuation(c continuation) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeContinuation parses a continuation token
+func decodeContinuation(token string) (continuation, error) {
+	var c continuation
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		err = json.Unmarshal(raw, &c)
+	}
+	if err != nil || c.Offset < 0 || c.Height < 0 {
+		return continuation{}, fmt.Errorf("invalid continuation_token: pass the token of a previous response unchanged")
+	}
+	return c, nil
+}
+
+// byteBudget combines the max_tokens and max_bytes arguments into a byte budget, 0 being unlimited
+func byteBudget(maxTokens, maxBytes int) int {
+	budget := maxBytes
+	if maxTokens > 0 && (budget == 0 || maxTokens*bytesPerToken < budget) {
+		budget = maxTokens * bytesPerToken
+	}
+	return budget
+}
+
+// recordFields returns the dotted paths a record can be projected on
+func recordFields(record interface{}) []string {
+	tree, err := render.Value(record)
+	if err != nil {
+		return nil
+	}
+	object, _ := tree.(render.Object)
+	return render.Paths(object)
+}
+
+// truncationLine repeats the truncation note and continuation token after the table for
+// the csv and markdown formats, which would otherwise carry them only in the metadata
+// (csv) or in the key/value table above a long list (markdown)
+func truncationLine(format, note, token string) string {
+	switch format {
+	case render.FormatCSV:
+		return fmt.Sprintf("# truncated: %s continuation_token: %s\n", note, token)
+	case render.FormatMarkdown:
+		return fmt.Sprintf("\n_Truncated: %s continuation_token: `%s`_\n", note, token)
+	default:
+		return ""
+	}
+}
+
+// listResult renders a list response, projecting its records on the requested fields.
+// When the rendered text exceeds the budget, it keeps the longest prefix of records
+// that fits (at least one, so a continuation always makes progress) and reports the
+// omitted records together with a continuation token.
+func (q queryInfo) listResult(response interface{}, listKey string, opts listOptions) (*mcp.CallToolResult, error) {
+	tree, err := render.Value(response)
+	if err != nil {
+		return nil, err
+	}
+	object, ok := tree.(render.Object)
+	if !ok {
+		return nil, fmt.Errorf("list response is not an object")
+	}
+	value, _ := object.Get(listKey)
+	records, _ := value.([]interface{})
+	if len(opts.fields) > 0 {
+		for i, record := range records {
+			if record, ok := record.(render.Object); ok {
+				records[i] = render.Project(record, opts.fields)
+			}
+		}
+	}
+
+	build := func(returned int) (string, mcp.Meta, error) {
+		meta := q.meta()
+		if returned < len(records) {
+			omitted := len(records) - returned
+			meta["truncated"] = true
+			meta["omitted_records"] = omitted
+			meta["continuation_token"] = opts.continuation(returned)
+			meta["truncation_note"] = fmt.Sprintf(
+				"Returned %d of %d %s to stay within the %d byte response budget; %d omitted. Call again with the same arguments and this continuation_token to get the rest.",
+				returned, len(records), listKey, opts.maxBytes, omitted)
+		}
+		object = object.Set(listKey, records[:returned])
+		text, err := renderAs(object, meta, opts.format)
+		if err == nil && returned < len(records) {
+			text += truncationLine(opts.format, meta["truncation_note"].(string), meta["continuation_token"].(string))
+		}
+		return text, meta, err
+	}
+
+	text, meta, err := build(len(records))
+	if err != nil {
+		return nil, err
+	}
+	if opts.maxBytes > 0 && len(text) > opts.maxBytes && len(records) > 1 {
+		// Rendered size grows with the number of records, so search for the longest prefix that fits
+		returned := sort.Search(len(records)-1, func(n int) bool {
+			text, _, err := build(n + 2)
+			return err != nil || len(text) > opts.maxBytes
+		}) + 1
+		if text, meta, err = build(returned); err != nil {
+			return nil, err
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Meta: meta,
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: text,
+			},
+		},
+	}, nil
+}
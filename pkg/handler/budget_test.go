@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"overlock-mcp-server/pkg/render"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func budgetProviders(n int) *overlockv1beta1.QueryListProviderResponse {
+	response := &overlockv1beta1.QueryListProviderResponse{}
+	for i := 1; i <= n; i++ {
+		response.Providers = append(response.Providers, overlockv1beta1.Provider{
+			Id:       uint64(i),
+			Creator:  fmt.Sprintf("overlock1creator%03d", i),
+			Metadata: &overlockv1beta1.Metadata{Name: fmt.Sprintf("provider-%03d", i)},
+		})
+	}
+	return response
+}
+
+func budgetOptions(maxBytes int) listOptions {
+	return listOptions{
+		format:   render.FormatJSON,
+		maxBytes: maxBytes,
+		continuation: func(returned int) string {
+			return encodeContinuation(continuation{Offset: returned, Height: 42})
+		},
+	}
+}
+
+func TestByteBudget(t *testing.T) {
+	assert.Equal(t, 0, byteBudget(0, 0))
+	assert.Equal(t, 4000, byteBudget(1000, 0))
+	assert.Equal(t, 2000, byteBudget(0, 2000))
+	assert.Equal(t, 2000, byteBudget(1000, 2000))
+	assert.Equal(t, 400, byteBudget(100, 2000))
+}
+
+func TestContinuation_RoundTrip(t *testing.T) {
+	token := encodeContinuation(continuation{Offset: 25, Height: 1200})
+
+	decoded, err := decodeContinuation(token)
+
+	require.NoError(t, err)
+	assert.Equal(t, continuation{Offset: 25, Height: 1200}, decoded)
+
+	_, err = decodeContinuation("not a token")
+	assert.ErrorContains(t, err, "invalid continuation_token")
+}
+
+func TestListResult_WithinBudget(t *testing.T) {
+	info := queryInfo{network: "default"}
+
+	result, err := info.listResult(budgetProviders(3), "providers", budgetOptions(0))
+
+	require.NoError(t, err)
+	assert.NotContains(t, result.Meta, "truncated")
+	var response overlockv1beta1.QueryListProviderResponse
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response))
+	assert.Len(t, response.Providers, 3)
+}
+
+func TestListResult_Truncates(t *testing.T) {
+	info := queryInfo{network: "default"}
+
+	result, err := info.listResult(budgetProviders(20), "providers", budgetOptions(2000))
+
+	require.NoError(t, err)
+	text := result.Content[0].(*mcp.TextContent).Text
+	assert.LessOrEqual(t, len(text), 2000)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(text), &response))
+	returned := len(response["providers"].([]interface{}))
+	assert.Greater(t, returned, 0)
+	assert.Less(t, returned, 20)
+	assert.Equal(t, true, response["truncated"])
+	assert.Equal(t, float64(20-returned), response["omitted_records"])
+	assert.Contains(t, response["truncation_note"], "continuation_token")
+	assert.Equal(t, true, result.Meta["truncated"])
+
+	resume, err := decodeContinuation(response["continuation_token"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, continuation{Offset: returned, Height: 42}, resume)
+
+	// Truncation is deterministic
+	again, err := info.listResult(budgetProviders(20), "providers", budgetOptions(2000))
+	require.NoError(t, err)
+	assert.Equal(t, text, again.Content[0].(*mcp.TextContent).Text)
+}
+
+func TestListResult_TruncatesTables(t *testing.T) {
+	info := queryInfo{network: "default"}
+
+	for _, format := range []string{render.FormatCSV, render.FormatMarkdown} {
+		t.Run(format, func(t *testing.T) {
+			opts := budgetOptions(1500)
+			opts.format = format
+
+			result, err := info.listResult(budgetProviders(40), "providers", opts)
+
+			require.NoError(t, err)
+			text := result.Content[0].(*mcp.TextContent).Text
+			assert.LessOrEqual(t, len(text), 1500)
+			require.Equal(t, true, result.Meta["truncated"])
+			lines := strings.Split(strings.TrimSpace(text), "\n")
+			last := lines[len(lines)-1]
+			assert.Contains(t, last, result.Meta["truncation_note"])
+			assert.Contains(t, last, result.Meta["continuation_token"])
+		})
+	}
+}
+
+func TestListResult_KeepsOneRecord(t *testing.T) {
+	info := queryInfo{network: "default"}
+
+	result, err := info.listResult(budgetProviders(3), "providers", budgetOptions(10))
+
+	require.NoError(t, err)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response))
+	assert.Len(t, response["providers"], 1)
+	assert.Equal(t, float64(2), response["omitted_records"])
+}
+
+func TestListResult_Fields(t *testing.T) {
+	info := queryInfo{network: "default"}
+	options := budgetOptions(0)
+	options.fields = []string{"id", "metadata.name"}
+
+	result, err := info.listResult(budgetProviders(2), "providers", options)
+
+	require.NoError(t, err)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response))
+	provider := response["providers"].([]interface{})[1].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"id": 2.0, "metadata": map[string]interface{}{"name": "provider-002"}}, provider)
+}
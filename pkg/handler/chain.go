@@ -0,0 +1,355 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"overlock-mcp-server/pkg/indexer"
+	"overlock-mcp-server/pkg/network"
+	"overlock-mcp-server/pkg/render"
+
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/sony/gobreaker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Data sources a tool call can be answered from
+const (
+	sourceChain = "chain"
+	sourceIndex = "index"
+)
+
+// chainBackend holds what a handler needs to reach the chain.
+// The chainClient, timeout and circuitBreaker fields belong to the default
+// network; additional networks are kept in networks, each with its own breaker.
+type chainBackend struct {
+	chainClient    overlockv1beta1.QueryClient
+	conn           grpc.ClientConnInterface // raw connection for calls outside the Overlock query service, nil when not dialed
+	timeout        time.Duration
+	circuitBreaker *gobreaker.CircuitBreaker
+
+	index    *indexer.Indexer
+	lastGood *lastGoodCache
+
+	defaultNetwork string
+	defaultSource  *network.Network // nil for a single, unnamed network
+	networks       map[string]*chainTarget
+}
+
+// chainTarget is a resolved network a single tool call is served from
+type chainTarget struct {
+	network        string
+	chainClient    overlockv1beta1.QueryClient
+	conn           grpc.ClientConnInterface
+	timeout        time.Duration
+	circuitBreaker *gobreaker.CircuitBreaker
+	index          *indexer.Indexer
+	lastGood       *lastGoodCache
+	source         *network.Network // checked on every call, as the network may be refused at runtime
+}
+
+// newChainBackend creates a backend for a single, unnamed network
+func newChainBackend(breakerName string, chainClient overlockv1beta1.QueryClient, timeout time.Duration) chainBackend {
+	return chainBackend{
+		chainClient:    chainClient,
+		timeout:        timeout,
+		circuitBreaker: newCircuitBreaker(breakerName),
+		lastGood:       newLastGoodCache(defaultStaleMaxAge),
+		defaultNetwork: "default",
+	}
+}
+
+// newChainBackendForNetworks creates a backend serving every network in the registry
+func newChainBackendForNetworks(breakerName string, networks *network.Registry) chainBackend {
+	b := chainBackend{
+		circuitBreaker: newCircuitBreaker(breakerName),
+		lastGood:       newLastGoodCache(defaultStaleMaxAge),
+		defaultNetwork: networks.DefaultName(),
+		networks:       make(map[string]*chainTarget),
+	}
+	if n := networks.Default(); n != nil {
+		b.chainClient = n.Client
+		b.conn = networkConn(n)
+		b.defaultSource = n
+		b.timeout = n.Timeout
+	}
+	for _, name := range networks.Names() {
+		if name == b.defaultNetwork {
+			continue
+		}
+		n, _ := networks.Get(name)
+		b.networks[name] = &chainTarget{
+			network:        name,
+			chainClient:    n.Client,
+			conn:           networkConn(n),
+			timeout:        n.Timeout,
+			circuitBreaker: newCircuitBreaker(breakerName + "-" + name),
+			lastGood:       newLastGoodCache(defaultStaleMaxAge),
+			source:         n,
+		}
+	}
+	return b
+}
+
+// networkConn returns the network's connection, or nil when it was not dialed
+func networkConn(n *network.Network) grpc.ClientConnInterface {
+	if n.Conn == nil {
+		return nil
+	}
+	return n.Conn
+}
+
+// newCircuitBreaker creates the circuit breaker guarding calls to one network
+func newCircuitBreaker(name string) *gobreaker.CircuitBreaker {
+	return gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		Name:        name,
+		MaxRequests: 3,
+		Interval:    30 * time.Second,
+		Timeout:     60 * time.Second,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures > 2
+		},
+		IsSuccessful: isBreakerSuccess,
+		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
+			log.Warn().
+				Str("circuit_breaker", name).
+				Str("from", from.String()).
+				Str("to", to.String()).
+				Msg("Circuit breaker state changed")
+		},
+	})
+}
+
+// isBreakerSuccess reports whether a call's outcome shows the network is healthy.
+// A missing record or a rejected request is a normal answer from a working node
+// and must not open the breaker every tool shares.
+func isBreakerSuccess(err error) bool {
+	switch status.Code(err) {
+	case codes.OK, codes.NotFound, codes.InvalidArgument:
+		return true
+	}
+	return false
+}
+
+// resolve returns the target for the named network, or the default network when name is empty
+func (b *chainBackend) resolve(name string) (*chainTarget, error) {
+	if name == "" || name == b.defaultNetwork {
+		return served(&chainTarget{
+			network:        b.defaultNetwork,
+			chainClient:    b.chainClient,
+			conn:           b.conn,
+			timeout:        b.timeout,
+			circuitBreaker: b.circuitBreaker,
+			index:          b.index,
+			lastGood:       b.lastGood,
+			source:         b.defaultSource,
+		}), nil
+	}
+	if target, ok := b.networks[name]; ok {
+		return served(target), nil
+	}
+	return nil, fmt.Errorf("%w '%s' (available: %s)", network.ErrUnknownNetwork, name, b.networkNames())
+}
+
+// served returns the target as it may be used right now: without a client or connection
+// while its network is refused, so the call reports the network as unavailable
+func served(target *chainTarget) *chainTarget {
+	if target.source == nil || target.source.RefusedReason() == nil {
+		return target
+	}
+	refused := *target
+	refused.chainClient = nil
+	refused.conn = nil
+	return &refused
+}
+
+// AttachIndexers lets the handler answer source=index requests from the given indexers, keyed by network
+func (b *chainBackend) AttachIndexers(indexers map[string]*indexer.Indexer) {
+	b.index = indexers[b.defaultNetwork]
+	for name, target := range b.networks {
+		target.index = indexers[name]
+	}
+}
+
+// networkNames lists the networks the backend can serve
+func (b *chainBackend) networkNames() string {
+	names := b.defaultNetwork
+	for _, name := range sortedKeys(b.networks) {
+		names += ", " + name
+	}
+	return names
+}
+
+// chainQuery is a single call against a network's query client
+type chainQuery func(ctx context.Context, client overlockv1beta1.QueryClient, opts ...grpc.CallOption) (interface{}, error)
+
+// queryInfo describes where, and at which block height, a response was served
+type queryInfo struct {
+	network     string
+	blockHeight int64
+
+	// Set when the response was served from the local index instead of the chain
+	indexedAt time.Time
+
+	// Set when a last-known-good response was served because the chain could not be reached
+	cachedAt    time.Time
+	staleReason string
+}
+
+// execute runs query against the target's client with timeout and circuit breaker protection.
+// A positive height pins the query to that block through the x-cosmos-block-height header;
+// the height reported back by the node is returned in the queryInfo.
+func (t *chainTarget) execute(ctx context.Context, height int64, query chainQuery) (interface{}, queryInfo, error) {
+	return t.call(ctx, height, func(ctx context.Context, opts ...grpc.CallOption) (interface{}, error) {
+		return query(ctx, t.chainClient, opts...)
+	})
+}
+
+// invoke runs call on the target's raw connection with the same protection as execute
+func (t *chainTarget) invoke(ctx context.Context, height int64, call func(ctx context.Context, conn grpc.ClientConnInterface, opts ...grpc.CallOption) (interface{}, error)) (interface{}, queryInfo, error) {
+	return t.call(ctx, height, func(ctx context.Context, opts ...grpc.CallOption) (interface{}, error) {
+		return call(ctx, t.conn, opts...)
+	})
+}
+
+// call runs a single RPC with timeout and circuit breaker protection, pinned to height when positive
+func (t *chainTarget) call(ctx context.Context, height int64, rpc func(ctx context.Context, opts ...grpc.CallOption) (interface{}, error)) (interface{}, queryInfo, error) {
+	info := t.info()
+
+	if height > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, grpctypes.GRPCBlockHeightHeader, strconv.FormatInt(height, 10))
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	var header metadata.MD
+	result, err := t.circuitBreaker.Execute(func() (interface{}, error) {
+		return rpc(timeoutCtx, grpc.Header(&header))
+	})
+
+	info.blockHeight = blockHeightFromHeader(header)
+	if info.blockHeight == 0 {
+		info.blockHeight = height
+	}
+	return result, info, err
+}
+
+// blockHeightFromHeader reads the block height a node reports in its response header
+func blockHeightFromHeader(header metadata.MD) int64 {
+	values := header.Get(grpctypes.GRPCBlockHeightHeader)
+	if len(values) == 0 {
+		return 0
+	}
+	height, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return height
+}
+
+// info returns the queryInfo for responses that did not reach the chain
+func (t *chainTarget) info() queryInfo {
+	return queryInfo{network: t.network}
+}
+
+// snapshot returns the target's index snapshot, or a tool result explaining why it cannot be used
+func (t *chainTarget) snapshot(logger zerolog.Logger) (*indexer.Snapshot, queryInfo, *mcp.CallToolResult) {
+	info := t.info()
+	if t.index == nil {
+		logger.Error().Msg("Index is not enabled")
+		return nil, info, info.textResult(fmt.Sprintf("Error: the local index is not enabled for network '%s'. Use source 'chain' instead.", t.network))
+	}
+	snapshot, err := t.index.Snapshot()
+	if err != nil {
+		logger.Info().Err(err).Msg("Index is not ready")
+		return nil, info, info.textResult(fmt.Sprintf("The local index for network '%s' has not completed its first sync yet. Please try again later or use source 'chain'.", t.network))
+	}
+	info.blockHeight = snapshot.BlockHeight
+	info.indexedAt = snapshot.SyncedAt
+	return snapshot, info, nil
+}
+
+// unavailableResult is returned when the target has no gRPC client
+func (t *chainTarget) unavailableResult() *mcp.CallToolResult {
+	return t.info().textResult("Error: gRPC connection to blockchain is not available. Please check the connection and try again.")
+}
+
+// errInvalidResponse is returned by multi-page reads when a page is not the expected message
+var errInvalidResponse = errors.New("received invalid response from blockchain service")
+
+// failureResult converts a failed chain call into a user-friendly tool result
+func (t *chainTarget) failureResult(logger zerolog.Logger, err error) *mcp.CallToolResult {
+	if errors.Is(err, errInvalidResponse) {
+		return t.invalidResponseResult(logger)
+	}
+	logger.Info().Err(err).Msg("Failed to connect to gRPC server - blockchain service unavailable")
+	// Check if it's a circuit breaker error
+	if err == gobreaker.ErrOpenState {
+		return t.info().textResult("Blockchain service is currently unavailable (circuit breaker protection active). Please try again later.")
+	}
+	// Return a user-friendly response instead of propagating the error
+	return t.info().textResult("Unable to connect to blockchain service. The service may be temporarily unavailable. Please check your connection and try again later.")
+}
+
+// invalidResponseResult is returned when the chain answers with an unexpected message
+func (t *chainTarget) invalidResponseResult(logger zerolog.Logger) *mcp.CallToolResult {
+	logger.Error().Msg("Received invalid response from blockchain service")
+	return t.info().textResult("Received invalid response from blockchain service. Please try again later.")
+}
+
+// textResult wraps a plain text message in a tool result tagged with the query info
+func (q queryInfo) textResult(text string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Meta: q.meta(),
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: text,
+			},
+		},
+	}
+}
+
+// jsonResult renders a chain response as indented JSON tagged with the query info
+func (q queryInfo) jsonResult(response interface{}) (*mcp.CallToolResult, error) {
+	return q.formattedResult(response, render.FormatJSON)
+}
+
+// formattedResult renders a chain response in the requested output format tagged with the query info
+func (q queryInfo) formattedResult(response interface{}, format string) (*mcp.CallToolResult, error) {
+	text, err := renderAs(response, q.meta(), format)
+	if err != nil {
+		return nil, err
+	}
+	return q.textResult(text), nil
+}
+
+// meta returns the metadata every response is tagged with
+func (q queryInfo) meta() mcp.Meta {
+	meta := mcp.Meta{"network": q.network}
+	if q.blockHeight > 0 {
+		meta["block_height"] = q.blockHeight
+	}
+	if !q.indexedAt.IsZero() {
+		meta["source"] = "index"
+		meta["index_synced_at"] = q.indexedAt.Format(time.RFC3339)
+		meta["staleness_seconds"] = int64(time.Since(q.indexedAt).Seconds())
+	}
+	if q.staleReason != "" {
+		meta["stale"] = true
+		meta["stale_reason"] = q.staleReason
+		meta["cached_at"] = q.cachedAt.UTC().Format(time.RFC3339)
+		meta["staleness_seconds"] = int64(time.Since(q.cachedAt).Seconds())
+	}
+	return meta
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newBufconnConn serves the services added by register over an in-memory listener
+// and returns a client connection to it; both are closed when the test ends
+func newBufconnConn(t *testing.T, register func(server *grpc.Server)) *grpc.ClientConn {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	register(server)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// heightQueryClient reports a block height in the response header, like a Cosmos node does,
+// and records the height requested by the caller
+type heightQueryClient struct {
+	*MockQueryClient
+	blockHeight     string
+	requestedHeight []string
+}
+
+func (c *heightQueryClient) reportHeight(ctx context.Context, opts []grpc.CallOption) {
+	if md, ok := metadata.FromOutgoingContext(ctx); ok {
+		c.requestedHeight = md.Get("x-cosmos-block-height")
+	}
+	for _, opt := range opts {
+		if header, ok := opt.(grpc.HeaderCallOption); ok {
+			*header.HeaderAddr = metadata.Pairs("x-cosmos-block-height", c.blockHeight)
+		}
+	}
+}
+
+func (c *heightQueryClient) ShowProvider(ctx context.Context, req *overlockv1beta1.QueryShowProviderRequest, opts ...grpc.CallOption) (*overlockv1beta1.QueryShowProviderResponse, error) {
+	c.reportHeight(ctx, opts)
+	return c.MockQueryClient.ShowProvider(ctx, req, opts...)
+}
+
+func (c *heightQueryClient) ListProvider(ctx context.Context, req *overlockv1beta1.QueryListProviderRequest, opts ...grpc.CallOption) (*overlockv1beta1.QueryListProviderResponse, error) {
+	c.reportHeight(ctx, opts)
+	return c.MockQueryClient.ListProvider(ctx, req, opts...)
+}
+
+func TestProvidersHandler_HandleShow_ReportsBlockHeight(t *testing.T) {
+	client := &heightQueryClient{MockQueryClient: &MockQueryClient{}, blockHeight: "4242"}
+	handler := NewProvidersHandler(client, 30*time.Second)
+
+	client.On("ShowProvider", mock.AnythingOfType("*context.timerCtx"), mock.Anything).Return(&overlockv1beta1.QueryShowProviderResponse{
+		Provider: &overlockv1beta1.Provider{Id: 1},
+	}, nil)
+
+	params := &mcp.CallToolParams{
+		Name: "show-provider",
+		Arguments: map[string]interface{}{
+			"id": 1,
+		},
+	}
+
+	result, err := handler.HandleShow(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Empty(t, client.requestedHeight)
+	assert.Equal(t, int64(4242), result.Meta["block_height"])
+
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	assert.Equal(t, 4242.0, response["block_height"])
+	assert.Equal(t, "default", response["network"])
+
+	client.AssertExpectations(t)
+}
+
+func TestProvidersHandler_HandleList_PinnedHeight(t *testing.T) {
+	client := &heightQueryClient{MockQueryClient: &MockQueryClient{}, blockHeight: "1200"}
+	handler := NewProvidersHandler(client, 30*time.Second)
+
+	client.On("ListProvider", mock.AnythingOfType("*context.timerCtx"), mock.Anything).Return(&overlockv1beta1.QueryListProviderResponse{}, nil)
+
+	params := &mcp.CallToolParams{
+		Name: "get-providers",
+		Arguments: map[string]interface{}{
+			"offset": 100,
+			"height": 1200,
+		},
+	}
+
+	result, err := handler.HandleList(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, []string{"1200"}, client.requestedHeight)
+	assert.Equal(t, int64(1200), result.Meta["block_height"])
+
+	client.AssertExpectations(t)
+}
+
+func TestProvidersHandler_HandleList_NegativeHeight(t *testing.T) {
+	handler := NewProvidersHandler(&MockQueryClient{}, 30*time.Second)
+
+	params := &mcp.CallToolParams{
+		Name: "get-providers",
+		Arguments: map[string]interface{}{
+			"height": -1,
+		},
+	}
+
+	result, err := handler.HandleList(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	assert.Contains(t, decodeValidation(t, result).Error, "validation failed")
+}
+
+func TestBlockHeightFromHeader(t *testing.T) {
+	assert.Equal(t, int64(0), blockHeightFromHeader(nil))
+	assert.Equal(t, int64(0), blockHeightFromHeader(metadata.Pairs("x-cosmos-block-height", "abc")))
+	assert.Equal(t, int64(17), blockHeightFromHeader(metadata.Pairs("x-cosmos-block-height", "17")))
+}
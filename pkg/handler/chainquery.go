@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"overlock-mcp-server/pkg/chainquery"
+	"overlock-mcp-server/pkg/network"
+	"overlock-mcp-server/pkg/render"
+
+	"github.com/Oudwins/zog"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+)
+
+// ChainQueryInput represents the input parameters for the chain-query tool
+type ChainQueryInput struct {
+	Method  string `json:"method,omitempty"`
+	Network string `json:"network,omitempty"`
+	Height  int64  `json:"height,omitempty"`
+	Format  string `json:"format,omitempty"`
+}
+
+// ChainQueryResponse is the response of the chain-query tool
+type ChainQueryResponse struct {
+	Method   string          `json:"method"`
+	Response json.RawMessage `json:"response"`
+}
+
+// ChainQueryHandler handles the chain-query tool requests
+type ChainQueryHandler struct {
+	chainBackend
+	allowlist chainquery.Allowlist
+	resolver  chainquery.Resolver
+}
+
+// NewChainQueryHandler creates a chain-query handler calling methods over conn
+func NewChainQueryHandler(conn grpc.ClientConnInterface, timeout time.Duration, allowlist chainquery.Allowlist) *ChainQueryHandler {
+	backend := newChainBackend("blockchain-client-chain-query", nil, timeout)
+	backend.conn = conn
+	return &ChainQueryHandler{
+		chainBackend: backend,
+		allowlist:    allowlist,
+		resolver:     newChainQueryResolver(),
+	}
+}
+
+// NewChainQueryHandlerForNetworks creates a chain-query handler serving every configured network
+func NewChainQueryHandlerForNetworks(networks *network.Registry, allowlist chainquery.Allowlist) *ChainQueryHandler {
+	return &ChainQueryHandler{
+		chainBackend: newChainBackendForNetworks("blockchain-client-chain-query", networks),
+		allowlist:    allowlist,
+		resolver:     newChainQueryResolver(),
+	}
+}
+
+// newChainQueryResolver resolves methods from the compiled-in descriptors first, then through server reflection
+func newChainQueryResolver() chainquery.Resolver {
+	return chainquery.Resolvers{chainquery.NewRegistryResolver(), chainquery.NewReflectionResolver()}
+}
+
+// Handle processes the chain-query tool call
+func (h *ChainQueryHandler) Handle(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	// Create a logger with request context
+	logger := log.With().
+		Str("tool", "chain-query").
+		Str("request_id", fmt.Sprintf("%p", params)).
+		Logger()
+
+	start := time.Now()
+	logger.Info().Msg("Processing chain-query request")
+
+	// Define validation schema using Zog
+	schema := zog.Struct(zog.Shape{
+		"method":  zog.String().Required(),
+		"network": zog.String().Default(""),
+		"height":  zog.Int64().GTE(0).Default(0),
+		"format":  zog.String().OneOf(render.RecordFormats).Default(render.FormatJSON),
+	})
+
+	// Validate input parameters
+	var input ChainQueryInput
+	arguments := params.Arguments
+	if arguments == nil {
+		arguments = make(map[string]interface{})
+	}
+
+	logger.Debug().Interface("arguments", arguments).Msg("Validating input arguments")
+	// Parse and validate the arguments
+	errs := schema.Parse(arguments, &input)
+	if errs != nil {
+		logger.Error().Interface("errors", errs).Msg("Input validation failed")
+		return invalidInput(errs), nil
+	}
+
+	fullMethod, err := chainquery.FullMethod(input.Method)
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("method", "<package.Service>/<Method>", input.Method, err.Error()), nil
+	}
+	if !h.allowlist.Allows(fullMethod) {
+		logger.Error().Str("method", fullMethod).Msg("Method not allowlisted")
+		return invalidArgument("method", "allowlisted", input.Method, fmt.Sprintf(
+			"'%s' is not in the chain-query allowlist (allowed: %s)", fullMethod, strings.Join(h.allowlist.Entries(), ", "))), nil
+	}
+
+	// The request fields are passed through as JSON, decoded once the method's request type is known
+	request, received := argument(arguments, "request")
+	var rawRequest json.RawMessage
+	if received {
+		if _, ok := request.(map[string]interface{}); !ok {
+			return invalidArgument("request", "object", request, "request must be a JSON object of the method's request fields"), nil
+		}
+		if rawRequest, err = json.Marshal(request); err != nil {
+			return invalidArgument("request", "object", request, err.Error()), nil
+		}
+	}
+
+	target, err := h.resolve(input.Network)
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("network", "known network", input.Network, err.Error()), nil
+	}
+
+	logger.Debug().Interface("parsed_input", input).Msg("Input validation successful")
+
+	// Check if the gRPC connection is available
+	if target.conn == nil {
+		logger.Error().Msg("gRPC connection is not available")
+		return target.unavailableResult(), nil
+	}
+
+	logger.Info().
+		Str("network", target.network).
+		Int64("height", input.Height).
+		Str("method", fullMethod).
+		Msg("Calling chain query method")
+
+	// Resolve the method outside the circuit breaker so a mistyped name does not trip it
+	resolveCtx, cancel := context.WithTimeout(ctx, target.timeout)
+	method, err := h.resolver.FindMethod(resolveCtx, target.conn, fullMethod)
+	cancel()
+	if errors.Is(err, chainquery.ErrUnknownMethod) {
+		logger.Error().Err(err).Msg("Unknown method")
+		return invalidArgument("method", "served by the node", input.Method, err.Error()), nil
+	}
+	if err != nil {
+		return target.failureResult(logger, err), nil
+	}
+
+	in, err := chainquery.NewRequest(method, rawRequest)
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("request", string(method.Descriptor.Input().FullName()), request, err.Error()), nil
+	}
+
+	// Call the method using circuit breaker protection
+	result, info, err := target.invoke(ctx, input.Height, func(ctx context.Context, conn grpc.ClientConnInterface, opts ...grpc.CallOption) (interface{}, error) {
+		return chainquery.Invoke(ctx, conn, method, in, opts...)
+	})
+	if err != nil {
+		return target.failureResult(logger, err), nil
+	}
+
+	encoded, ok := result.(json.RawMessage)
+	if !ok {
+		return target.invalidResponseResult(logger), nil
+	}
+
+	// Log successful response
+	duration := time.Since(start)
+	logger.Info().
+		Str("method", fullMethod).
+		Int64("block_height", info.blockHeight).
+		Dur("duration", duration).
+		Msg("Successfully called chain query method")
+
+	toolResult, err := info.formattedResult(ChainQueryResponse{Method: fullMethod, Response: encoded}, input.Format)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to marshal response")
+		return nil, fmt.Errorf("failed to marshal chain query response: %w", err)
+	}
+
+	return toolResult, nil
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"overlock-mcp-server/pkg/chainquery"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// newChainQueryTestHandler serves the gRPC health service with reflection and allowlists it
+func newChainQueryTestHandler(t *testing.T, entries ...string) *ChainQueryHandler {
+	t.Helper()
+	conn := newBufconnConn(t, func(server *grpc.Server) {
+		healthServer := health.NewServer()
+		healthServer.SetServingStatus("overlock", healthpb.HealthCheckResponse_SERVING)
+		healthpb.RegisterHealthServer(server, healthServer)
+		reflection.Register(server)
+	})
+
+	allowlist, err := chainquery.ParseAllowlist(entries)
+	require.NoError(t, err)
+	return NewChainQueryHandler(conn, 5*time.Second, allowlist)
+}
+
+func TestChainQueryHandler_Handle(t *testing.T) {
+	handler := newChainQueryTestHandler(t, "grpc.health.v1.Health")
+
+	params := &mcp.CallToolParams{
+		Name: "chain-query",
+		Arguments: map[string]interface{}{
+			"method":  "/grpc.health.v1.Health/Check",
+			"request": map[string]interface{}{"service": "overlock"},
+		},
+	}
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "default", result.Meta["network"])
+
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+
+	var response struct {
+		Method   string            `json:"method"`
+		Response map[string]string `json:"response"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	assert.Equal(t, "grpc.health.v1.Health/Check", response.Method)
+	assert.Equal(t, map[string]string{"status": "SERVING"}, response.Response)
+}
+
+func TestChainQueryHandler_Handle_InvalidArguments(t *testing.T) {
+	handler := newChainQueryTestHandler(t, "grpc.health.v1.Health/Check", "grpc.health.v1.Health/Watch", "grpc.health.v1.Health/Nope")
+
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		field     string
+		hint      string
+	}{
+		{name: "missing method", arguments: map[string]interface{}{}, field: "method"},
+		{name: "malformed method", arguments: map[string]interface{}{"method": "Check"}, field: "method", hint: "expected <package.Service>/<Method>"},
+		{name: "not allowlisted", arguments: map[string]interface{}{"method": "grpc.health.v1.Health/List"}, field: "method", hint: "not in the chain-query allowlist"},
+		{name: "unknown method", arguments: map[string]interface{}{"method": "grpc.health.v1.Health/Nope"}, field: "method", hint: "unknown method"},
+		{name: "streaming method", arguments: map[string]interface{}{"method": "grpc.health.v1.Health/Watch"}, field: "request", hint: "streaming"},
+		{name: "request not an object", arguments: map[string]interface{}{"method": "grpc.health.v1.Health/Check", "request": "overlock"}, field: "request"},
+		{name: "unknown request field", arguments: map[string]interface{}{"method": "grpc.health.v1.Health/Check", "request": map[string]interface{}{"svc": "x"}}, field: "request", hint: "svc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := &mcp.CallToolParams{Name: "chain-query", Arguments: tt.arguments}
+
+			result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, params)
+
+			require.NoError(t, err)
+			failure := decodeValidation(t, result)
+			require.Len(t, failure.Problems, 1)
+			assert.Equal(t, tt.field, failure.Problems[0].Field)
+			assert.Contains(t, failure.Problems[0].Hint, tt.hint)
+		})
+	}
+}
+
+func TestChainQueryHandler_Handle_NoConnection(t *testing.T) {
+	allowlist, err := chainquery.ParseAllowlist(chainquery.DefaultAllowlist)
+	require.NoError(t, err)
+	handler := NewChainQueryHandler(nil, 5*time.Second, allowlist)
+
+	params := &mcp.CallToolParams{
+		Name:      "chain-query",
+		Arguments: map[string]interface{}{"method": "cosmos.bank.v1beta1.Query/Params"},
+	}
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "gRPC connection to blockchain is not available")
+}
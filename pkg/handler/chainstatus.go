@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"overlock-mcp-server/pkg/network"
+	"overlock-mcp-server/pkg/render"
+
+	"github.com/Oudwins/zog"
+	"github.com/cosmos/cosmos-sdk/client/grpc/cmtservice"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rs/zerolog/log"
+)
+
+// Statuses reported by the chain-status tool
+const (
+	chainStatusOK               = "ok"
+	chainStatusCatchingUp       = "catching_up"
+	chainStatusChainIDMismatch  = "chain_id_mismatch"
+	chainStatusRefused          = "refused"
+	chainStatusUnreachable      = "unreachable"
+	chainStatusDisconnected     = "disconnected"
+	chainStatusLatestBlockError = "latest_block_unavailable"
+)
+
+// ChainStatusInput represents the input parameters for the chain-status tool
+type ChainStatusInput struct {
+	Network string `json:"network,omitempty"`
+	Format  string `json:"format,omitempty"`
+}
+
+// ChainStatusResponse is the response of the chain-status tool
+type ChainStatusResponse struct {
+	Network               string `json:"network"`
+	Status                string `json:"status"`
+	ChainID               string `json:"chain_id,omitempty"`
+	ExpectedChainID       string `json:"expected_chain_id,omitempty"`
+	Moniker               string `json:"moniker,omitempty"`
+	NodeVersion           string `json:"node_version,omitempty"`
+	AppName               string `json:"app_name,omitempty"`
+	AppVersion            string `json:"app_version,omitempty"`
+	CosmosSDKVersion      string `json:"cosmos_sdk_version,omitempty"`
+	LatestBlockHeight     int64  `json:"latest_block_height,omitempty"`
+	LatestBlockTime       string `json:"latest_block_time,omitempty"`
+	LatestBlockAgeSeconds int64  `json:"latest_block_age_seconds,omitempty"`
+	CatchingUp            bool   `json:"catching_up"`
+	Error                 string `json:"error,omitempty"`
+}
+
+// ChainStatusHandler handles the chain-status tool requests.
+// It queries the node directly, bypassing the circuit breakers, so it can
+// report on networks that are failing or refused.
+type ChainStatusHandler struct {
+	networks *network.Registry
+}
+
+// NewChainStatusHandler creates a new chain status handler
+func NewChainStatusHandler(networks *network.Registry) *ChainStatusHandler {
+	return &ChainStatusHandler{
+		networks: networks,
+	}
+}
+
+// Handle processes the chain-status tool call
+func (h *ChainStatusHandler) Handle(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	// Create a logger with request context
+	logger := log.With().
+		Str("tool", "chain-status").
+		Str("request_id", fmt.Sprintf("%p", params)).
+		Logger()
+
+	start := time.Now()
+	logger.Info().Msg("Processing chain-status request")
+
+	// Define validation schema using Zog
+	schema := zog.Struct(zog.Shape{
+		"network": zog.String().Default(""),
+		"format":  zog.String().OneOf(render.RecordFormats).Default(render.FormatJSON),
+	})
+
+	// Validate input parameters
+	var input ChainStatusInput
+	arguments := params.Arguments
+	if arguments == nil {
+		arguments = make(map[string]interface{})
+	}
+
+	logger.Debug().Interface("arguments", arguments).Msg("Validating input arguments")
+	// Parse and validate the arguments
+	errs := schema.Parse(arguments, &input)
+	if errs != nil {
+		logger.Error().Interface("errors", errs).Msg("Input validation failed")
+		return invalidInput(errs), nil
+	}
+
+	n, err := h.networks.Get(input.Network)
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("network", "known network", input.Network, err.Error()), nil
+	}
+
+	logger.Info().Str("network", n.Name).Msg("Fetching chain status")
+
+	response := h.status(ctx, n)
+
+	logger.Info().
+		Str("status", response.Status).
+		Str("chain_id", response.ChainID).
+		Int64("block_height", response.LatestBlockHeight).
+		Dur("duration", time.Since(start)).
+		Msg("Successfully fetched chain status")
+
+	info := queryInfo{network: n.Name, blockHeight: response.LatestBlockHeight}
+	toolResult, err := info.formattedResult(response, input.Format)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to marshal response")
+		return nil, fmt.Errorf("failed to marshal chain status response: %w", err)
+	}
+	return toolResult, nil
+}
+
+// status asks the node for its identity, sync state and latest block
+func (h *ChainStatusHandler) status(ctx context.Context, n *network.Network) ChainStatusResponse {
+	response := ChainStatusResponse{
+		Network:         n.Name,
+		ExpectedChainID: n.ExpectedChainID,
+	}
+	if n.Conn == nil {
+		response.Status = chainStatusDisconnected
+		response.Error = "gRPC connection is not available"
+		return response
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.Timeout)
+	defer cancel()
+	client := cmtservice.NewServiceClient(n.Conn)
+
+	nodeInfo, err := client.GetNodeInfo(ctx, &cmtservice.GetNodeInfoRequest{})
+	if err != nil {
+		response.Status = chainStatusUnreachable
+		response.Error = err.Error()
+		return response
+	}
+	response.ChainID = nodeInfo.GetDefaultNodeInfo().GetNetwork()
+
+	// The node answered with its chain-id, so this call doubles as a verification:
+	// a wrong chain refuses the network, the expected one serves it again
+	mismatch := network.CheckChainID(n.ExpectedChainID, response.ChainID)
+	if mismatch != nil {
+		if n.Refuse(mismatch) {
+			log.Error().Err(mismatch).Str("network", n.Name).Msg("Refusing to serve network")
+		}
+	} else if n.ExpectedChainID != "" && n.Admit() {
+		log.Info().Str("network", n.Name).Str("chain_id", response.ChainID).Msg("Chain-id verified, serving network")
+	}
+	response.Moniker = nodeInfo.GetDefaultNodeInfo().GetMoniker()
+	response.NodeVersion = nodeInfo.GetDefaultNodeInfo().GetVersion()
+	response.AppName = nodeInfo.GetApplicationVersion().GetAppName()
+	response.AppVersion = nodeInfo.GetApplicationVersion().GetVersion()
+	response.CosmosSDKVersion = nodeInfo.GetApplicationVersion().GetCosmosSdkVersion()
+
+	syncing, err := client.GetSyncing(ctx, &cmtservice.GetSyncingRequest{})
+	if err != nil {
+		response.Status = chainStatusUnreachable
+		response.Error = err.Error()
+		return response
+	}
+	response.CatchingUp = syncing.GetSyncing()
+
+	latest, err := client.GetLatestBlock(ctx, &cmtservice.GetLatestBlockRequest{})
+	if err != nil {
+		response.Status = chainStatusLatestBlockError
+		response.Error = err.Error()
+	} else if height, blockTime, ok := latestBlock(latest); ok {
+		response.LatestBlockHeight = height
+		response.LatestBlockTime = blockTime.UTC().Format(time.RFC3339)
+		response.LatestBlockAgeSeconds = int64(time.Since(blockTime).Seconds())
+	}
+
+	// A wrong chain outranks every other status, followed by a network refused for another reason
+	if mismatch != nil {
+		response.Status = chainStatusChainIDMismatch
+		response.Error = mismatch.Error()
+	} else if reason := n.RefusedReason(); reason != nil {
+		response.Status = chainStatusRefused
+		response.Error = reason.Error()
+	} else if response.Status == "" && response.CatchingUp {
+		response.Status = chainStatusCatchingUp
+	} else if response.Status == "" {
+		response.Status = chainStatusOK
+	}
+	return response
+}
+
+// latestBlock reads the height and time of a latest block response, preferring the
+// sdk_block field over the deprecated block field older nodes fill instead
+func latestBlock(resp *cmtservice.GetLatestBlockResponse) (int64, time.Time, bool) {
+	if block := resp.GetSdkBlock(); block != nil {
+		return block.Header.Height, block.Header.Time, true
+	}
+	if block := resp.GetBlock(); block != nil {
+		return block.Header.Height, block.Header.Time, true
+	}
+	return 0, time.Time{}, false
+}
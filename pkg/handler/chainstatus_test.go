@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"overlock-mcp-server/pkg/network"
+
+	cmtp2p "github.com/cometbft/cometbft/proto/tendermint/p2p"
+	"github.com/cosmos/cosmos-sdk/client/grpc/cmtservice"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeCometService reports a fixed node identity, sync state and latest block
+type fakeCometService struct {
+	cmtservice.UnimplementedServiceServer
+	chainID   string
+	syncing   bool
+	blockTime time.Time
+}
+
+func (s *fakeCometService) GetNodeInfo(context.Context, *cmtservice.GetNodeInfoRequest) (*cmtservice.GetNodeInfoResponse, error) {
+	return &cmtservice.GetNodeInfoResponse{
+		DefaultNodeInfo:    &cmtp2p.DefaultNodeInfo{Network: s.chainID, Moniker: "validator-0", Version: "0.38.17"},
+		ApplicationVersion: &cmtservice.VersionInfo{AppName: "overlockd", Version: "v1.2.0", CosmosSdkVersion: "v0.50.12"},
+	}, nil
+}
+
+func (s *fakeCometService) GetSyncing(context.Context, *cmtservice.GetSyncingRequest) (*cmtservice.GetSyncingResponse, error) {
+	return &cmtservice.GetSyncingResponse{Syncing: s.syncing}, nil
+}
+
+func (s *fakeCometService) GetLatestBlock(context.Context, *cmtservice.GetLatestBlockRequest) (*cmtservice.GetLatestBlockResponse, error) {
+	if s.blockTime.IsZero() {
+		return nil, status.Error(codes.Unavailable, "block store is not ready")
+	}
+	return &cmtservice.GetLatestBlockResponse{
+		SdkBlock: &cmtservice.Block{Header: cmtservice.Header{ChainID: s.chainID, Height: 4321, Time: s.blockTime}},
+	}, nil
+}
+
+// newCometConn serves the fake service over an in-memory listener
+func newCometConn(t *testing.T, service *fakeCometService) *grpc.ClientConn {
+	t.Helper()
+	return newBufconnConn(t, func(server *grpc.Server) {
+		cmtservice.RegisterServiceServer(server, service)
+	})
+}
+
+func callChainStatus(t *testing.T, n *network.Network) (ChainStatusResponse, *mcp.CallToolResult) {
+	t.Helper()
+	handler := NewChainStatusHandler(network.NewRegistry(n.Name, n))
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{Name: "chain-status"})
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.False(t, result.IsError)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	var response ChainStatusResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response), textContent.Text)
+	return response, result
+}
+
+func TestChainStatusHandler_Handle(t *testing.T) {
+	blockTime := time.Now().Add(-3 * time.Second)
+	conn := newCometConn(t, &fakeCometService{chainID: "overlock-1", blockTime: blockTime})
+
+	response, result := callChainStatus(t, &network.Network{Name: "mainnet", Conn: conn, Timeout: 5 * time.Second, ExpectedChainID: "overlock-1"})
+
+	assert.Equal(t, "ok", response.Status)
+	assert.Equal(t, "mainnet", response.Network)
+	assert.Equal(t, "overlock-1", response.ChainID)
+	assert.Equal(t, "overlock-1", response.ExpectedChainID)
+	assert.Equal(t, "validator-0", response.Moniker)
+	assert.Equal(t, "0.38.17", response.NodeVersion)
+	assert.Equal(t, "overlockd", response.AppName)
+	assert.Equal(t, "v1.2.0", response.AppVersion)
+	assert.Equal(t, "v0.50.12", response.CosmosSDKVersion)
+	assert.Equal(t, int64(4321), response.LatestBlockHeight)
+	assert.Equal(t, blockTime.UTC().Format(time.RFC3339), response.LatestBlockTime)
+	assert.GreaterOrEqual(t, response.LatestBlockAgeSeconds, int64(2))
+	assert.False(t, response.CatchingUp)
+	assert.Empty(t, response.Error)
+	assert.Equal(t, int64(4321), result.Meta["block_height"])
+}
+
+func TestChainStatusHandler_Handle_Statuses(t *testing.T) {
+	tests := []struct {
+		name     string
+		service  *fakeCometService
+		expected string
+		refused  error
+		status   string
+		error    string
+		// whether the network is refused after the call
+		refusedAfter bool
+	}{
+		{name: "catching up", service: &fakeCometService{chainID: "overlock-1", syncing: true, blockTime: time.Now()}, status: "catching_up"},
+		{name: "chain-id mismatch", service: &fakeCometService{chainID: "overlock-testnet-3", blockTime: time.Now()}, expected: "overlock-1", status: "chain_id_mismatch", error: "node reports 'overlock-testnet-3', expected 'overlock-1'", refusedAfter: true},
+		{name: "unverified at startup, verified now", service: &fakeCometService{chainID: "overlock-1", blockTime: time.Now()}, expected: "overlock-1", refused: network.ErrChainIDUnverified, status: "ok"},
+		{name: "refused without an expected chain-id", service: &fakeCometService{chainID: "overlock-1", blockTime: time.Now()}, refused: errors.New("refused by the operator"), status: "refused", error: "refused by the operator", refusedAfter: true},
+		{name: "latest block unavailable", service: &fakeCometService{chainID: "overlock-1"}, status: "latest_block_unavailable", error: "block store is not ready"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := newCometConn(t, tt.service)
+			n := &network.Network{Name: "mainnet", Conn: conn, Timeout: 5 * time.Second, ExpectedChainID: tt.expected}
+			if tt.refused != nil {
+				n.Refuse(tt.refused)
+			}
+
+			response, _ := callChainStatus(t, n)
+
+			assert.Equal(t, tt.status, response.Status)
+			assert.Contains(t, response.Error, tt.error)
+			assert.Equal(t, tt.refusedAfter, n.RefusedReason() != nil)
+		})
+	}
+}
+
+func TestChainStatusHandler_Handle_Disconnected(t *testing.T) {
+	response, result := callChainStatus(t, &network.Network{Name: "mainnet", Timeout: 5 * time.Second})
+
+	assert.Equal(t, "disconnected", response.Status)
+	assert.Equal(t, "gRPC connection is not available", response.Error)
+	assert.NotContains(t, result.Meta, "block_height")
+}
+
+func TestChainStatusHandler_Handle_UnknownNetwork(t *testing.T) {
+	handler := NewChainStatusHandler(network.NewRegistry("mainnet", &network.Network{Name: "mainnet"}))
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "chain-status",
+		Arguments: map[string]interface{}{"network": "devnet"},
+	})
+
+	require.NoError(t, err)
+	failure := decodeValidation(t, result)
+	require.Len(t, failure.Problems, 1)
+	assert.Equal(t, "network", failure.Problems[0].Field)
+}
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"overlock-mcp-server/pkg/compare"
+	"overlock-mcp-server/pkg/network"
+
+	"github.com/Oudwins/zog"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxCompareIDs bounds the number of providers a single comparison may include
+const maxCompareIDs = 10
+
+// CompareInput represents the input parameters for the compare-providers tool
+type CompareInput struct {
+	Ids     []int  `json:"ids,omitempty"`
+	Network string `json:"network,omitempty"`
+	Height  int64  `json:"height,omitempty"`
+	Source  string `json:"source,omitempty"`
+}
+
+// CompareResponse is the response of the compare-providers tool
+type CompareResponse struct {
+	compare.Comparison
+	NotFound  []uint64 `json:"not_found"`
+	Truncated bool     `json:"truncated,omitempty"`
+}
+
+// CompareHandler handles the compare-providers tool requests
+type CompareHandler struct {
+	chainBackend
+}
+
+// NewCompareHandler creates a new provider comparison handler
+func NewCompareHandler(chainClient overlockv1beta1.QueryClient, timeout time.Duration) *CompareHandler {
+	return &CompareHandler{
+		chainBackend: newChainBackend("blockchain-client-compare", chainClient, timeout),
+	}
+}
+
+// NewCompareHandlerForNetworks creates a provider comparison handler serving every configured network
+func NewCompareHandlerForNetworks(networks *network.Registry) *CompareHandler {
+	return &CompareHandler{
+		chainBackend: newChainBackendForNetworks("blockchain-client-compare", networks),
+	}
+}
+
+// Handle processes the compare-providers tool call
+func (h *CompareHandler) Handle(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	// Create a logger with request context
+	logger := log.With().
+		Str("tool", "compare-providers").
+		Str("request_id", fmt.Sprintf("%p", params)).
+		Logger()
+
+	start := time.Now()
+	logger.Info().Msg("Processing compare-providers request")
+
+	// Define validation schema using Zog
+	schema := zog.Struct(zog.Shape{
+		"ids":     zog.Slice(zog.Int().GTE(1)).Required().Min(2).Max(maxCompareIDs),
+		"network": zog.String().Default(""),
+		"height":  zog.Int64().GTE(0).Default(0),
+		"source":  zog.String().OneOf([]string{sourceChain, sourceIndex}).Default(sourceChain),
+	})
+
+	// Validate input parameters
+	var input CompareInput
+	arguments := params.Arguments
+	if arguments == nil {
+		arguments = make(map[string]interface{})
+	}
+
+	logger.Debug().Interface("arguments", arguments).Msg("Validating input arguments")
+	// Parse and validate the arguments
+	errs := schema.Parse(arguments, &input)
+	if errs != nil {
+		logger.Error().Interface("errors", errs).Msg("Input validation failed")
+		return invalidInput(errs), nil
+	}
+	ids := uniqueIDs(input.Ids)
+	if len(ids) < 2 {
+		logger.Error().Msg("Fewer than two distinct IDs")
+		return invalidArgument("ids", "min_distinct 2", input.Ids, "at least two distinct provider IDs are required"), nil
+	}
+
+	target, err := h.resolve(input.Network)
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("network", "known network", input.Network, err.Error()), nil
+	}
+
+	logger.Info().
+		Str("network", target.network).
+		Int64("height", input.Height).
+		Interface("ids", ids).
+		Msg("Comparing providers")
+
+	response := CompareResponse{NotFound: []uint64{}}
+	var providers []overlockv1beta1.Provider
+	var environments []overlockv1beta1.Environment
+	var info queryInfo
+	if input.Source == sourceIndex {
+		snapshot, indexInfo, unavailable := target.snapshot(logger)
+		if unavailable != nil {
+			return unavailable, nil
+		}
+		info = indexInfo
+		for _, id := range ids {
+			provider, ok := snapshot.Provider(id)
+			if !ok {
+				response.NotFound = append(response.NotFound, id)
+				continue
+			}
+			providers = append(providers, provider)
+		}
+		environments = snapshot.Environments
+	} else {
+		// Check if chain client is available
+		if target.chainClient == nil {
+			logger.Error().Msg("gRPC client is not available")
+			return target.unavailableResult(), nil
+		}
+		outcomes := runBatch(ctx, target, ids, input.Height, lookupProvider)
+		for _, outcome := range outcomes {
+			switch {
+			case outcome.err != nil && status.Code(outcome.err) == codes.NotFound:
+				response.NotFound = append(response.NotFound, outcome.id)
+			case outcome.err != nil:
+				return target.failureResult(logger, outcome.err), nil
+			case outcome.record == nil:
+				response.NotFound = append(response.NotFound, outcome.id)
+			default:
+				providers = append(providers, *outcome.record.(*overlockv1beta1.Provider))
+			}
+		}
+		info = mergeBatchInfo(target.info(), outcomes)
+
+		// Count hosted environments at the height the providers were read at
+		environments, response.Truncated, _, err = target.listEnvironments(ctx, info.blockHeight, "", fullListMaxPages)
+		if err != nil {
+			return target.failureResult(logger, err), nil
+		}
+	}
+
+	hosted := make(map[uint64]int)
+	for _, environment := range environments {
+		hosted[environment.Provider]++
+	}
+	response.Comparison = compare.Providers(providers, hosted, time.Now())
+
+	logger.Info().
+		Int("compared", len(providers)).
+		Int("not_found", len(response.NotFound)).
+		Int("differing", len(response.Differing)).
+		Int64("block_height", info.blockHeight).
+		Dur("duration", time.Since(start)).
+		Msg("Successfully compared providers")
+
+	toolResult, err := info.jsonResult(response)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to marshal response")
+		return nil, fmt.Errorf("failed to marshal comparison response: %w", err)
+	}
+	return toolResult, nil
+}
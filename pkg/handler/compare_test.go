@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"overlock-mcp-server/pkg/indexer"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func decodeComparison(t *testing.T, result *mcp.CallToolResult) CompareResponse {
+	require.NotNil(t, result)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	var response CompareResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	return response
+}
+
+func TestCompareHandler_Handle(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewCompareHandler(mockClient, 30*time.Second)
+
+	mockClient.On("ShowProvider", mock.AnythingOfType("*context.timerCtx"), &overlockv1beta1.QueryShowProviderRequest{Id: 1}).
+		Return(&overlockv1beta1.QueryShowProviderResponse{Provider: &overlockv1beta1.Provider{Id: 1, CountryCode: "DE", Availability: "available"}}, nil)
+	mockClient.On("ShowProvider", mock.AnythingOfType("*context.timerCtx"), &overlockv1beta1.QueryShowProviderRequest{Id: 2}).
+		Return(&overlockv1beta1.QueryShowProviderResponse{Provider: &overlockv1beta1.Provider{Id: 2, CountryCode: "FR", Availability: "available"}}, nil)
+	mockClient.On("ShowProvider", mock.AnythingOfType("*context.timerCtx"), &overlockv1beta1.QueryShowProviderRequest{Id: 3}).
+		Return(&overlockv1beta1.QueryShowProviderResponse{}, status.Error(codes.NotFound, "provider not found"))
+	mockClient.On("ListEnvironment", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryListEnvironmentResponse{
+		Environments: []overlockv1beta1.Environment{{Id: 1001, Provider: 1}, {Id: 1002, Provider: 1}, {Id: 1003, Provider: 9}},
+		Pagination:   &query.PageResponse{},
+	}, nil)
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "compare-providers",
+		Arguments: map[string]interface{}{"ids": []interface{}{1, 2, 3}},
+	})
+
+	require.NoError(t, err)
+	response := decodeComparison(t, result)
+	assert.Equal(t, []uint64{1, 2}, response.Providers)
+	assert.Equal(t, []uint64{3}, response.NotFound)
+	assert.Equal(t, []string{"country_code", "hosted_environments"}, response.Differing)
+	for _, field := range response.Fields {
+		if field.Name == "hosted_environments" {
+			assert.Equal(t, []interface{}{2.0, 0.0}, field.Values)
+		}
+	}
+}
+
+func TestCompareHandler_Handle_FromIndex(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewCompareHandler(mockClient, 30*time.Second)
+	handler.AttachIndexers(map[string]*indexer.Indexer{"default": newSyncedIndexer(t)})
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "compare-providers",
+		Arguments: map[string]interface{}{"ids": []interface{}{1, 2}, "source": "index"},
+	})
+
+	require.NoError(t, err)
+	response := decodeComparison(t, result)
+	assert.Equal(t, []uint64{1, 2}, response.Providers)
+	assert.Equal(t, []string{"creator", "hosted_environments"}, response.Differing)
+	mockClient.AssertNotCalled(t, "ShowProvider", mock.Anything, mock.Anything)
+}
+
+func TestCompareHandler_Handle_LookupError(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewCompareHandler(mockClient, 30*time.Second)
+
+	mockClient.On("ShowProvider", mock.AnythingOfType("*context.timerCtx"), mock.Anything).
+		Return(&overlockv1beta1.QueryShowProviderResponse{}, errors.New("connection reset"))
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "compare-providers",
+		Arguments: map[string]interface{}{"ids": []interface{}{1, 2}},
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "Unable to connect to blockchain service")
+	mockClient.AssertNotCalled(t, "ListEnvironment", mock.Anything, mock.Anything)
+}
+
+func TestCompareHandler_Handle_Validation(t *testing.T) {
+	handler := NewCompareHandler(&MockQueryClient{}, 30*time.Second)
+
+	for name, ids := range map[string][]interface{}{
+		"single id":       {1},
+		"duplicate ids":   {1, 1},
+		"too many ids":    {1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+		"non-positive id": {0, 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+				Name:      "compare-providers",
+				Arguments: map[string]interface{}{"ids": ids},
+			})
+			require.NoError(t, err)
+			assert.Contains(t, decodeValidation(t, result).Error, "validation failed")
+		})
+	}
+}
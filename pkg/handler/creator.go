@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"overlock-mcp-server/pkg/network"
+
+	"github.com/Oudwins/zog"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/rs/zerolog/log"
+)
+
+// AddressPrefix is the bech32 human-readable part of Overlock account addresses
+const AddressPrefix = "overlock"
+
+// creatorMaxPages bounds the number of pages read per record type for a single creator
+const creatorMaxPages = 50
+
+// unspecified is the summary bucket of records that leave a field empty
+const unspecified = "unspecified"
+
+// CreatorInput represents the input parameters for the show-creator tool
+type CreatorInput struct {
+	Creator string `json:"creator,omitempty"`
+	Network string `json:"network,omitempty"`
+	Height  int64  `json:"height,omitempty"`
+	Source  string `json:"source,omitempty"`
+	Expand  bool   `json:"expand,omitempty"`
+}
+
+// CreatorSummary counts a creator's records
+type CreatorSummary struct {
+	ProviderCount              int            `json:"provider_count"`
+	EnvironmentCount           int            `json:"environment_count"`
+	ProvidersByAvailability    map[string]int `json:"providers_by_availability"`
+	ProvidersByCountry         map[string]int `json:"providers_by_country"`
+	ProvidersByEnvironmentType map[string]int `json:"providers_by_environment_type"`
+	EnvironmentsByProvider     map[string]int `json:"environments_by_provider"`
+}
+
+// CreatorResponse is the response of the show-creator tool. Environments carry their
+// expansion only when expand is set.
+type CreatorResponse struct {
+	Creator      string                     `json:"creator"`
+	Summary      CreatorSummary             `json:"summary"`
+	Providers    []overlockv1beta1.Provider `json:"providers"`
+	Environments []*ExpandedEnvironment     `json:"environments"`
+	Truncated    bool                       `json:"truncated,omitempty"`
+}
+
+// CreatorHandler handles the show-creator tool requests
+type CreatorHandler struct {
+	chainBackend
+}
+
+// NewCreatorHandler creates a new creator handler
+func NewCreatorHandler(chainClient overlockv1beta1.QueryClient, timeout time.Duration) *CreatorHandler {
+	return &CreatorHandler{
+		chainBackend: newChainBackend("blockchain-client-creator", chainClient, timeout),
+	}
+}
+
+// NewCreatorHandlerForNetworks creates a creator handler serving every configured network
+func NewCreatorHandlerForNetworks(networks *network.Registry) *CreatorHandler {
+	return &CreatorHandler{
+		chainBackend: newChainBackendForNetworks("blockchain-client-creator", networks),
+	}
+}
+
+// Handle processes the show-creator tool call
+func (h *CreatorHandler) Handle(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	// Create a logger with request context
+	logger := log.With().
+		Str("tool", "show-creator").
+		Str("request_id", fmt.Sprintf("%p", params)).
+		Logger()
+
+	start := time.Now()
+	logger.Info().Msg("Processing show-creator request")
+
+	// Define validation schema using Zog
+	schema := zog.Struct(zog.Shape{
+		"creator": zog.String().Required(),
+		"network": zog.String().Default(""),
+		"height":  zog.Int64().GTE(0).Default(0),
+		"source":  zog.String().OneOf([]string{sourceChain, sourceIndex}).Default(sourceChain),
+		"expand":  zog.Bool().Default(false),
+	})
+
+	// Validate input parameters
+	var input CreatorInput
+	arguments := params.Arguments
+	if arguments == nil {
+		arguments = make(map[string]interface{})
+	}
+
+	logger.Debug().Interface("arguments", arguments).Msg("Validating input arguments")
+	// Parse and validate the arguments
+	errs := schema.Parse(arguments, &input)
+	if errs != nil {
+		logger.Error().Interface("errors", errs).Msg("Input validation failed")
+		return invalidInput(errs), nil
+	}
+
+	if err := ValidateAddress(input.Creator); err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("creator", "bech32_address", input.Creator, err.Error()), nil
+	}
+
+	target, err := h.resolve(input.Network)
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("network", "known network", input.Network, err.Error()), nil
+	}
+
+	logger.Info().
+		Str("network", target.network).
+		Int64("height", input.Height).
+		Str("creator", input.Creator).
+		Msg("Fetching creator portfolio")
+
+	response := &CreatorResponse{Creator: input.Creator}
+	var environments []overlockv1beta1.Environment
+	var info queryInfo
+	var related relatedRecords
+	if input.Source == sourceIndex {
+		snapshot, indexInfo, unavailable := target.snapshot(logger)
+		if unavailable != nil {
+			return unavailable, nil
+		}
+		info = indexInfo
+		related = snapshotRecords{snapshot: snapshot}
+		for _, provider := range snapshot.Providers {
+			if provider.Creator == input.Creator {
+				response.Providers = append(response.Providers, provider)
+			}
+		}
+		for _, environment := range snapshot.Environments {
+			if environment.Creator == input.Creator {
+				environments = append(environments, environment)
+			}
+		}
+	} else {
+		// Check if chain client is available
+		if target.chainClient == nil {
+			logger.Error().Msg("gRPC client is not available")
+			return target.unavailableResult(), nil
+		}
+		var portfolio listing
+		portfolio, info, err = target.listAll(ctx, input.Height, input.Creator, creatorMaxPages)
+		if err != nil {
+			return target.failureResult(logger, err), nil
+		}
+		related = chainRecords{target: target, height: info.blockHeight}
+		response.Providers = portfolio.providers
+		environments = portfolio.environments
+		response.Truncated = portfolio.truncated
+	}
+
+	if response.Providers == nil {
+		response.Providers = []overlockv1beta1.Provider{}
+	}
+	response.Summary = summarizeCreator(response.Providers, environments)
+	response.Environments = creatorEnvironments(ctx, related, environments, input.Expand)
+
+	logger.Info().
+		Int("provider_count", len(response.Providers)).
+		Int("environment_count", len(response.Environments)).
+		Bool("truncated", response.Truncated).
+		Int64("block_height", info.blockHeight).
+		Dur("duration", time.Since(start)).
+		Msg("Successfully fetched creator portfolio")
+
+	toolResult, err := info.jsonResult(response)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to marshal response")
+		return nil, fmt.Errorf("failed to marshal creator response: %w", err)
+	}
+	return toolResult, nil
+}
+
+// creatorEnvironments returns the creator's environments, with their related records
+// inlined when expand is set
+func creatorEnvironments(ctx context.Context, related relatedRecords, environments []overlockv1beta1.Environment, expand bool) []*ExpandedEnvironment {
+	records := make([]*overlockv1beta1.Environment, len(environments))
+	for i := range environments {
+		records[i] = &environments[i]
+	}
+	if expand {
+		return expandEnvironments(ctx, related, records)
+	}
+	plain := make([]*ExpandedEnvironment, len(records))
+	for i, environment := range records {
+		plain[i] = &ExpandedEnvironment{Environment: environment}
+	}
+	return plain
+}
+
+// summarizeCreator counts a creator's providers by availability, country and environment
+// type, and its environments by provider
+func summarizeCreator(providers []overlockv1beta1.Provider, environments []overlockv1beta1.Environment) CreatorSummary {
+	summary := CreatorSummary{
+		ProviderCount:              len(providers),
+		EnvironmentCount:           len(environments),
+		ProvidersByAvailability:    make(map[string]int),
+		ProvidersByCountry:         make(map[string]int),
+		ProvidersByEnvironmentType: make(map[string]int),
+		EnvironmentsByProvider:     make(map[string]int),
+	}
+	for _, provider := range providers {
+		summary.ProvidersByAvailability[bucket(provider.Availability)]++
+		summary.ProvidersByCountry[bucket(provider.CountryCode)]++
+		summary.ProvidersByEnvironmentType[bucket(provider.EnvironmentType)]++
+	}
+	for _, environment := range environments {
+		summary.EnvironmentsByProvider[fmt.Sprintf("%d", environment.Provider)]++
+	}
+	return summary
+}
+
+// bucket returns the summary bucket of a field value
+func bucket(value string) string {
+	if value == "" {
+		return unspecified
+	}
+	return value
+}
+
+// ValidateAddress checks that address is a bech32 encoded Overlock account address
+func ValidateAddress(address string) error {
+	prefix, data, err := bech32.DecodeAndConvert(address)
+	if err != nil {
+		return fmt.Errorf("invalid address '%s': %v", address, err)
+	}
+	if prefix != AddressPrefix {
+		return fmt.Errorf("invalid address '%s': expected prefix '%s', got '%s'", address, AddressPrefix, prefix)
+	}
+	if len(data) != 20 && len(data) != 32 {
+		return fmt.Errorf("invalid address '%s': unexpected length %d", address, len(data))
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"overlock-mcp-server/pkg/indexer"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testAddress returns a valid bech32 address with the given prefix
+func testAddress(t *testing.T, prefix string, fill byte) string {
+	address, err := bech32.ConvertAndEncode(prefix, bytes.Repeat([]byte{fill}, 20))
+	require.NoError(t, err)
+	return address
+}
+
+func decodeCreator(t *testing.T, result *mcp.CallToolResult) CreatorResponse {
+	require.NotNil(t, result)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	var response CreatorResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	return response
+}
+
+func TestCreatorHandler_Handle(t *testing.T) {
+	creator := testAddress(t, AddressPrefix, 1)
+	mockClient := &MockQueryClient{}
+	handler := NewCreatorHandler(mockClient, 30*time.Second)
+
+	// Providers span two pages
+	mockClient.On("ListProvider", mock.Anything, mock.MatchedBy(func(req *overlockv1beta1.QueryListProviderRequest) bool {
+		return req.Creator.GetValue() == creator && req.Pagination.Key == nil
+	})).Return(&overlockv1beta1.QueryListProviderResponse{
+		Providers: []overlockv1beta1.Provider{
+			{Id: 1, Creator: creator, Availability: "online", CountryCode: "DE", EnvironmentType: "kubernetes"},
+		},
+		Pagination: &query.PageResponse{NextKey: []byte("next")},
+	}, nil).Once()
+	mockClient.On("ListProvider", mock.Anything, mock.MatchedBy(func(req *overlockv1beta1.QueryListProviderRequest) bool {
+		return bytes.Equal(req.Pagination.Key, []byte("next"))
+	})).Return(&overlockv1beta1.QueryListProviderResponse{
+		Providers: []overlockv1beta1.Provider{
+			{Id: 2, Creator: creator, Availability: "online", CountryCode: "US"},
+		},
+		Pagination: &query.PageResponse{},
+	}, nil).Once()
+	mockClient.On("ListEnvironment", mock.Anything, mock.MatchedBy(func(req *overlockv1beta1.QueryListEnvironmentRequest) bool {
+		return req.Creator == creator
+	})).Return(&overlockv1beta1.QueryListEnvironmentResponse{
+		Environments: []overlockv1beta1.Environment{
+			{Id: 1001, Creator: creator, Provider: 1},
+			{Id: 1002, Creator: creator, Provider: 1},
+		},
+		Pagination: &query.PageResponse{},
+	}, nil).Once()
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "show-creator",
+		Arguments: map[string]interface{}{"creator": creator},
+	})
+
+	require.NoError(t, err)
+	response := decodeCreator(t, result)
+	assert.Equal(t, creator, response.Creator)
+	require.Len(t, response.Providers, 2)
+	require.Len(t, response.Environments, 2)
+	assert.Nil(t, response.Environments[0].Expanded)
+	assert.False(t, response.Truncated)
+
+	summary := response.Summary
+	assert.Equal(t, 2, summary.ProviderCount)
+	assert.Equal(t, 2, summary.EnvironmentCount)
+	assert.Equal(t, map[string]int{"online": 2}, summary.ProvidersByAvailability)
+	assert.Equal(t, map[string]int{"DE": 1, "US": 1}, summary.ProvidersByCountry)
+	assert.Equal(t, map[string]int{"kubernetes": 1, unspecified: 1}, summary.ProvidersByEnvironmentType)
+	assert.Equal(t, map[string]int{"1": 2}, summary.EnvironmentsByProvider)
+	mockClient.AssertExpectations(t)
+}
+
+func TestCreatorHandler_Handle_InvalidAddress(t *testing.T) {
+	handler := NewCreatorHandler(&MockQueryClient{}, 30*time.Second)
+
+	for name, arguments := range map[string]map[string]interface{}{
+		"missing creator": {},
+		"not bech32":      {"creator": "overlock1alice"},
+		"wrong prefix":    {"creator": testAddress(t, "cosmos", 1)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+				Name:      "show-creator",
+				Arguments: arguments,
+			})
+			require.NoError(t, err)
+			assert.Contains(t, decodeValidation(t, result).Error, "validation failed")
+		})
+	}
+}
+
+func TestCreatorHandler_Handle_FromIndex(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewCreatorHandler(mockClient, 30*time.Second)
+
+	creator := testAddress(t, AddressPrefix, 2)
+	indexClient := &MockQueryClient{}
+	indexClient.On("ListProvider", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryListProviderResponse{
+		Providers: []overlockv1beta1.Provider{
+			{Id: 1, Creator: creator, Availability: "offline"},
+			{Id: 2, Creator: testAddress(t, AddressPrefix, 3)},
+		},
+		Pagination: &query.PageResponse{},
+	}, nil)
+	indexClient.On("ListEnvironment", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryListEnvironmentResponse{
+		Pagination: &query.PageResponse{},
+	}, nil)
+	idx := indexer.New("default", indexClient, indexer.Options{})
+	require.NoError(t, idx.Sync(context.Background()))
+	handler.AttachIndexers(map[string]*indexer.Indexer{"default": idx})
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "show-creator",
+		Arguments: map[string]interface{}{"creator": creator, "source": "index"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "index", result.Meta["source"])
+	response := decodeCreator(t, result)
+	require.Len(t, response.Providers, 1)
+	assert.Empty(t, response.Environments)
+	assert.Equal(t, map[string]int{"offline": 1}, response.Summary.ProvidersByAvailability)
+	mockClient.AssertNotCalled(t, "ListProvider", mock.Anything, mock.Anything)
+}
+
+func TestCreatorHandler_Handle_Expand(t *testing.T) {
+	handler := NewCreatorHandler(&MockQueryClient{}, 30*time.Second)
+
+	creator := testAddress(t, AddressPrefix, 2)
+	indexClient := &MockQueryClient{}
+	indexClient.On("ListProvider", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryListProviderResponse{
+		Providers:  []overlockv1beta1.Provider{{Id: 1, Creator: testAddress(t, AddressPrefix, 3)}},
+		Pagination: &query.PageResponse{},
+	}, nil)
+	indexClient.On("ListEnvironment", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryListEnvironmentResponse{
+		Environments: []overlockv1beta1.Environment{
+			{Id: 1001, Creator: creator, Provider: 1},
+			{Id: 1002, Creator: creator, Provider: 9},
+		},
+		Pagination: &query.PageResponse{},
+	}, nil)
+	idx := indexer.New("default", indexClient, indexer.Options{})
+	require.NoError(t, idx.Sync(context.Background()))
+	handler.AttachIndexers(map[string]*indexer.Indexer{"default": idx})
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "show-creator",
+		Arguments: map[string]interface{}{"creator": creator, "source": "index", "expand": true},
+	})
+
+	require.NoError(t, err)
+	response := decodeCreator(t, result)
+	require.Len(t, response.Environments, 2)
+	require.NotNil(t, response.Environments[0].Expanded)
+	assert.Equal(t, providerStatusFound, response.Environments[0].Expanded.ProviderStatus)
+	assert.Equal(t, uint64(1), response.Environments[0].Expanded.Provider.Id)
+	assert.Equal(t, uint64(1), *response.Environments[0].Expanded.CreatorOtherEnvironments)
+	assert.Equal(t, providerStatusNotFound, response.Environments[1].Expanded.ProviderStatus)
+	assert.Equal(t, map[string]int{"1": 1, "9": 1}, response.Summary.EnvironmentsByProvider)
+}
+
+func TestValidateAddress(t *testing.T) {
+	assert.NoError(t, ValidateAddress(testAddress(t, AddressPrefix, 1)))
+	assert.Error(t, ValidateAddress(""))
+	assert.Error(t, ValidateAddress("overlock1alice"))
+	assert.ErrorContains(t, ValidateAddress(testAddress(t, "cosmos", 1)), "expected prefix 'overlock'")
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"overlock-mcp-server/pkg/diagnose"
+	"overlock-mcp-server/pkg/network"
+
+	"github.com/Oudwins/zog"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DiagnoseInput represents the input parameters for the diagnose-environment tool
+type DiagnoseInput struct {
+	Id           int    `json:"id,omitempty"`
+	TcpTimeoutMs int    `json:"tcp_timeout_ms,omitempty" zog:"tcp_timeout_ms"`
+	Network      string `json:"network,omitempty"`
+	Height       int64  `json:"height,omitempty"`
+	Source       string `json:"source,omitempty"`
+}
+
+// DiagnoseHandler handles the diagnose-environment tool requests
+type DiagnoseHandler struct {
+	chainBackend
+	prober func(timeout time.Duration) diagnose.Prober
+}
+
+// NewDiagnoseHandler creates a new environment diagnosis handler
+func NewDiagnoseHandler(chainClient overlockv1beta1.QueryClient, timeout time.Duration) *DiagnoseHandler {
+	return &DiagnoseHandler{
+		chainBackend: newChainBackend("blockchain-client-diagnose", chainClient, timeout),
+		prober:       diagnose.PublicTCPProber,
+	}
+}
+
+// NewDiagnoseHandlerForNetworks creates an environment diagnosis handler serving every configured network
+func NewDiagnoseHandlerForNetworks(networks *network.Registry) *DiagnoseHandler {
+	return &DiagnoseHandler{
+		chainBackend: newChainBackendForNetworks("blockchain-client-diagnose", networks),
+		prober:       diagnose.PublicTCPProber,
+	}
+}
+
+// AllowPrivateProbes lets the reachability check connect to loopback, private and link-local endpoints
+func (h *DiagnoseHandler) AllowPrivateProbes() {
+	h.prober = diagnose.TCPProber
+}
+
+// Handle processes the diagnose-environment tool call
+func (h *DiagnoseHandler) Handle(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	// Create a logger with request context
+	logger := log.With().
+		Str("tool", "diagnose-environment").
+		Str("request_id", fmt.Sprintf("%p", params)).
+		Logger()
+
+	start := time.Now()
+	logger.Info().Msg("Processing diagnose-environment request")
+
+	// Define validation schema using Zog
+	schema := zog.Struct(zog.Shape{
+		"id":           zog.Int().GTE(1),
+		"tcpTimeoutMs": zog.Int().GTE(100).LTE(10000).Default(3000),
+		"network":      zog.String().Default(""),
+		"height":       zog.Int64().GTE(0).Default(0),
+		"source":       zog.String().OneOf([]string{sourceChain, sourceIndex}).Default(sourceChain),
+	})
+
+	// Validate input parameters
+	var input DiagnoseInput
+	arguments := params.Arguments
+	if arguments == nil {
+		arguments = make(map[string]interface{})
+	}
+
+	logger.Debug().Interface("arguments", arguments).Msg("Validating input arguments")
+	// Parse and validate the arguments
+	errs := schema.Parse(arguments, &input)
+	if errs != nil {
+		logger.Error().Interface("errors", errs).Msg("Input validation failed")
+		return invalidInput(errs), nil
+	}
+
+	// Check if ID was provided (required field)
+	if input.Id == 0 {
+		logger.Error().Msg("Environment ID is required")
+		return requiredArgument("id", "environment ID is required"), nil
+	}
+
+	target, err := h.resolve(input.Network)
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("network", "known network", input.Network, err.Error()), nil
+	}
+
+	id := uint64(input.Id)
+	logger.Info().
+		Str("network", target.network).
+		Int64("height", input.Height).
+		Uint64("environment_id", id).
+		Msg("Diagnosing environment")
+
+	var info queryInfo
+	var environment diagnose.Lookup[overlockv1beta1.Environment]
+	var provider func() diagnose.Lookup[overlockv1beta1.Provider]
+	if input.Source == sourceIndex {
+		snapshot, indexInfo, unavailable := target.snapshot(logger)
+		if unavailable != nil {
+			return unavailable, nil
+		}
+		info = indexInfo
+		if record, ok := snapshot.Environment(id); ok {
+			environment.Record = &record
+		}
+		provider = func() diagnose.Lookup[overlockv1beta1.Provider] {
+			record, ok := snapshot.Provider(environment.Record.Provider)
+			if !ok {
+				return diagnose.Lookup[overlockv1beta1.Provider]{}
+			}
+			return diagnose.Lookup[overlockv1beta1.Provider]{Record: &record}
+		}
+	} else {
+		// Check if chain client is available
+		if target.chainClient == nil {
+			logger.Error().Msg("gRPC client is not available")
+			return target.unavailableResult(), nil
+		}
+		record, envInfo, err := lookupEnvironment(ctx, target, id, input.Height)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return target.failureResult(logger, err), nil
+		}
+		info = envInfo
+		environment.Record, _ = record.(*overlockv1beta1.Environment)
+		provider = func() diagnose.Lookup[overlockv1beta1.Provider] {
+			// Resolve the provider at the height the environment was read at
+			record, _, err := lookupProvider(ctx, target, environment.Record.Provider, info.blockHeight)
+			if err != nil && status.Code(err) != codes.NotFound {
+				return diagnose.Lookup[overlockv1beta1.Provider]{Err: err}
+			}
+			found, _ := record.(*overlockv1beta1.Provider)
+			return diagnose.Lookup[overlockv1beta1.Provider]{Record: found}
+		}
+	}
+
+	report := diagnose.Environment(ctx, id, environment, provider, h.prober(time.Duration(input.TcpTimeoutMs)*time.Millisecond))
+
+	logger.Info().
+		Str("status", string(report.Status)).
+		Int64("block_height", info.blockHeight).
+		Dur("duration", time.Since(start)).
+		Msg("Successfully diagnosed environment")
+
+	toolResult, err := info.jsonResult(report)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to marshal response")
+		return nil, fmt.Errorf("failed to marshal diagnosis response: %w", err)
+	}
+	return toolResult, nil
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"overlock-mcp-server/pkg/diagnose"
+	"overlock-mcp-server/pkg/indexer"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func decodeDiagnosis(t *testing.T, result *mcp.CallToolResult) diagnose.Report {
+	require.NotNil(t, result)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	var report diagnose.Report
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &report))
+	return report
+}
+
+func TestDiagnoseHandler_Handle(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewDiagnoseHandler(mockClient, 30*time.Second)
+	var probedAddress string
+	var probeTimeout time.Duration
+	handler.prober = func(timeout time.Duration) diagnose.Prober {
+		probeTimeout = timeout
+		return func(ctx context.Context, address string) (time.Duration, error) {
+			probedAddress = address
+			return 0, errors.New("connection refused")
+		}
+	}
+
+	mockClient.On("ShowEnvironment", mock.AnythingOfType("*context.timerCtx"), &overlockv1beta1.QueryShowEnvironmentRequest{Id: 7}).
+		Return(&overlockv1beta1.QueryShowEnvironmentResponse{Environment: &overlockv1beta1.Environment{
+			Id: 7, Provider: 3, Metadata: &overlockv1beta1.Metadata{Name: "web"},
+		}}, nil)
+	mockClient.On("ShowProvider", mock.AnythingOfType("*context.timerCtx"), &overlockv1beta1.QueryShowProviderRequest{Id: 3}).
+		Return(&overlockv1beta1.QueryShowProviderResponse{Provider: &overlockv1beta1.Provider{
+			Id: 3, Availability: "available", Ip: "203.0.113.10", Port: 8080,
+		}}, nil)
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "diagnose-environment",
+		Arguments: map[string]interface{}{"id": 7, "tcp_timeout_ms": 500},
+	})
+
+	require.NoError(t, err)
+	report := decodeDiagnosis(t, result)
+	assert.Equal(t, diagnose.StatusFail, report.Status)
+	assert.Equal(t, "203.0.113.10:8080", probedAddress)
+	assert.Equal(t, 500*time.Millisecond, probeTimeout)
+	require.Len(t, report.Checks, 7)
+	assert.Equal(t, diagnose.CheckReachability, report.Checks[6].Name)
+	assert.Equal(t, diagnose.StatusFail, report.Checks[6].Status)
+	assert.Equal(t, "Environment 7 has problems. TCP connection to 203.0.113.10:8080 failed: connection refused.", report.Summary)
+}
+
+func TestDiagnoseHandler_Handle_PrivateEndpointNotProbed(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewDiagnoseHandler(mockClient, 30*time.Second)
+
+	mockClient.On("ShowEnvironment", mock.AnythingOfType("*context.timerCtx"), &overlockv1beta1.QueryShowEnvironmentRequest{Id: 7}).
+		Return(&overlockv1beta1.QueryShowEnvironmentResponse{Environment: &overlockv1beta1.Environment{
+			Id: 7, Provider: 3, Metadata: &overlockv1beta1.Metadata{Name: "web"},
+		}}, nil)
+	mockClient.On("ShowProvider", mock.AnythingOfType("*context.timerCtx"), &overlockv1beta1.QueryShowProviderRequest{Id: 3}).
+		Return(&overlockv1beta1.QueryShowProviderResponse{Provider: &overlockv1beta1.Provider{
+			Id: 3, Availability: "available", Ip: "127.0.0.1", Port: 8080,
+		}}, nil)
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "diagnose-environment",
+		Arguments: map[string]interface{}{"id": 7},
+	})
+
+	require.NoError(t, err)
+	report := decodeDiagnosis(t, result)
+	require.Len(t, report.Checks, 7)
+	assert.Equal(t, diagnose.StatusWarn, report.Checks[5].Status)
+	assert.Equal(t, diagnose.StatusSkip, report.Checks[6].Status)
+	assert.Contains(t, report.Checks[6].Message, "probing non-public addresses is disabled")
+}
+
+func TestDiagnoseHandler_Handle_EnvironmentNotFound(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewDiagnoseHandler(mockClient, 30*time.Second)
+
+	mockClient.On("ShowEnvironment", mock.AnythingOfType("*context.timerCtx"), mock.Anything).
+		Return(&overlockv1beta1.QueryShowEnvironmentResponse{}, status.Error(codes.NotFound, "environment not found"))
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "diagnose-environment",
+		Arguments: map[string]interface{}{"id": 7},
+	})
+
+	require.NoError(t, err)
+	report := decodeDiagnosis(t, result)
+	assert.Equal(t, diagnose.StatusFail, report.Status)
+	assert.Equal(t, diagnose.StatusFail, report.Checks[0].Status)
+	assert.Nil(t, report.Environment)
+	mockClient.AssertNotCalled(t, "ShowProvider", mock.Anything, mock.Anything)
+}
+
+func TestDiagnoseHandler_Handle_FromIndex(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewDiagnoseHandler(mockClient, 30*time.Second)
+	handler.AttachIndexers(map[string]*indexer.Indexer{"default": newSyncedIndexer(t)})
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "diagnose-environment",
+		Arguments: map[string]interface{}{"id": 1001, "source": "index"},
+	})
+
+	require.NoError(t, err)
+	report := decodeDiagnosis(t, result)
+	require.NotNil(t, report.Provider)
+	assert.Equal(t, uint64(1), report.Provider.Id)
+	// The indexed provider has no endpoint, so the TCP check is skipped
+	assert.Equal(t, diagnose.StatusSkip, report.Checks[6].Status)
+	mockClient.AssertNotCalled(t, "ShowEnvironment", mock.Anything, mock.Anything)
+}
+
+func TestDiagnoseHandler_Handle_InvalidArguments(t *testing.T) {
+	handler := NewDiagnoseHandler(&MockQueryClient{}, 30*time.Second)
+
+	for name, arguments := range map[string]map[string]interface{}{
+		"missing id":        {},
+		"timeout too short": {"id": 1, "tcp_timeout_ms": 10},
+		"timeout too long":  {"id": 1, "tcp_timeout_ms": 60000},
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+				Name:      "diagnose-environment",
+				Arguments: arguments,
+			})
+			require.NoError(t, err)
+			assert.Contains(t, decodeValidation(t, result).Error, "validation failed")
+		})
+	}
+}
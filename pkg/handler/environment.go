@@ -2,53 +2,54 @@ package handler
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
+	"overlock-mcp-server/pkg/network"
+	"overlock-mcp-server/pkg/render"
+	"overlock-mcp-server/pkg/watch"
+
 	"github.com/Oudwins/zog"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
-	"github.com/sony/gobreaker"
+	"google.golang.org/grpc"
 )
 
 // EnvironmentInput represents the input parameters for the show-environment tool
 type EnvironmentInput struct {
-	Id int `json:"id,omitempty"`
+	Id      int    `json:"id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Uri     string `json:"uri,omitempty"`
+	Network string `json:"network,omitempty"`
+	Height  int64  `json:"height,omitempty"`
+	Source  string `json:"source,omitempty"`
+	Expand  bool   `json:"expand,omitempty"`
+	Format  string `json:"format,omitempty"`
+}
+
+// ExpandedEnvironmentResponse is the show-environment response when expand is set
+type ExpandedEnvironmentResponse struct {
+	Environment *ExpandedEnvironment `json:"environment"`
 }
 
 // EnvironmentHandler handles the show-environment tool requests
 type EnvironmentHandler struct {
-	chainClient    overlockv1beta1.QueryClient
-	timeout        time.Duration
-	circuitBreaker *gobreaker.CircuitBreaker
+	chainBackend
 }
 
 // NewEnvironmentHandler creates a new environment handler
 func NewEnvironmentHandler(chainClient overlockv1beta1.QueryClient, timeout time.Duration) *EnvironmentHandler {
-	// Configure circuit breaker
-	cb := gobreaker.NewCircuitBreaker(gobreaker.Settings{
-		Name:        "blockchain-client-env",
-		MaxRequests: 3,
-		Interval:    30 * time.Second,
-		Timeout:     60 * time.Second,
-		ReadyToTrip: func(counts gobreaker.Counts) bool {
-			return counts.ConsecutiveFailures > 2
-		},
-		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
-			log.Warn().
-				Str("circuit_breaker", name).
-				Str("from", from.String()).
-				Str("to", to.String()).
-				Msg("Circuit breaker state changed")
-		},
-	})
+	return &EnvironmentHandler{
+		chainBackend: newChainBackend("blockchain-client-env", chainClient, timeout),
+	}
+}
 
+// NewEnvironmentHandlerForNetworks creates an environment handler serving every configured network
+func NewEnvironmentHandlerForNetworks(networks *network.Registry) *EnvironmentHandler {
 	return &EnvironmentHandler{
-		chainClient:    chainClient,
-		timeout:        timeout,
-		circuitBreaker: cb,
+		chainBackend: newChainBackendForNetworks("blockchain-client-env", networks),
 	}
 }
 
@@ -63,13 +64,16 @@ func (h *EnvironmentHandler) Handle(ctx context.Context, session *mcp.ServerSess
 	start := time.Now()
 	logger.Info().Msg("Processing show-environment request")
 
-	// Apply timeout to the context
-	timeoutCtx, cancel := context.WithTimeout(ctx, h.timeout)
-	defer cancel()
-
 	// Define validation schema using Zog
 	schema := zog.Struct(zog.Shape{
-		"id": zog.Int().GTE(1),
+		"id":      zog.Int().GTE(1),
+		"name":    zog.String().Default(""),
+		"uri":     zog.String().Default(""),
+		"network": zog.String().Default(""),
+		"height":  zog.Int64().GTE(0).Default(0),
+		"source":  zog.String().OneOf([]string{sourceChain, sourceIndex}).Default(sourceChain),
+		"expand":  zog.Bool().Default(false),
+		"format":  zog.String().OneOf(render.RecordFormats).Default(render.FormatJSON),
 	})
 
 	// Validate input parameters
@@ -84,109 +88,161 @@ func (h *EnvironmentHandler) Handle(ctx context.Context, session *mcp.ServerSess
 	errs := schema.Parse(arguments, &input)
 	if errs != nil {
 		logger.Error().Interface("errors", errs).Msg("Input validation failed")
-		return nil, fmt.Errorf("validation failed: %v", errs)
+		return invalidInput(errs), nil
+	}
+
+	// Exactly one of id, name or uri selects the environment
+	selector, invalid := selectRecord(watch.KindEnvironment, input.Id, input.Name, input.Uri, input.Network)
+	if invalid != nil {
+		logger.Error().Msg("Input validation failed")
+		return invalid, nil
 	}
 
-	// Check if ID was provided (required field)
-	if input.Id == 0 {
-		logger.Error().Msg("Environment ID is required")
-		return nil, fmt.Errorf("validation failed: environment ID is required")
+	target, err := h.resolve(selector.network)
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("network", "known network", selector.network, err.Error()), nil
 	}
 
 	logger.Debug().Interface("parsed_input", input).Msg("Input validation successful")
 
+	// Resolve a name to the ID of the single environment carrying it
+	if selector.name != "" {
+		id, result, err := h.environmentByName(ctx, logger, target, input)
+		if result != nil || err != nil {
+			return result, err
+		}
+		selector.id = id
+	}
+
 	// Create the request
 	req := &overlockv1beta1.QueryShowEnvironmentRequest{
-		Id: uint64(input.Id),
+		Id: selector.id,
 	}
 
 	// Log request parameters
 	logger.Info().
+		Str("network", target.network).
+		Int64("height", input.Height).
 		Uint64("environment_id", req.Id).
 		Msg("Fetching environment from blockchain")
 
+	// Serve from the local index when requested
+	if input.Source == sourceIndex {
+		return h.showFromIndex(ctx, logger, target, req.Id, input.Expand, input.Format)
+	}
+
 	// Check if chain client is available
-	if h.chainClient == nil {
+	if target.chainClient == nil {
 		logger.Error().Msg("gRPC client is not available")
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: "Error: gRPC connection to blockchain is not available. Please check the connection and try again.",
-				},
-			},
-		}, nil
-	}
-
-	// Fetch environment from the chain using circuit breaker protection
-	result, err := h.circuitBreaker.Execute(func() (interface{}, error) {
-		return h.chainClient.ShowEnvironment(timeoutCtx, req)
-	})
+		return target.unavailableResult(), nil
+	}
 
+	// Fetch environment from the chain using circuit breaker protection, falling back to the last-known-good response
+	result, info, err := target.executeCached(ctx, cacheKey("ShowEnvironment", input.Height, req), input.Height, func(ctx context.Context, client overlockv1beta1.QueryClient, opts ...grpc.CallOption) (interface{}, error) {
+		return client.ShowEnvironment(ctx, req, opts...)
+	})
 	if err != nil {
-		logger.Info().Err(err).Msg("Failed to connect to gRPC server - blockchain service unavailable")
-		// Check if it's a circuit breaker error
-		if err == gobreaker.ErrOpenState {
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					&mcp.TextContent{
-						Text: "Blockchain service is currently unavailable (circuit breaker protection active). Please try again later.",
-					},
-				},
-			}, nil
-		}
-		// Return a user-friendly response instead of propagating the error
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: "Unable to connect to blockchain service. The service may be temporarily unavailable. Please check your connection and try again later.",
-				},
-			},
-		}, nil
+		return target.failureResult(logger, err), nil
 	}
 
 	chainResponse, ok := result.(*overlockv1beta1.QueryShowEnvironmentResponse)
 	if !ok || chainResponse == nil {
-		logger.Error().Msg("Received invalid response from blockchain service")
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: "Received invalid response from blockchain service. Please try again later.",
-				},
-			},
-		}, nil
+		return target.invalidResponseResult(logger), nil
 	}
 
 	// Check if environment was found
 	if chainResponse.Environment == nil {
 		logger.Info().Uint64("environment_id", req.Id).Msg("Environment not found")
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Environment with ID '%d' not found.", req.Id),
-				},
-			},
-		}, nil
+		return info.textResult(fmt.Sprintf("Environment with ID '%d' not found.", req.Id)), nil
 	}
 
 	// Log successful response
 	duration := time.Since(start)
 	logger.Info().
 		Uint64("environment_id", req.Id).
+		Int64("block_height", info.blockHeight).
 		Dur("duration", duration).
 		Msg("Successfully fetched environment")
 
+	// Inline the provider and creator context when requested
+	var response interface{} = chainResponse
+	if input.Expand {
+		related := chainRecords{target: target, height: info.blockHeight}
+		response = expandedEnvironmentResponse(ctx, related, chainResponse.Environment)
+	}
+
 	// Use the official API response directly
-	responseJSON, err := json.MarshalIndent(chainResponse, "", "  ")
+	toolResult, err := info.formattedResult(response, input.Format)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to marshal response")
 		return nil, fmt.Errorf("failed to marshal environment response: %w", err)
 	}
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			&mcp.TextContent{
-				Text: string(responseJSON),
-			},
-		},
-	}, nil
+	return toolResult, nil
+}
+
+// environmentByName resolves an environment name to an ID by walking the environment listing
+// of the requested source. It returns a result instead when no environment or several carry the name.
+func (h *EnvironmentHandler) environmentByName(ctx context.Context, logger zerolog.Logger, target *chainTarget, input EnvironmentInput) (uint64, *mcp.CallToolResult, error) {
+	var environments []overlockv1beta1.Environment
+	var info queryInfo
+	var truncated bool
+	if input.Source == sourceIndex {
+		snapshot, indexInfo, unavailable := target.snapshot(logger)
+		if unavailable != nil {
+			return 0, unavailable, nil
+		}
+		environments, info = snapshot.Environments, indexInfo
+	} else {
+		if target.chainClient == nil {
+			logger.Error().Msg("gRPC client is not available")
+			return 0, target.unavailableResult(), nil
+		}
+		var err error
+		environments, truncated, info, err = target.listEnvironments(ctx, input.Height, "", fullListMaxPages)
+		if err != nil {
+			return 0, target.failureResult(logger, err), nil
+		}
+	}
+
+	matches := matchName(environments, input.Name, environmentName)
+	if len(matches) == 1 {
+		return matches[0].Id, nil, nil
+	}
+	result, err := nameMatchResult(logger, info, watch.KindEnvironment, input.Name, matches, len(matches), !truncated, input.Format)
+	return 0, result, err
+}
+
+// showFromIndex answers show-environment from the target's local index
+func (h *EnvironmentHandler) showFromIndex(ctx context.Context, logger zerolog.Logger, target *chainTarget, id uint64, expand bool, format string) (*mcp.CallToolResult, error) {
+	snapshot, info, unavailable := target.snapshot(logger)
+	if unavailable != nil {
+		return unavailable, nil
+	}
+
+	environment, ok := snapshot.Environment(id)
+	if !ok {
+		logger.Info().Uint64("environment_id", id).Msg("Environment not found in index")
+		return info.textResult(fmt.Sprintf("Environment with ID '%d' not found.", id)), nil
+	}
+
+	var response interface{} = &overlockv1beta1.QueryShowEnvironmentResponse{Environment: &environment}
+	if expand {
+		response = expandedEnvironmentResponse(ctx, snapshotRecords{snapshot: snapshot}, &environment)
+	}
+
+	toolResult, err := info.formattedResult(response, format)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to marshal response")
+		return nil, fmt.Errorf("failed to marshal environment response: %w", err)
+	}
+	return toolResult, nil
+}
+
+// expandedEnvironmentResponse builds the show-environment response with the environment's related records inlined
+func expandedEnvironmentResponse(ctx context.Context, related relatedRecords, environment *overlockv1beta1.Environment) *ExpandedEnvironmentResponse {
+	return &ExpandedEnvironmentResponse{
+		Environment: expandEnvironments(ctx, related, []*overlockv1beta1.Environment{environment})[0],
+	}
 }
@@ -107,9 +107,8 @@ func TestEnvironmentHandler_Handle_ValidationError_MissingID(t *testing.T) {
 
 	result, err := handler.Handle(ctx, session, params)
 
-	assert.Error(t, err)
-	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "validation failed")
+	require.NoError(t, err)
+	assert.Contains(t, decodeValidation(t, result).Error, "validation failed")
 }
 
 func TestEnvironmentHandler_Handle_ValidationError_InvalidID(t *testing.T) {
@@ -128,9 +127,8 @@ func TestEnvironmentHandler_Handle_ValidationError_InvalidID(t *testing.T) {
 
 	result, err := handler.Handle(ctx, session, params)
 
-	assert.Error(t, err)
-	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "validation failed")
+	require.NoError(t, err)
+	assert.Contains(t, decodeValidation(t, result).Error, "validation failed")
 }
 
 func TestEnvironmentHandler_Handle_EnvironmentNotFound(t *testing.T) {
@@ -241,3 +239,35 @@ func TestEnvironmentHandler_Handle_WithValidID(t *testing.T) {
 
 	mockClient.AssertExpectations(t)
 }
+
+func TestEnvironmentHandler_Handle_CompactJSONFormat(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewEnvironmentHandler(mockClient, 30*time.Second)
+
+	expectedResponse := &overlockv1beta1.QueryShowEnvironmentResponse{
+		Environment: &overlockv1beta1.Environment{
+			Id:      1,
+			Creator: "test-creator",
+		},
+	}
+
+	mockClient.On("ShowEnvironment", mock.AnythingOfType("*context.timerCtx"), mock.Anything).Return(expectedResponse, nil)
+
+	params := &mcp.CallToolParams{
+		Name:      "show-environment",
+		Arguments: map[string]interface{}{"id": 1, "format": "compact_json"},
+	}
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.NotContains(t, textContent.Text, "\n")
+
+	var response overlockv1beta1.QueryShowEnvironmentResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	assert.Equal(t, "test-creator", response.Environment.Creator)
+
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"overlock-mcp-server/pkg/indexer"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Provider statuses reported by an environment expansion
+const (
+	providerStatusFound    = "found"
+	providerStatusNotFound = "not_found"
+	providerStatusError    = "error"
+)
+
+// EnvironmentExpansion is the context inlined into an environment when expand is set
+type EnvironmentExpansion struct {
+	ProviderStatus  string                    `json:"provider_status"`
+	Provider        *overlockv1beta1.Provider `json:"provider,omitempty"`
+	ProviderMessage string                    `json:"provider_message,omitempty"`
+
+	// Number of other environments owned by the environment's creator
+	CreatorOtherEnvironments *uint64 `json:"creator_other_environments,omitempty"`
+	CreatorError             string  `json:"creator_error,omitempty"`
+}
+
+// ExpandedEnvironment is an environment with its provider and creator context inlined
+type ExpandedEnvironment struct {
+	*overlockv1beta1.Environment
+	Expanded *EnvironmentExpansion `json:"expanded,omitempty"`
+}
+
+// relatedRecords looks up the records an environment refers to
+type relatedRecords interface {
+	// provider returns the provider with the given ID, or nil when it does not exist
+	provider(ctx context.Context, id uint64) (*overlockv1beta1.Provider, error)
+	// environmentCount returns the number of environments owned by creator
+	environmentCount(ctx context.Context, creator string) (uint64, error)
+}
+
+// chainRecords reads related records from the chain at a fixed height
+type chainRecords struct {
+	target *chainTarget
+	height int64
+}
+
+func (r chainRecords) provider(ctx context.Context, id uint64) (*overlockv1beta1.Provider, error) {
+	record, _, err := lookupProvider(ctx, r.target, id, r.height)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	provider, _ := record.(*overlockv1beta1.Provider)
+	return provider, nil
+}
+
+func (r chainRecords) environmentCount(ctx context.Context, creator string) (uint64, error) {
+	req := &overlockv1beta1.QueryListEnvironmentRequest{
+		Creator:    creator,
+		Pagination: &query.PageRequest{Limit: 1, CountTotal: true},
+	}
+	result, _, err := r.target.executeCached(ctx, cacheKey("ListEnvironment", r.height, req), r.height, func(ctx context.Context, client overlockv1beta1.QueryClient, opts ...grpc.CallOption) (interface{}, error) {
+		return client.ListEnvironment(ctx, req, opts...)
+	})
+	if err != nil {
+		return 0, err
+	}
+	response, ok := result.(*overlockv1beta1.QueryListEnvironmentResponse)
+	if !ok || response == nil {
+		return 0, fmt.Errorf("invalid response from blockchain service")
+	}
+	if response.Pagination == nil {
+		return uint64(len(response.Environments)), nil
+	}
+	return response.Pagination.Total, nil
+}
+
+// snapshotRecords reads related records from a local index snapshot
+type snapshotRecords struct {
+	snapshot *indexer.Snapshot
+}
+
+func (r snapshotRecords) provider(ctx context.Context, id uint64) (*overlockv1beta1.Provider, error) {
+	provider, ok := r.snapshot.Provider(id)
+	if !ok {
+		return nil, nil
+	}
+	return &provider, nil
+}
+
+func (r snapshotRecords) environmentCount(ctx context.Context, creator string) (uint64, error) {
+	var count uint64
+	for _, environment := range r.snapshot.Environments {
+		if environment.Creator == creator {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// expandEnvironments inlines the provider and the creator's environment count into every
+// environment. Each distinct provider and creator is looked up once.
+func expandEnvironments(ctx context.Context, related relatedRecords, environments []*overlockv1beta1.Environment) []*ExpandedEnvironment {
+	var providerIDs []uint64
+	var creators []string
+	seenProviders := make(map[uint64]bool)
+	seenCreators := make(map[string]bool)
+	for _, environment := range environments {
+		if environment == nil {
+			continue
+		}
+		if !seenProviders[environment.Provider] {
+			seenProviders[environment.Provider] = true
+			providerIDs = append(providerIDs, environment.Provider)
+		}
+		if !seenCreators[environment.Creator] {
+			seenCreators[environment.Creator] = true
+			creators = append(creators, environment.Creator)
+		}
+	}
+
+	type providerResult struct {
+		provider *overlockv1beta1.Provider
+		err      error
+	}
+	type countResult struct {
+		count uint64
+		err   error
+	}
+	providers := make([]providerResult, len(providerIDs))
+	forEachBounded(len(providerIDs), func(i int) {
+		provider, err := related.provider(ctx, providerIDs[i])
+		providers[i] = providerResult{provider: provider, err: err}
+	})
+	counts := make([]countResult, len(creators))
+	forEachBounded(len(creators), func(i int) {
+		count, err := related.environmentCount(ctx, creators[i])
+		counts[i] = countResult{count: count, err: err}
+	})
+
+	providerByID := make(map[uint64]providerResult, len(providerIDs))
+	for i, id := range providerIDs {
+		providerByID[id] = providers[i]
+	}
+	countByCreator := make(map[string]countResult, len(creators))
+	for i, creator := range creators {
+		countByCreator[creator] = counts[i]
+	}
+
+	expanded := make([]*ExpandedEnvironment, len(environments))
+	for i, environment := range environments {
+		if environment == nil {
+			continue
+		}
+		expansion := &EnvironmentExpansion{}
+		switch p := providerByID[environment.Provider]; {
+		case p.err != nil:
+			expansion.ProviderStatus = providerStatusError
+			expansion.ProviderMessage = fmt.Sprintf("Failed to look up provider with ID '%d': %v", environment.Provider, p.err)
+		case p.provider == nil:
+			expansion.ProviderStatus = providerStatusNotFound
+			expansion.ProviderMessage = fmt.Sprintf("Provider with ID '%d' referenced by this environment does not exist.", environment.Provider)
+		default:
+			expansion.ProviderStatus = providerStatusFound
+			expansion.Provider = p.provider
+		}
+		if c := countByCreator[environment.Creator]; c.err != nil {
+			expansion.CreatorError = c.err.Error()
+		} else {
+			// The count includes the environment itself
+			others := c.count
+			if others > 0 {
+				others--
+			}
+			expansion.CreatorOtherEnvironments = &others
+		}
+		expanded[i] = &ExpandedEnvironment{Environment: environment, Expanded: expansion}
+	}
+	return expanded
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"overlock-mcp-server/pkg/indexer"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// expandedTestEnvironment mirrors an expanded environment in a response
+type expandedTestEnvironment struct {
+	Id       uint64               `json:"id"`
+	Provider uint64               `json:"provider"`
+	Expanded EnvironmentExpansion `json:"expanded"`
+}
+
+func decodeExpandedEnvironment(t *testing.T, result *mcp.CallToolResult) expandedTestEnvironment {
+	require.NotNil(t, result)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	var response struct {
+		Environment expandedTestEnvironment `json:"environment"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	return response.Environment
+}
+
+func expandParams(id int) *mcp.CallToolParams {
+	return &mcp.CallToolParams{
+		Name:      "show-environment",
+		Arguments: map[string]interface{}{"id": id, "expand": true},
+	}
+}
+
+func TestEnvironmentHandler_Handle_Expand(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewEnvironmentHandler(mockClient, 30*time.Second)
+
+	mockClient.On("ShowEnvironment", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryShowEnvironmentResponse{
+		Environment: &overlockv1beta1.Environment{Id: 1001, Creator: "overlock1alice", Provider: 7},
+	}, nil)
+	mockClient.On("ShowProvider", mock.Anything, &overlockv1beta1.QueryShowProviderRequest{Id: 7}).Return(&overlockv1beta1.QueryShowProviderResponse{
+		Provider: &overlockv1beta1.Provider{Id: 7, Ip: "10.0.0.7"},
+	}, nil)
+	mockClient.On("ListEnvironment", mock.Anything, mock.MatchedBy(func(req *overlockv1beta1.QueryListEnvironmentRequest) bool {
+		return req.Creator == "overlock1alice" && req.Pagination.CountTotal
+	})).Return(&overlockv1beta1.QueryListEnvironmentResponse{
+		Pagination: &query.PageResponse{Total: 3},
+	}, nil)
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, expandParams(1001))
+
+	require.NoError(t, err)
+	environment := decodeExpandedEnvironment(t, result)
+	assert.Equal(t, uint64(1001), environment.Id)
+	assert.Equal(t, uint64(7), environment.Provider)
+	assert.Equal(t, providerStatusFound, environment.Expanded.ProviderStatus)
+	require.NotNil(t, environment.Expanded.Provider)
+	assert.Equal(t, "10.0.0.7", environment.Expanded.Provider.Ip)
+	require.NotNil(t, environment.Expanded.CreatorOtherEnvironments)
+	assert.Equal(t, uint64(2), *environment.Expanded.CreatorOtherEnvironments)
+}
+
+func TestEnvironmentHandler_Handle_ExpandMissingProvider(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewEnvironmentHandler(mockClient, 30*time.Second)
+
+	mockClient.On("ShowEnvironment", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryShowEnvironmentResponse{
+		Environment: &overlockv1beta1.Environment{Id: 1001, Creator: "overlock1alice", Provider: 9},
+	}, nil)
+	mockClient.On("ShowProvider", mock.Anything, mock.Anything).
+		Return(&overlockv1beta1.QueryShowProviderResponse{}, status.Error(codes.NotFound, "provider not found"))
+	mockClient.On("ListEnvironment", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryListEnvironmentResponse{
+		Pagination: &query.PageResponse{Total: 1},
+	}, nil)
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, expandParams(1001))
+
+	require.NoError(t, err)
+	environment := decodeExpandedEnvironment(t, result)
+	assert.Equal(t, providerStatusNotFound, environment.Expanded.ProviderStatus)
+	assert.Nil(t, environment.Expanded.Provider)
+	assert.Contains(t, environment.Expanded.ProviderMessage, "Provider with ID '9'")
+	require.NotNil(t, environment.Expanded.CreatorOtherEnvironments)
+	assert.Equal(t, uint64(0), *environment.Expanded.CreatorOtherEnvironments)
+}
+
+func TestEnvironmentHandler_Handle_ExpandFromIndex(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewEnvironmentHandler(mockClient, 30*time.Second)
+	handler.AttachIndexers(map[string]*indexer.Indexer{"default": newSyncedIndexer(t)})
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "show-environment",
+		Arguments: map[string]interface{}{"id": 1001, "expand": true, "source": "index"},
+	})
+
+	require.NoError(t, err)
+	environment := decodeExpandedEnvironment(t, result)
+	assert.Equal(t, providerStatusFound, environment.Expanded.ProviderStatus)
+	require.NotNil(t, environment.Expanded.Provider)
+	assert.Equal(t, uint64(1), environment.Expanded.Provider.Id)
+	require.NotNil(t, environment.Expanded.CreatorOtherEnvironments)
+	assert.Equal(t, uint64(0), *environment.Expanded.CreatorOtherEnvironments)
+	mockClient.AssertNotCalled(t, "ShowProvider", mock.Anything, mock.Anything)
+}
+
+func TestEnvironmentHandler_Handle_NotExpandedByDefault(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewEnvironmentHandler(mockClient, 30*time.Second)
+
+	mockClient.On("ShowEnvironment", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryShowEnvironmentResponse{
+		Environment: &overlockv1beta1.Environment{Id: 1001, Provider: 7},
+	}, nil)
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "show-environment",
+		Arguments: map[string]interface{}{"id": 1001},
+	})
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.NotContains(t, textContent.Text, "expanded")
+	mockClient.AssertNotCalled(t, "ShowProvider", mock.Anything, mock.Anything)
+}
+
+func TestEnvironmentHandler_HandleShowBatch_Expand(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewEnvironmentHandler(mockClient, 30*time.Second)
+
+	for _, id := range []uint64{1001, 1002} {
+		mockClient.On("ShowEnvironment", mock.Anything, &overlockv1beta1.QueryShowEnvironmentRequest{Id: id}).Return(&overlockv1beta1.QueryShowEnvironmentResponse{
+			Environment: &overlockv1beta1.Environment{Id: id, Creator: "overlock1alice", Provider: 7},
+		}, nil)
+	}
+	mockClient.On("ShowProvider", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryShowProviderResponse{
+		Provider: &overlockv1beta1.Provider{Id: 7},
+	}, nil)
+	mockClient.On("ListEnvironment", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryListEnvironmentResponse{
+		Pagination: &query.PageResponse{Total: 2},
+	}, nil)
+
+	result, err := handler.HandleShowBatch(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "show-environments",
+		Arguments: map[string]interface{}{"ids": []interface{}{1001, 1002}, "expand": true},
+	})
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	var response struct {
+		Found []expandedTestEnvironment `json:"found"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	require.Len(t, response.Found, 2)
+	for _, environment := range response.Found {
+		assert.Equal(t, providerStatusFound, environment.Expanded.ProviderStatus)
+		assert.Equal(t, uint64(1), *environment.Expanded.CreatorOtherEnvironments)
+	}
+
+	// The shared provider and creator are looked up once
+	mockClient.AssertNumberOfCalls(t, "ShowProvider", 1)
+	mockClient.AssertNumberOfCalls(t, "ListEnvironment", 1)
+}
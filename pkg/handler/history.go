@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"overlock-mcp-server/pkg/history"
+	"overlock-mcp-server/pkg/network"
+
+	"github.com/Oudwins/zog"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// RecordHistoryInput represents the input parameters for the provider-history and environment-history tools
+type RecordHistoryInput struct {
+	Id      int    `json:"id,omitempty"`
+	Network string `json:"network,omitempty"`
+	Since   string `json:"since,omitempty"`
+	Until   string `json:"until,omitempty"`
+	Limit   int    `json:"limit,omitempty"`
+}
+
+// RecentChangesInput represents the input parameters for the recent-changes tool
+type RecentChangesInput struct {
+	Network string `json:"network,omitempty"`
+	Kind    string `json:"kind,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Since   string `json:"since,omitempty"`
+	Until   string `json:"until,omitempty"`
+	Limit   int    `json:"limit,omitempty"`
+}
+
+// HistoryResponse is the response of the history tools
+type HistoryResponse struct {
+	Network string           `json:"network"`
+	Note    string           `json:"note,omitempty"`
+	Since   string           `json:"since,omitempty"`
+	Until   string           `json:"until,omitempty"`
+	Count   int              `json:"count"`
+	Changes []history.Change `json:"changes"`
+}
+
+// HistoryHandler handles the provider-history, environment-history and recent-changes tool requests
+type HistoryHandler struct {
+	changes  history.Log
+	networks *network.Registry
+}
+
+// NewHistoryHandler creates a new history handler reading the changes of the given networks from the change log
+func NewHistoryHandler(changes history.Log, networks *network.Registry) *HistoryHandler {
+	return &HistoryHandler{
+		changes:  changes,
+		networks: networks,
+	}
+}
+
+// HandleProviderHistory processes the provider-history tool call
+func (h *HistoryHandler) HandleProviderHistory(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	return h.handleRecordHistory("provider-history", history.KindProvider, params)
+}
+
+// HandleEnvironmentHistory processes the environment-history tool call
+func (h *HistoryHandler) HandleEnvironmentHistory(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	return h.handleRecordHistory("environment-history", history.KindEnvironment, params)
+}
+
+// handleRecordHistory returns the changes recorded for a single provider or environment
+func (h *HistoryHandler) handleRecordHistory(tool, kind string, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	// Create a logger with request context
+	logger := log.With().
+		Str("tool", tool).
+		Str("request_id", fmt.Sprintf("%p", params)).
+		Logger()
+
+	logger.Info().Msgf("Processing %s request", tool)
+
+	// Define validation schema using Zog
+	schema := zog.Struct(zog.Shape{
+		"id":      zog.Int().GTE(1),
+		"network": zog.String().Default(""),
+		"since":   zog.String().Default(""),
+		"until":   zog.String().Default(""),
+		"limit":   zog.Int().GTE(1).LTE(500).Default(50),
+	})
+
+	// Validate input parameters
+	var input RecordHistoryInput
+	arguments := params.Arguments
+	if arguments == nil {
+		arguments = make(map[string]interface{})
+	}
+
+	logger.Debug().Interface("arguments", arguments).Msg("Validating input arguments")
+	// Parse and validate the arguments
+	errs := schema.Parse(arguments, &input)
+	if errs != nil {
+		logger.Error().Interface("errors", errs).Msg("Input validation failed")
+		return invalidInput(errs), nil
+	}
+
+	// Check if ID was provided (required field)
+	if input.Id == 0 {
+		logger.Error().Msgf("%s ID is required", kind)
+		return requiredArgument("id", fmt.Sprintf("%s ID is required", kind)), nil
+	}
+
+	n, err := h.networks.Get(input.Network)
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("network", "known network", input.Network, err.Error()), nil
+	}
+
+	filter, err := timeRangeFilter(input.Since, input.Until, time.Now())
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return timeRangeProblem(input.Since, input.Until, err), nil
+	}
+	filter.Network = n.Name
+	filter.Kind = kind
+	filter.RecordID = uint64(input.Id)
+	filter.Limit = input.Limit
+
+	return h.query(logger, filter)
+}
+
+// HandleRecentChanges processes the recent-changes tool call
+func (h *HistoryHandler) HandleRecentChanges(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	// Create a logger with request context
+	logger := log.With().
+		Str("tool", "recent-changes").
+		Str("request_id", fmt.Sprintf("%p", params)).
+		Logger()
+
+	logger.Info().Msg("Processing recent-changes request")
+
+	// Define validation schema using Zog
+	schema := zog.Struct(zog.Shape{
+		"network": zog.String().Default(""),
+		"kind":    zog.String().OneOf([]string{"", history.KindProvider, history.KindEnvironment}).Default(""),
+		"type":    zog.String().OneOf([]string{"", history.TypeCreated, history.TypeUpdated, history.TypeRemoved}).Default(""),
+		"since":   zog.String().Default("168h"),
+		"until":   zog.String().Default(""),
+		"limit":   zog.Int().GTE(1).LTE(500).Default(100),
+	})
+
+	// Validate input parameters
+	var input RecentChangesInput
+	arguments := params.Arguments
+	if arguments == nil {
+		arguments = make(map[string]interface{})
+	}
+
+	logger.Debug().Interface("arguments", arguments).Msg("Validating input arguments")
+	// Parse and validate the arguments
+	errs := schema.Parse(arguments, &input)
+	if errs != nil {
+		logger.Error().Interface("errors", errs).Msg("Input validation failed")
+		return invalidInput(errs), nil
+	}
+
+	n, err := h.networks.Get(input.Network)
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("network", "known network", input.Network, err.Error()), nil
+	}
+
+	filter, err := timeRangeFilter(input.Since, input.Until, time.Now())
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return timeRangeProblem(input.Since, input.Until, err), nil
+	}
+	filter.Network = n.Name
+	filter.Kind = input.Kind
+	filter.Type = input.Type
+	filter.Limit = input.Limit
+
+	return h.query(logger, filter)
+}
+
+// query runs the filter against the change log and renders the matching changes
+func (h *HistoryHandler) query(logger zerolog.Logger, filter history.Filter) (*mcp.CallToolResult, error) {
+	if h.changes == nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: "Change history is not available: it is recorded by the local index. Set OVERLOCK_INDEX_ENABLED=true to enable it.",
+				},
+			},
+		}, nil
+	}
+
+	changes, err := h.changes.Query(filter)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to query change history")
+		return nil, fmt.Errorf("failed to query change history: %w", err)
+	}
+
+	response := HistoryResponse{
+		Network: filter.Network,
+		Count:   len(changes),
+		Changes: changes,
+	}
+	if _, inMemory := h.changes.(*history.MemoryLog); inMemory {
+		response.Note = "Change history is kept in memory because the history database could not be opened; it only covers changes detected since the server started."
+	}
+	if response.Changes == nil {
+		response.Changes = []history.Change{}
+	}
+	if !filter.Since.IsZero() {
+		response.Since = filter.Since.UTC().Format(time.RFC3339)
+	}
+	if !filter.Until.IsZero() {
+		response.Until = filter.Until.UTC().Format(time.RFC3339)
+	}
+
+	logger.Info().Int("change_count", len(changes)).Msg("Successfully queried change history")
+
+	responseJSON, err := renderJSON(response, nil)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to marshal response")
+		return nil, fmt.Errorf("failed to marshal history response: %w", err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: responseJSON,
+			},
+		},
+	}, nil
+}
+
+// timeRangeFilter builds a filter from since/until bounds, each either an RFC3339
+// timestamp or a duration before now such as "24h"
+func timeRangeFilter(since, until string, now time.Time) (history.Filter, error) {
+	var filter history.Filter
+	var err error
+	if filter.Since, err = parseTimeBound(since, now); err != nil {
+		return filter, fmt.Errorf("invalid since: %w", err)
+	}
+	if filter.Until, err = parseTimeBound(until, now); err != nil {
+		return filter, fmt.Errorf("invalid until: %w", err)
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && filter.Until.Before(filter.Since) {
+		return filter, fmt.Errorf("until must not be before since")
+	}
+	return filter, nil
+}
+
+// timeRangeProblem returns the error result for a time range timeRangeFilter rejected
+func timeRangeProblem(since, until string, err error) *mcp.CallToolResult {
+	if _, boundErr := parseTimeBound(since, time.Now()); boundErr != nil {
+		return invalidArgument("since", "time bound", since, boundErr.Error())
+	}
+	if _, boundErr := parseTimeBound(until, time.Now()); boundErr != nil {
+		return invalidArgument("until", "time bound", until, boundErr.Error())
+	}
+	return invalidArgument("until", "not before since", until, err.Error())
+}
+
+// parseTimeBound parses an RFC3339 timestamp or a duration before now; empty means unbounded
+func parseTimeBound(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("'%s' is neither an RFC3339 timestamp nor a positive duration such as '24h'", value)
+	}
+	return now.Add(-d), nil
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"overlock-mcp-server/pkg/history"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHistory(t *testing.T) history.Log {
+	now := time.Now().UTC()
+	changeLog := history.NewMemoryLog(0)
+	require.NoError(t, changeLog.Append([]history.Change{
+		{Network: "default", Kind: history.KindProvider, RecordID: 1, Type: history.TypeCreated, DetectedAt: now.Add(-240 * time.Hour)},
+		{Network: "default", Kind: history.KindEnvironment, RecordID: 1001, Type: history.TypeCreated, DetectedAt: now.Add(-48 * time.Hour)},
+		{Network: "default", Kind: history.KindProvider, RecordID: 1, Type: history.TypeUpdated, DetectedAt: now.Add(-time.Hour),
+			Fields: []history.FieldChange{{Field: "ip", Old: "10.0.0.1", New: "10.0.0.2"}}},
+		{Network: "testnet", Kind: history.KindProvider, RecordID: 1, Type: history.TypeRemoved, DetectedAt: now.Add(-time.Hour)},
+	}))
+	return changeLog
+}
+
+func decodeHistory(t *testing.T, result *mcp.CallToolResult) HistoryResponse {
+	require.NotNil(t, result)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+
+	var response HistoryResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	return response
+}
+
+func TestHistoryHandler_HandleProviderHistory(t *testing.T) {
+	handler := NewHistoryHandler(newTestHistory(t), newRecordedNetworks())
+
+	params := &mcp.CallToolParams{
+		Name:      "provider-history",
+		Arguments: map[string]interface{}{"id": 1, "network": "default"},
+	}
+
+	result, err := handler.HandleProviderHistory(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	response := decodeHistory(t, result)
+	require.Equal(t, 2, response.Count)
+	assert.Equal(t, history.TypeUpdated, response.Changes[0].Type)
+	assert.Equal(t, []history.FieldChange{{Field: "ip", Old: "10.0.0.1", New: "10.0.0.2"}}, response.Changes[0].Fields)
+	assert.Equal(t, history.TypeCreated, response.Changes[1].Type)
+}
+
+func TestHistoryHandler_HandleProviderHistory_Since(t *testing.T) {
+	handler := NewHistoryHandler(newTestHistory(t), newRecordedNetworks())
+
+	params := &mcp.CallToolParams{
+		Name:      "provider-history",
+		Arguments: map[string]interface{}{"id": 1, "since": "24h"},
+	}
+
+	result, err := handler.HandleProviderHistory(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	response := decodeHistory(t, result)
+	assert.Equal(t, 1, response.Count)
+	assert.NotEmpty(t, response.Since)
+	for _, change := range response.Changes {
+		assert.NotEqual(t, history.TypeCreated, change.Type)
+	}
+}
+
+func TestHistoryHandler_HandleProviderHistory_MissingID(t *testing.T) {
+	handler := NewHistoryHandler(newTestHistory(t), newRecordedNetworks())
+
+	result, err := handler.HandleProviderHistory(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{Name: "provider-history"})
+
+	require.NoError(t, err)
+	assert.Contains(t, decodeValidation(t, result).Error, "validation failed")
+}
+
+func TestHistoryHandler_HandleEnvironmentHistory(t *testing.T) {
+	handler := NewHistoryHandler(newTestHistory(t), newRecordedNetworks())
+
+	params := &mcp.CallToolParams{
+		Name:      "environment-history",
+		Arguments: map[string]interface{}{"id": 1001},
+	}
+
+	result, err := handler.HandleEnvironmentHistory(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	response := decodeHistory(t, result)
+	require.Equal(t, 1, response.Count)
+	assert.Equal(t, history.KindEnvironment, response.Changes[0].Kind)
+}
+
+func TestHistoryHandler_HandleRecentChanges(t *testing.T) {
+	handler := NewHistoryHandler(newTestHistory(t), newRecordedNetworks())
+
+	// The default window of 168h excludes the 10 day old creation, and only the default network is read
+	result, err := handler.HandleRecentChanges(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{Name: "recent-changes"})
+	require.NoError(t, err)
+	response := decodeHistory(t, result)
+	assert.Equal(t, "default", response.Network)
+	assert.Equal(t, 2, response.Count)
+	assert.Contains(t, response.Note, "kept in memory")
+
+	params := &mcp.CallToolParams{
+		Name:      "recent-changes",
+		Arguments: map[string]interface{}{"network": "testnet", "kind": "provider", "type": "removed"},
+	}
+	result, err = handler.HandleRecentChanges(context.Background(), &mcp.ServerSession{}, params)
+	require.NoError(t, err)
+	response = decodeHistory(t, result)
+	require.Equal(t, 1, response.Count)
+	assert.Equal(t, "testnet", response.Changes[0].Network)
+}
+
+func TestHistoryHandler_HandleRecentChanges_InvalidArguments(t *testing.T) {
+	handler := NewHistoryHandler(newTestHistory(t), newRecordedNetworks())
+
+	for name, arguments := range map[string]map[string]interface{}{
+		"invalid kind":   {"kind": "validator"},
+		"invalid since":  {"since": "yesterday"},
+		"reversed range": {"since": "1h", "until": "2h"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			params := &mcp.CallToolParams{Name: "recent-changes", Arguments: arguments}
+
+			result, err := handler.HandleRecentChanges(context.Background(), &mcp.ServerSession{}, params)
+
+			require.NoError(t, err)
+			assert.Contains(t, decodeValidation(t, result).Error, "validation failed")
+		})
+	}
+}
+
+func TestHistoryHandler_UnknownNetwork(t *testing.T) {
+	handler := NewHistoryHandler(newTestHistory(t), newRecordedNetworks())
+
+	for name, call := range map[string]func(context.Context, *mcp.ServerSession, *mcp.CallToolParams) (*mcp.CallToolResult, error){
+		"provider-history": handler.HandleProviderHistory,
+		"recent-changes":   handler.HandleRecentChanges,
+	} {
+		t.Run(name, func(t *testing.T) {
+			params := &mcp.CallToolParams{Name: name, Arguments: map[string]interface{}{"id": 1, "network": "devnet"}}
+
+			result, err := call(context.Background(), &mcp.ServerSession{}, params)
+
+			require.NoError(t, err)
+			failure := decodeValidation(t, result)
+			require.Len(t, failure.Problems, 1)
+			assert.Equal(t, "network", failure.Problems[0].Field)
+		})
+	}
+}
+
+func TestHistoryHandler_Disabled(t *testing.T) {
+	handler := NewHistoryHandler(nil, newRecordedNetworks())
+
+	result, err := handler.HandleRecentChanges(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{Name: "recent-changes"})
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "OVERLOCK_INDEX_ENABLED")
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"overlock-mcp-server/pkg/indexer"
+
+	"github.com/Oudwins/zog"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rs/zerolog/log"
+)
+
+// SyncStatusInput represents the input parameters for the sync-status tool
+type SyncStatusInput struct {
+	Network string `json:"network,omitempty"`
+}
+
+// SyncStatus is the sync state of one network's index
+type SyncStatus struct {
+	indexer.Status
+	StalenessSeconds int64 `json:"staleness_seconds,omitempty"`
+}
+
+// SyncStatusResponse is the sync-status tool response
+type SyncStatusResponse struct {
+	Indexes []SyncStatus `json:"indexes"`
+}
+
+// IndexHandler handles the sync-status tool requests
+type IndexHandler struct {
+	indexers map[string]*indexer.Indexer
+}
+
+// NewIndexHandler creates a new index handler for the given indexers, keyed by network
+func NewIndexHandler(indexers map[string]*indexer.Indexer) *IndexHandler {
+	return &IndexHandler{
+		indexers: indexers,
+	}
+}
+
+// HandleStatus processes the sync-status tool call
+func (h *IndexHandler) HandleStatus(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	// Create a logger with request context
+	logger := log.With().
+		Str("tool", "sync-status").
+		Str("request_id", fmt.Sprintf("%p", params)).
+		Logger()
+
+	logger.Info().Msg("Processing sync-status request")
+
+	// Define validation schema using Zog
+	schema := zog.Struct(zog.Shape{
+		"network": zog.String().Default(""),
+	})
+
+	// Validate input parameters
+	var input SyncStatusInput
+	arguments := params.Arguments
+	if arguments == nil {
+		arguments = make(map[string]interface{})
+	}
+
+	logger.Debug().Interface("arguments", arguments).Msg("Validating input arguments")
+	// Parse and validate the arguments
+	errs := schema.Parse(arguments, &input)
+	if errs != nil {
+		logger.Error().Interface("errors", errs).Msg("Input validation failed")
+		return invalidInput(errs), nil
+	}
+
+	if len(h.indexers) == 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: "The local index is not enabled on this server. Set OVERLOCK_INDEX_ENABLED=true to enable it.",
+				},
+			},
+		}, nil
+	}
+
+	names := sortedKeys(h.indexers)
+	if input.Network != "" {
+		if _, ok := h.indexers[input.Network]; !ok {
+			logger.Error().Str("network", input.Network).Msg("No index for network")
+			return invalidArgument("network", "indexed network", input.Network, fmt.Sprintf("no index for network '%s' (indexed: %v)", input.Network, names)), nil
+		}
+		names = []string{input.Network}
+	}
+
+	response := SyncStatusResponse{Indexes: make([]SyncStatus, 0, len(names))}
+	for _, name := range names {
+		status := SyncStatus{Status: h.indexers[name].Status()}
+		if !status.LastSuccessAt.IsZero() {
+			status.StalenessSeconds = int64(time.Since(status.LastSuccessAt).Seconds())
+		}
+		response.Indexes = append(response.Indexes, status)
+	}
+
+	responseJSON, err := renderJSON(response, nil)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to marshal response")
+		return nil, fmt.Errorf("failed to marshal sync status response: %w", err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: responseJSON,
+			},
+		},
+	}, nil
+}
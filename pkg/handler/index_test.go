@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"overlock-mcp-server/pkg/indexer"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newSyncedIndexer(t *testing.T) *indexer.Indexer {
+	client := &MockQueryClient{}
+	client.On("ListProvider", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryListProviderResponse{
+		Providers: []overlockv1beta1.Provider{
+			{Id: 1, Creator: "overlock1alice"},
+			{Id: 2, Creator: "overlock1bob"},
+			{Id: 3, Creator: "overlock1alice"},
+		},
+		Pagination: &query.PageResponse{},
+	}, nil)
+	client.On("ListEnvironment", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryListEnvironmentResponse{
+		Environments: []overlockv1beta1.Environment{
+			{Id: 1001, Creator: "overlock1alice", Provider: 1},
+		},
+		Pagination: &query.PageResponse{},
+	}, nil)
+
+	idx := indexer.New("default", client, indexer.Options{})
+	require.NoError(t, idx.Sync(context.Background()))
+	return idx
+}
+
+func TestProvidersHandler_HandleList_FromIndex(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewProvidersHandler(mockClient, 30*time.Second)
+	handler.AttachIndexers(map[string]*indexer.Indexer{"default": newSyncedIndexer(t)})
+
+	params := &mcp.CallToolParams{
+		Name: "get-providers",
+		Arguments: map[string]interface{}{
+			"creator": "overlock1alice",
+			"source":  "index",
+		},
+	}
+
+	result, err := handler.HandleList(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "index", result.Meta["source"])
+	assert.Contains(t, result.Meta, "staleness_seconds")
+
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+
+	var response overlockv1beta1.QueryListProviderResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	require.Len(t, response.Providers, 2)
+	assert.Equal(t, uint64(1), response.Providers[0].Id)
+	assert.Equal(t, uint64(3), response.Providers[1].Id)
+	assert.Equal(t, uint64(2), response.Pagination.Total)
+
+	// The chain is not queried
+	mockClient.AssertNotCalled(t, "ListProvider", mock.Anything, mock.Anything)
+}
+
+func TestProvidersHandler_HandleShow_FromIndex(t *testing.T) {
+	handler := NewProvidersHandler(nil, 30*time.Second)
+	handler.AttachIndexers(map[string]*indexer.Indexer{"default": newSyncedIndexer(t)})
+
+	params := &mcp.CallToolParams{
+		Name: "show-provider",
+		Arguments: map[string]interface{}{
+			"id":     2,
+			"source": "index",
+		},
+	}
+
+	result, err := handler.HandleShow(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+
+	var response overlockv1beta1.QueryShowProviderResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	require.NotNil(t, response.Provider)
+	assert.Equal(t, "overlock1bob", response.Provider.Creator)
+
+	params.Arguments = map[string]interface{}{"id": 99, "source": "index"}
+	result, err = handler.HandleShow(context.Background(), &mcp.ServerSession{}, params)
+	require.NoError(t, err)
+	textContent, ok = result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "Provider with ID '99' not found")
+}
+
+func TestEnvironmentHandler_Handle_FromIndex(t *testing.T) {
+	handler := NewEnvironmentHandler(nil, 30*time.Second)
+	handler.AttachIndexers(map[string]*indexer.Indexer{"default": newSyncedIndexer(t)})
+
+	params := &mcp.CallToolParams{
+		Name: "show-environment",
+		Arguments: map[string]interface{}{
+			"id":     1001,
+			"source": "index",
+		},
+	}
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+
+	var response overlockv1beta1.QueryShowEnvironmentResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	require.NotNil(t, response.Environment)
+	assert.Equal(t, uint64(1), response.Environment.Provider)
+}
+
+func TestProvidersHandler_HandleList_IndexNotEnabled(t *testing.T) {
+	handler := NewProvidersHandler(&MockQueryClient{}, 30*time.Second)
+
+	params := &mcp.CallToolParams{
+		Name: "get-providers",
+		Arguments: map[string]interface{}{
+			"source": "index",
+		},
+	}
+
+	result, err := handler.HandleList(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "local index is not enabled")
+}
+
+func TestProvidersHandler_HandleList_InvalidSource(t *testing.T) {
+	handler := NewProvidersHandler(&MockQueryClient{}, 30*time.Second)
+
+	params := &mcp.CallToolParams{
+		Name: "get-providers",
+		Arguments: map[string]interface{}{
+			"source": "cache",
+		},
+	}
+
+	result, err := handler.HandleList(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	assert.Contains(t, decodeValidation(t, result).Error, "validation failed")
+}
+
+func TestIndexHandler_HandleStatus(t *testing.T) {
+	handler := NewIndexHandler(map[string]*indexer.Indexer{
+		"default": newSyncedIndexer(t),
+		"testnet": indexer.New("testnet", nil, indexer.Options{}),
+	})
+
+	result, err := handler.HandleStatus(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{Name: "sync-status"})
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+
+	var response SyncStatusResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	require.Len(t, response.Indexes, 2)
+
+	assert.Equal(t, "default", response.Indexes[0].Network)
+	assert.True(t, response.Indexes[0].Synced)
+	assert.Equal(t, 3, response.Indexes[0].ProviderCount)
+	assert.Equal(t, 1, response.Indexes[0].EnvironmentCount)
+
+	assert.Equal(t, "testnet", response.Indexes[1].Network)
+	assert.False(t, response.Indexes[1].Synced)
+}
+
+func TestIndexHandler_HandleStatus_Disabled(t *testing.T) {
+	handler := NewIndexHandler(nil)
+
+	result, err := handler.HandleStatus(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{Name: "sync-status"})
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "not enabled")
+}
+
+func TestIndexHandler_HandleStatus_UnknownNetwork(t *testing.T) {
+	handler := NewIndexHandler(map[string]*indexer.Indexer{"default": newSyncedIndexer(t)})
+
+	params := &mcp.CallToolParams{
+		Name:      "sync-status",
+		Arguments: map[string]interface{}{"network": "devnet"},
+	}
+
+	result, err := handler.HandleStatus(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	assert.Contains(t, decodeValidation(t, result).Error, "validation failed")
+}
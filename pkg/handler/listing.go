@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+	gogotypes "github.com/gogo/protobuf/types"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"google.golang.org/grpc"
+)
+
+// listPageSize is the page size used to walk a full provider or environment listing
+const listPageSize = 100
+
+// fullListMaxPages bounds the number of pages read per record type by tools that walk the whole network
+const fullListMaxPages = 200
+
+// listing is the result of walking every page of a listing
+type listing struct {
+	providers    []overlockv1beta1.Provider
+	environments []overlockv1beta1.Environment
+	truncated    bool // maxPages was reached before the last page
+}
+
+// listAll reads every provider and environment, optionally only those of creator, reading at
+// most maxPages pages of each. All pages are pinned to the block height of the first one so
+// the listing is a consistent snapshot.
+func (t *chainTarget) listAll(ctx context.Context, height int64, creator string, maxPages int) (listing, queryInfo, error) {
+	var result listing
+	providers, truncated, info, err := t.listProviders(ctx, height, creator, maxPages)
+	if err != nil {
+		return result, info, err
+	}
+	result.providers = providers
+	result.truncated = truncated
+
+	environments, truncated, info, err := t.listEnvironments(ctx, info.blockHeight, creator, maxPages)
+	if err != nil {
+		return result, info, err
+	}
+	result.environments = environments
+	result.truncated = result.truncated || truncated
+	return result, info, nil
+}
+
+// listProviders reads every provider, optionally only those of creator, reading at most
+// maxPages pages pinned to the block height of the first one. It reports whether maxPages was reached.
+func (t *chainTarget) listProviders(ctx context.Context, height int64, creator string, maxPages int) ([]overlockv1beta1.Provider, bool, queryInfo, error) {
+	var providers []overlockv1beta1.Provider
+	var truncated bool
+	info := t.info()
+	info.blockHeight = height
+
+	var nextKey []byte
+	for page := 0; ; page++ {
+		if page == maxPages {
+			truncated = true
+			break
+		}
+		req := &overlockv1beta1.QueryListProviderRequest{
+			Pagination: &query.PageRequest{Key: nextKey, Limit: listPageSize},
+		}
+		if creator != "" {
+			req.Creator = &gogotypes.StringValue{Value: creator}
+		}
+		response, pageInfo, err := t.execute(ctx, info.blockHeight, func(ctx context.Context, client overlockv1beta1.QueryClient, opts ...grpc.CallOption) (interface{}, error) {
+			return client.ListProvider(ctx, req, opts...)
+		})
+		if err != nil {
+			return providers, truncated, info, err
+		}
+		info.blockHeight = pageInfo.blockHeight
+		resp, ok := response.(*overlockv1beta1.QueryListProviderResponse)
+		if !ok || resp == nil {
+			return providers, truncated, info, errInvalidResponse
+		}
+		providers = append(providers, resp.Providers...)
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 || len(resp.Providers) == 0 {
+			break
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+	return providers, truncated, info, nil
+}
+
+// listEnvironments reads every environment, optionally only those of creator, reading at most
+// maxPages pages pinned to the block height of the first one. It reports whether maxPages was reached.
+func (t *chainTarget) listEnvironments(ctx context.Context, height int64, creator string, maxPages int) ([]overlockv1beta1.Environment, bool, queryInfo, error) {
+	var environments []overlockv1beta1.Environment
+	var truncated bool
+	info := t.info()
+	info.blockHeight = height
+
+	var nextKey []byte
+	for page := 0; ; page++ {
+		if page == maxPages {
+			truncated = true
+			break
+		}
+		req := &overlockv1beta1.QueryListEnvironmentRequest{
+			Creator:    creator,
+			Pagination: &query.PageRequest{Key: nextKey, Limit: listPageSize},
+		}
+		response, pageInfo, err := t.execute(ctx, info.blockHeight, func(ctx context.Context, client overlockv1beta1.QueryClient, opts ...grpc.CallOption) (interface{}, error) {
+			return client.ListEnvironment(ctx, req, opts...)
+		})
+		if err != nil {
+			return environments, truncated, info, err
+		}
+		info.blockHeight = pageInfo.blockHeight
+		resp, ok := response.(*overlockv1beta1.QueryListEnvironmentResponse)
+		if !ok || resp == nil {
+			return environments, truncated, info, errInvalidResponse
+		}
+		environments = append(environments, resp.Environments...)
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 || len(resp.Environments) == 0 {
+			break
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+	return environments, truncated, info, nil
+}
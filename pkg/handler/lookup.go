@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"overlock-mcp-server/pkg/watch"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/rs/zerolog"
+)
+
+// NameCandidatesResponse lists every record sharing a name that does not identify a single record
+type NameCandidatesResponse struct {
+	Name       string      `json:"name"`
+	Note       string      `json:"note"`
+	Candidates interface{} `json:"candidates"`
+}
+
+// recordSelector identifies the record a show tool returns, by ID or by metadata.name
+type recordSelector struct {
+	id      uint64
+	name    string
+	network string
+}
+
+// selectRecord reads the id, name and uri arguments of a show tool, exactly one of which
+// selects the record. A URI also selects the network, which must agree with the network
+// argument when both are given.
+func selectRecord(kind string, id int, name, uri, network string) (recordSelector, *mcp.CallToolResult) {
+	selector := recordSelector{id: uint64(id), name: name, network: network}
+	set := 0
+	for _, given := range []bool{id != 0, name != "", uri != ""} {
+		if given {
+			set++
+		}
+	}
+	switch {
+	case set == 0:
+		return selector, requiredArgument("id", fmt.Sprintf("%s ID is required (or pass name or uri instead)", kind))
+	case set > 1:
+		return selector, invalidArgument("id", "exclusive with name and uri", id, "pass only one of id, name or uri")
+	case uri == "":
+		return selector, nil
+	}
+
+	target, err := watch.ParseURI(uri)
+	if err != nil {
+		return selector, invalidArgument("uri", "overlock uri", uri, err.Error())
+	}
+	if target.Kind != kind {
+		return selector, invalidArgument("uri", kind+" uri", uri, fmt.Sprintf("expected a %s URI such as %s://<network>/%ss/<id>", kind, watch.URIScheme, kind))
+	}
+	if network != "" && network != target.Network {
+		return selector, invalidArgument("network", "matches uri", network, fmt.Sprintf("the uri selects network '%s'; omit network or pass the same one", target.Network))
+	}
+	selector.id = target.ID
+	selector.network = target.Network
+	return selector, nil
+}
+
+// matchName returns the records whose metadata.name equals name, falling back to a
+// case-insensitive comparison when no name matches exactly
+func matchName[T any](records []T, name string, nameOf func(T) string) []T {
+	var exact, folded []T
+	for _, record := range records {
+		switch recordName := nameOf(record); {
+		case recordName == name:
+			exact = append(exact, record)
+		case strings.EqualFold(recordName, name):
+			folded = append(folded, record)
+		}
+	}
+	if len(exact) > 0 {
+		return exact
+	}
+	return folded
+}
+
+// providerName returns the metadata.name of a provider
+func providerName(provider overlockv1beta1.Provider) string {
+	return provider.GetMetadata().GetName()
+}
+
+// environmentName returns the metadata.name of an environment
+func environmentName(environment overlockv1beta1.Environment) string {
+	return environment.GetMetadata().GetName()
+}
+
+// nameMatchResult answers a lookup by name that did not resolve to exactly one record:
+// a not-found text when nothing matched, or every candidate when the name is ambiguous.
+// searched is false when the listing was cut short, so a missing match may exist.
+func nameMatchResult(logger zerolog.Logger, info queryInfo, kind, name string, candidates interface{}, count int, searched bool, format string) (*mcp.CallToolResult, error) {
+	if count == 0 {
+		logger.Info().Str("name", name).Msgf("No %s with this name", kind)
+		text := fmt.Sprintf("No %s named '%s' found.", kind, name)
+		if !searched {
+			text += fmt.Sprintf(" Only the first %d pages of %ss were searched.", fullListMaxPages, kind)
+		}
+		return info.textResult(text), nil
+	}
+
+	logger.Info().Str("name", name).Int("candidates", count).Msgf("Ambiguous %s name", kind)
+	response := NameCandidatesResponse{
+		Name:       name,
+		Note:       fmt.Sprintf("%d %ss are named '%s'; call again with the id of the one you mean.", count, kind, name),
+		Candidates: candidates,
+	}
+	toolResult, err := info.formattedResult(response, format)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to marshal response")
+		return nil, fmt.Errorf("failed to marshal %s candidates: %w", kind, err)
+	}
+	return toolResult, nil
+}
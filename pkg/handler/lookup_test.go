@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"overlock-mcp-server/pkg/indexer"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// namedProviders answers ListProvider with providers carrying the given names, IDs starting at 1
+func namedProviders(client *MockQueryClient, names ...string) {
+	providers := make([]overlockv1beta1.Provider, len(names))
+	for i, name := range names {
+		providers[i] = overlockv1beta1.Provider{Id: uint64(i + 1), Metadata: &overlockv1beta1.Metadata{Name: name}}
+	}
+	client.On("ListProvider", mock.AnythingOfType("*context.timerCtx"), mock.Anything).Return(&overlockv1beta1.QueryListProviderResponse{
+		Providers:  providers,
+		Pagination: &query.PageResponse{},
+	}, nil)
+}
+
+func TestMatchName(t *testing.T) {
+	providers := []overlockv1beta1.Provider{
+		{Id: 1, Metadata: &overlockv1beta1.Metadata{Name: "Edge"}},
+		{Id: 2, Metadata: &overlockv1beta1.Metadata{Name: "edge"}},
+		{Id: 3, Metadata: &overlockv1beta1.Metadata{Name: "EDGE"}},
+		{Id: 4},
+	}
+
+	exact := matchName(providers, "edge", providerName)
+	require.Len(t, exact, 1, "an exact match wins over case-insensitive ones")
+	assert.Equal(t, uint64(2), exact[0].Id)
+
+	folded := matchName(providers, "eDgE", providerName)
+	assert.Len(t, folded, 3)
+
+	assert.Empty(t, matchName(providers, "core", providerName))
+}
+
+func TestSelectRecord(t *testing.T) {
+	selector, invalid := selectRecord("provider", 0, "", "overlock://testnet/providers/7", "")
+	require.Nil(t, invalid)
+	assert.Equal(t, recordSelector{id: 7, network: "testnet"}, selector)
+
+	selector, invalid = selectRecord("provider", 0, "edge", "", "mainnet")
+	require.Nil(t, invalid)
+	assert.Equal(t, recordSelector{name: "edge", network: "mainnet"}, selector)
+
+	tests := []struct {
+		name  string
+		id    int
+		title string
+		uri   string
+		net   string
+		field string
+	}{
+		{name: "nothing", field: "id"},
+		{name: "id and name", id: 1, title: "edge", field: "id"},
+		{name: "malformed uri", uri: "https://example.com/providers/1", field: "uri"},
+		{name: "environment uri", uri: "overlock://mainnet/environments/1", field: "uri"},
+		{name: "collection uri", uri: "overlock://mainnet/providers", field: "uri"},
+		{name: "network mismatch", uri: "overlock://mainnet/providers/1", net: "testnet", field: "network"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, invalid := selectRecord("provider", tt.id, tt.title, tt.uri, tt.net)
+			failure := decodeValidation(t, invalid)
+			require.Len(t, failure.Problems, 1)
+			assert.Equal(t, tt.field, failure.Problems[0].Field)
+		})
+	}
+}
+
+func TestProvidersHandler_HandleShow_ByName(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	namedProviders(mockClient, "test-provider-1", "test-provider-2")
+	mockClient.On("ShowProvider", mock.AnythingOfType("*context.timerCtx"), &overlockv1beta1.QueryShowProviderRequest{Id: 2}).Return(&overlockv1beta1.QueryShowProviderResponse{
+		Provider: &overlockv1beta1.Provider{Id: 2, Metadata: &overlockv1beta1.Metadata{Name: "test-provider-2"}},
+	}, nil)
+
+	handler := NewProvidersHandler(mockClient, 30*time.Second)
+
+	params := &mcp.CallToolParams{
+		Name:      "show-provider",
+		Arguments: map[string]interface{}{"name": "Test-Provider-2"},
+	}
+
+	result, err := handler.HandleShow(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+
+	var response overlockv1beta1.QueryShowProviderResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	require.NotNil(t, response.Provider)
+	assert.Equal(t, uint64(2), response.Provider.Id)
+	mockClient.AssertExpectations(t)
+}
+
+func TestProvidersHandler_HandleShow_AmbiguousName(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	namedProviders(mockClient, "edge", "core", "Edge")
+
+	handler := NewProvidersHandler(mockClient, 30*time.Second)
+
+	params := &mcp.CallToolParams{
+		Name:      "show-provider",
+		Arguments: map[string]interface{}{"name": "EDGE"},
+	}
+
+	result, err := handler.HandleShow(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+
+	var response struct {
+		Name       string                     `json:"name"`
+		Note       string                     `json:"note"`
+		Candidates []overlockv1beta1.Provider `json:"candidates"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	assert.Equal(t, "EDGE", response.Name)
+	assert.Contains(t, response.Note, "2 providers are named 'EDGE'")
+	require.Len(t, response.Candidates, 2)
+	assert.Equal(t, uint64(1), response.Candidates[0].Id)
+	assert.Equal(t, uint64(3), response.Candidates[1].Id)
+	mockClient.AssertNotCalled(t, "ShowProvider", mock.Anything, mock.Anything)
+}
+
+func TestProvidersHandler_HandleShow_NameNotFound(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	namedProviders(mockClient, "edge")
+
+	handler := NewProvidersHandler(mockClient, 30*time.Second)
+
+	params := &mcp.CallToolParams{
+		Name:      "show-provider",
+		Arguments: map[string]interface{}{"name": "core"},
+	}
+
+	result, err := handler.HandleShow(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Equal(t, "No provider named 'core' found.", textContent.Text)
+}
+
+func TestProvidersHandler_HandleShow_ByURI(t *testing.T) {
+	mainnetClient := &MockQueryClient{}
+	testnetClient := &MockQueryClient{}
+	testnetClient.On("ShowProvider", mock.AnythingOfType("*context.timerCtx"), &overlockv1beta1.QueryShowProviderRequest{Id: 7}).Return(&overlockv1beta1.QueryShowProviderResponse{
+		Provider: &overlockv1beta1.Provider{Id: 7},
+	}, nil)
+
+	handler := NewProvidersHandlerForNetworks(newTestRegistry(mainnetClient, testnetClient))
+
+	params := &mcp.CallToolParams{
+		Name:      "show-provider",
+		Arguments: map[string]interface{}{"uri": "overlock://testnet/providers/7"},
+	}
+
+	result, err := handler.HandleShow(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	assert.Equal(t, "testnet", result.Meta["network"])
+	testnetClient.AssertExpectations(t)
+	mainnetClient.AssertNotCalled(t, "ShowProvider", mock.Anything, mock.Anything)
+}
+
+func TestEnvironmentHandler_Handle_ByNameFromIndex(t *testing.T) {
+	client := &MockQueryClient{}
+	client.On("ListProvider", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryListProviderResponse{
+		Pagination: &query.PageResponse{},
+	}, nil)
+	client.On("ListEnvironment", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryListEnvironmentResponse{
+		Environments: []overlockv1beta1.Environment{
+			{Id: 1001, Metadata: &overlockv1beta1.Metadata{Name: "production-environment"}},
+			{Id: 1002, Metadata: &overlockv1beta1.Metadata{Name: "staging-environment"}},
+		},
+		Pagination: &query.PageResponse{},
+	}, nil)
+	idx := indexer.New("default", client, indexer.Options{})
+	require.NoError(t, idx.Sync(context.Background()))
+
+	handler := NewEnvironmentHandler(nil, 30*time.Second)
+	handler.AttachIndexers(map[string]*indexer.Indexer{"default": idx})
+
+	params := &mcp.CallToolParams{
+		Name:      "show-environment",
+		Arguments: map[string]interface{}{"name": "production-environment", "source": "index"},
+	}
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+
+	var response overlockv1beta1.QueryShowEnvironmentResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	require.NotNil(t, response.Environment)
+	assert.Equal(t, uint64(1001), response.Environment.Id)
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"overlock-mcp-server/pkg/network"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// networkProbeTimeout bounds the health check made for each network
+const networkProbeTimeout = 5 * time.Second
+
+// NetworkStatus describes one configured network in the list-networks response
+type NetworkStatus struct {
+	Name        string `json:"name"`
+	GRPCURL     string `json:"grpc_url"`
+	Default     bool   `json:"default"`
+	TLS         bool   `json:"tls"`
+	Timeout     string `json:"timeout"`
+	Status      string `json:"status"`
+	BlockHeight int64  `json:"block_height,omitempty"`
+	LatencyMs   int64  `json:"latency_ms,omitempty"`
+	Error       string `json:"error,omitempty"`
+
+	// Coalescing counts the calls made to the network and how many shared another caller's in-flight RPC
+	Coalescing *network.CoalesceStats `json:"coalescing,omitempty"`
+}
+
+// NetworksListResponse is the list-networks tool response
+type NetworksListResponse struct {
+	DefaultNetwork string          `json:"default_network"`
+	Networks       []NetworkStatus `json:"networks"`
+}
+
+// NetworksHandler handles the list-networks tool requests
+type NetworksHandler struct {
+	networks *network.Registry
+}
+
+// NewNetworksHandler creates a new networks handler
+func NewNetworksHandler(networks *network.Registry) *NetworksHandler {
+	return &NetworksHandler{
+		networks: networks,
+	}
+}
+
+// Handle processes the list-networks tool call
+func (h *NetworksHandler) Handle(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	// Create a logger with request context
+	logger := log.With().
+		Str("tool", "list-networks").
+		Str("request_id", fmt.Sprintf("%p", params)).
+		Logger()
+
+	start := time.Now()
+	logger.Info().Msg("Processing list-networks request")
+
+	names := h.networks.Names()
+	statuses := make([]NetworkStatus, len(names))
+
+	// Probe every network concurrently so one slow node does not delay the others
+	var wg sync.WaitGroup
+	for i, name := range names {
+		n, _ := h.networks.Get(name)
+		wg.Add(1)
+		go func(i int, n *network.Network) {
+			defer wg.Done()
+			statuses[i] = h.probe(ctx, n)
+		}(i, n)
+	}
+	wg.Wait()
+
+	logger.Info().
+		Int("network_count", len(statuses)).
+		Dur("duration", time.Since(start)).
+		Msg("Successfully checked networks")
+
+	response := NetworksListResponse{
+		DefaultNetwork: h.networks.DefaultName(),
+		Networks:       statuses,
+	}
+
+	responseJSON, err := renderJSON(response, nil)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to marshal response")
+		return nil, fmt.Errorf("failed to marshal networks response: %w", err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: responseJSON,
+			},
+		},
+	}, nil
+}
+
+// probe checks whether a network answers a minimal ListProvider query
+func (h *NetworksHandler) probe(ctx context.Context, n *network.Network) (status NetworkStatus) {
+	status = NetworkStatus{
+		Name:    n.Name,
+		GRPCURL: n.GRPCURL,
+		Default: n.Name == h.networks.DefaultName(),
+		TLS:     n.TLS,
+		Timeout: n.Timeout.String(),
+	}
+	if n.Coalescer != nil {
+		defer func() {
+			stats := n.Coalescer.Stats()
+			status.Coalescing = &stats
+		}()
+	}
+
+	if reason := n.RefusedReason(); reason != nil {
+		status.Status = "refused"
+		status.Error = reason.Error()
+		return status
+	}
+
+	if n.Client == nil {
+		status.Status = "disconnected"
+		status.Error = "gRPC connection is not available"
+		return status
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, networkProbeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	var header metadata.MD
+	_, err := n.Client.ListProvider(probeCtx, &overlockv1beta1.QueryListProviderRequest{
+		Pagination: &query.PageRequest{Limit: 1},
+	}, grpc.Header(&header))
+	status.LatencyMs = time.Since(start).Milliseconds()
+	status.BlockHeight = blockHeightFromHeader(header)
+	if err != nil {
+		status.Status = "unreachable"
+		status.Error = err.Error()
+		return status
+	}
+
+	status.Status = "ok"
+	return status
+}
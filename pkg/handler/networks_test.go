@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"overlock-mcp-server/pkg/network"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRegistry(mainnet, testnet overlockv1beta1.QueryClient) *network.Registry {
+	return network.NewRegistry("mainnet",
+		&network.Network{Name: "mainnet", GRPCURL: "mainnet:9090", Timeout: 30 * time.Second, Client: mainnet},
+		&network.Network{Name: "testnet", GRPCURL: "testnet:9090", Timeout: 10 * time.Second, Client: testnet},
+	)
+}
+
+func TestNetworksHandler_Handle(t *testing.T) {
+	mainnetClient := &MockQueryClient{}
+	testnetClient := &MockQueryClient{}
+
+	mainnetClient.On("ListProvider", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryListProviderResponse{}, nil)
+	testnetClient.On("ListProvider", mock.Anything, mock.Anything).Return((*overlockv1beta1.QueryListProviderResponse)(nil), errors.New("connection refused"))
+
+	handler := NewNetworksHandler(newTestRegistry(mainnetClient, testnetClient))
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{Name: "list-networks"})
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.Len(t, result.Content, 1)
+
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+
+	var response NetworksListResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	assert.Equal(t, "mainnet", response.DefaultNetwork)
+	require.Len(t, response.Networks, 2)
+
+	assert.Equal(t, "mainnet", response.Networks[0].Name)
+	assert.True(t, response.Networks[0].Default)
+	assert.Equal(t, "ok", response.Networks[0].Status)
+
+	assert.Equal(t, "testnet", response.Networks[1].Name)
+	assert.False(t, response.Networks[1].Default)
+	assert.Equal(t, "unreachable", response.Networks[1].Status)
+	assert.Contains(t, response.Networks[1].Error, "connection refused")
+
+	mainnetClient.AssertExpectations(t)
+	testnetClient.AssertExpectations(t)
+}
+
+func TestNetworksHandler_Handle_Disconnected(t *testing.T) {
+	handler := NewNetworksHandler(newTestRegistry(nil, nil))
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{Name: "list-networks"})
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+
+	var response NetworksListResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	for _, status := range response.Networks {
+		assert.Equal(t, "disconnected", status.Status)
+	}
+}
+
+func TestNetworksHandler_Handle_Refused(t *testing.T) {
+	mainnet := &network.Network{Name: "mainnet", GRPCURL: "mainnet:9090", Timeout: 5 * time.Second}
+	mainnet.Refuse(fmt.Errorf("%w: node reports 'overlock-testnet-3', expected 'overlock-1'", network.ErrChainIDMismatch))
+	handler := NewNetworksHandler(network.NewRegistry("mainnet", mainnet))
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{Name: "list-networks"})
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+
+	var response NetworksListResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	require.Len(t, response.Networks, 1)
+	assert.Equal(t, "refused", response.Networks[0].Status)
+	assert.Contains(t, response.Networks[0].Error, "chain-id mismatch")
+}
+
+func TestProvidersHandler_HandleShow_NamedNetwork(t *testing.T) {
+	mainnetClient := &MockQueryClient{}
+	testnetClient := &MockQueryClient{}
+	handler := NewProvidersHandlerForNetworks(newTestRegistry(mainnetClient, testnetClient))
+
+	testnetClient.On("ShowProvider", mock.AnythingOfType("*context.timerCtx"), mock.Anything).Return(&overlockv1beta1.QueryShowProviderResponse{
+		Provider: &overlockv1beta1.Provider{Id: 7, Creator: "overlock1testnet"},
+	}, nil)
+
+	params := &mcp.CallToolParams{
+		Name: "show-provider",
+		Arguments: map[string]interface{}{
+			"id":      7,
+			"network": "testnet",
+		},
+	}
+
+	result, err := handler.HandleShow(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "testnet", result.Meta["network"])
+
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	assert.Equal(t, "testnet", response["network"])
+
+	testnetClient.AssertExpectations(t)
+	mainnetClient.AssertNotCalled(t, "ShowProvider", mock.Anything, mock.Anything)
+}
+
+func TestProvidersHandler_HandleList_DefaultNetwork(t *testing.T) {
+	mainnetClient := &MockQueryClient{}
+	testnetClient := &MockQueryClient{}
+	handler := NewProvidersHandlerForNetworks(newTestRegistry(mainnetClient, testnetClient))
+
+	mainnetClient.On("ListProvider", mock.AnythingOfType("*context.timerCtx"), mock.Anything).Return(&overlockv1beta1.QueryListProviderResponse{}, nil)
+
+	result, err := handler.HandleList(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{Name: "get-providers"})
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "mainnet", result.Meta["network"])
+
+	mainnetClient.AssertExpectations(t)
+	testnetClient.AssertNotCalled(t, "ListProvider", mock.Anything, mock.Anything)
+}
+
+func TestEnvironmentHandler_Handle_UnknownNetwork(t *testing.T) {
+	handler := NewEnvironmentHandlerForNetworks(newTestRegistry(&MockQueryClient{}, &MockQueryClient{}))
+
+	params := &mcp.CallToolParams{
+		Name: "show-environment",
+		Arguments: map[string]interface{}{
+			"id":      1,
+			"network": "devnet",
+		},
+	}
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	failure := decodeValidation(t, result)
+	require.Len(t, failure.Problems, 1)
+	assert.Equal(t, "network", failure.Problems[0].Field)
+	assert.Equal(t, "devnet", failure.Problems[0].Received)
+	assert.Contains(t, failure.Error, "unknown network 'devnet'")
+	assert.Contains(t, failure.Error, "mainnet, testnet")
+}
+
+func TestProvidersHandler_HandleShow_RefusedAtRuntime(t *testing.T) {
+	mainnetClient := &MockQueryClient{}
+	registry := newTestRegistry(mainnetClient, &MockQueryClient{})
+	handler := NewProvidersHandlerForNetworks(registry)
+	mainnet, err := registry.Get("mainnet")
+	require.NoError(t, err)
+
+	mainnetClient.On("ShowProvider", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryShowProviderResponse{
+		Provider: &overlockv1beta1.Provider{Id: 7, Creator: "overlock1mainnet"},
+	}, nil)
+	params := &mcp.CallToolParams{Name: "show-provider", Arguments: map[string]interface{}{"id": 7}}
+
+	// Refusing the network after the handler was built keeps calls off it
+	mainnet.Refuse(network.ErrChainIDUnverified)
+	result, err := handler.HandleShow(context.Background(), &mcp.ServerSession{}, params)
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "gRPC connection to blockchain is not available")
+	mainnetClient.AssertNotCalled(t, "ShowProvider", mock.Anything, mock.Anything)
+
+	// Once verified, the network is served again
+	mainnet.Admit()
+	result, err = handler.HandleShow(context.Background(), &mcp.ServerSession{}, params)
+	require.NoError(t, err)
+	textContent, ok = result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "overlock1mainnet")
+	mainnetClient.AssertExpectations(t)
+}
@@ -2,62 +2,68 @@ package handler
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
+	"overlock-mcp-server/pkg/network"
+	"overlock-mcp-server/pkg/render"
+	"overlock-mcp-server/pkg/watch"
+
 	"github.com/Oudwins/zog"
 	"github.com/cosmos/cosmos-sdk/types/query"
 	gogotypes "github.com/gogo/protobuf/types"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
-	"github.com/sony/gobreaker"
+	"google.golang.org/grpc"
 )
 
 // ProvidersListInput represents the input parameters for the get-providers tool
 type ProvidersListInput struct {
-	Creator string `json:"creator,omitempty"`
-	Limit   int    `json:"limit,omitempty"`
-	Offset  int    `json:"offset,omitempty"`
+	Creator           string `json:"creator,omitempty"`
+	Limit             int    `json:"limit,omitempty"`
+	Offset            int    `json:"offset,omitempty"`
+	Network           string `json:"network,omitempty"`
+	Height            int64  `json:"height,omitempty"`
+	Source            string `json:"source,omitempty"`
+	Format            string `json:"format,omitempty"`
+	Fields            string `json:"fields,omitempty"`
+	MaxTokens         int    `json:"max_tokens,omitempty" zog:"max_tokens"`
+	MaxBytes          int    `json:"max_bytes,omitempty" zog:"max_bytes"`
+	ContinuationToken string `json:"continuation_token,omitempty" zog:"continuation_token"`
 }
 
+// providerFields are the dotted provider paths the fields argument accepts
+var providerFields = recordFields(overlockv1beta1.Provider{Metadata: &overlockv1beta1.Metadata{}})
+
 // ProviderShowInput represents the input parameters for the show-provider tool
 type ProviderShowInput struct {
-	Id int `json:"id,omitempty"`
+	Id      int    `json:"id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Uri     string `json:"uri,omitempty"`
+	Network string `json:"network,omitempty"`
+	Height  int64  `json:"height,omitempty"`
+	Source  string `json:"source,omitempty"`
+	Format  string `json:"format,omitempty"`
 }
 
 // ProvidersHandler handles both get-providers and show-provider tool requests
 type ProvidersHandler struct {
-	chainClient    overlockv1beta1.QueryClient
-	timeout        time.Duration
-	circuitBreaker *gobreaker.CircuitBreaker
+	chainBackend
 }
 
 // NewProvidersHandler creates a new providers handler
 func NewProvidersHandler(chainClient overlockv1beta1.QueryClient, timeout time.Duration) *ProvidersHandler {
-	// Configure circuit breaker
-	cb := gobreaker.NewCircuitBreaker(gobreaker.Settings{
-		Name:        "blockchain-client-providers",
-		MaxRequests: 3,
-		Interval:    30 * time.Second,
-		Timeout:     60 * time.Second,
-		ReadyToTrip: func(counts gobreaker.Counts) bool {
-			return counts.ConsecutiveFailures > 2
-		},
-		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
-			log.Warn().
-				Str("circuit_breaker", name).
-				Str("from", from.String()).
-				Str("to", to.String()).
-				Msg("Circuit breaker state changed")
-		},
-	})
+	return &ProvidersHandler{
+		chainBackend: newChainBackend("blockchain-client-providers", chainClient, timeout),
+	}
+}
 
+// NewProvidersHandlerForNetworks creates a providers handler serving every configured network
+func NewProvidersHandlerForNetworks(networks *network.Registry) *ProvidersHandler {
 	return &ProvidersHandler{
-		chainClient:    chainClient,
-		timeout:        timeout,
-		circuitBreaker: cb,
+		chainBackend: newChainBackendForNetworks("blockchain-client-providers", networks),
 	}
 }
 
@@ -72,15 +78,19 @@ func (h *ProvidersHandler) HandleList(ctx context.Context, session *mcp.ServerSe
 	start := time.Now()
 	logger.Info().Msg("Processing get-providers request")
 
-	// Apply timeout to the context
-	timeoutCtx, cancel := context.WithTimeout(ctx, h.timeout)
-	defer cancel()
-
 	// Define validation schema using Zog with default values
 	schema := zog.Struct(zog.Shape{
-		"creator": zog.String().Default(""),
-		"limit":   zog.Int().LTE(1000).Default(100),
-		"offset":  zog.Int().Default(0),
+		"creator":           zog.String().Default(""),
+		"limit":             zog.Int().LTE(1000).Default(100),
+		"offset":            zog.Int().GTE(0).Default(0),
+		"network":           zog.String().Default(""),
+		"height":            zog.Int64().GTE(0).Default(0),
+		"source":            zog.String().OneOf([]string{sourceChain, sourceIndex}).Default(sourceChain),
+		"format":            zog.String().OneOf(render.ListFormats).Default(render.FormatJSON),
+		"fields":            zog.String().Default(""),
+		"maxTokens":         zog.Int().GTE(0).Default(0),
+		"maxBytes":          zog.Int().GTE(0).Default(0),
+		"continuationToken": zog.String().Default(""),
 	})
 
 	// Validate input parameters (always parse to apply defaults)
@@ -95,7 +105,27 @@ func (h *ProvidersHandler) HandleList(ctx context.Context, session *mcp.ServerSe
 	errs := schema.Parse(arguments, &input)
 	if errs != nil {
 		logger.Error().Interface("errors", errs).Msg("Input validation failed")
-		return nil, fmt.Errorf("validation failed: %v", errs)
+		return invalidInput(errs), nil
+	}
+
+	target, err := h.resolve(input.Network)
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("network", "known network", input.Network, err.Error()), nil
+	}
+	fields, err := render.ParseFields(input.Fields, providerFields)
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("fields", "known fields", input.Fields, err.Error()), nil
+	}
+	// A continuation token resumes a truncated response where it stopped
+	if input.ContinuationToken != "" {
+		resume, err := decodeContinuation(input.ContinuationToken)
+		if err != nil {
+			logger.Error().Err(err).Msg("Input validation failed")
+			return invalidArgument("continuation_token", "token of a previous response", input.ContinuationToken, err.Error()), nil
+		}
+		input.Offset, input.Height = resume.Offset, resume.Height
 	}
 	logger.Debug().Interface("parsed_input", input).Msg("Input validation successful")
 
@@ -118,60 +148,35 @@ func (h *ProvidersHandler) HandleList(ctx context.Context, session *mcp.ServerSe
 
 	// Log request parameters
 	logger.Info().
+		Str("network", target.network).
+		Int64("height", input.Height).
 		Uint64("limit", req.Pagination.Limit).
 		Uint64("offset", req.Pagination.Offset).
 		Interface("creator", req.Creator).
 		Msg("Fetching providers from blockchain")
 
+	// Serve from the local index when requested
+	if input.Source == sourceIndex {
+		return h.listFromIndex(logger, target, input, fields)
+	}
+
 	// Check if chain client is available
-	if h.chainClient == nil {
+	if target.chainClient == nil {
 		logger.Error().Msg("gRPC client is not available")
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: "Error: gRPC connection to blockchain is not available. Please check the connection and try again.",
-				},
-			},
-		}, nil
-	}
-
-	// Fetch providers from the chain using circuit breaker protection
-	result, err := h.circuitBreaker.Execute(func() (interface{}, error) {
-		return h.chainClient.ListProvider(timeoutCtx, req)
-	})
+		return target.unavailableResult(), nil
+	}
 
+	// Fetch providers from the chain using circuit breaker protection, falling back to the last-known-good response
+	result, info, err := target.executeCached(ctx, cacheKey("ListProvider", input.Height, req), input.Height, func(ctx context.Context, client overlockv1beta1.QueryClient, opts ...grpc.CallOption) (interface{}, error) {
+		return client.ListProvider(ctx, req, opts...)
+	})
 	if err != nil {
-		logger.Info().Err(err).Msg("Failed to connect to gRPC server - blockchain service unavailable")
-		// Check if it's a circuit breaker error
-		if err == gobreaker.ErrOpenState {
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					&mcp.TextContent{
-						Text: "Blockchain service is currently unavailable (circuit breaker protection active). Please try again later.",
-					},
-				},
-			}, nil
-		}
-		// Return a user-friendly response instead of propagating the error
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: "Unable to connect to blockchain service. The service may be temporarily unavailable. Please check your connection and try again later.",
-				},
-			},
-		}, nil
+		return target.failureResult(logger, err), nil
 	}
 
 	chainResponse, ok := result.(*overlockv1beta1.QueryListProviderResponse)
 	if !ok || chainResponse == nil {
-		logger.Error().Msg("Received invalid response from blockchain service")
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: "Received invalid response from blockchain service. Please try again later.",
-				},
-			},
-		}, nil
+		return target.invalidResponseResult(logger), nil
 	}
 
 	// Log successful response
@@ -179,23 +184,18 @@ func (h *ProvidersHandler) HandleList(ctx context.Context, session *mcp.ServerSe
 	duration := time.Since(start)
 	logger.Info().
 		Int("provider_count", providerCount).
+		Int64("block_height", info.blockHeight).
 		Dur("duration", duration).
 		Msg("Successfully fetched providers")
 
 	// Use the official API response directly
-	responseJSON, err := json.MarshalIndent(chainResponse, "", "  ")
+	toolResult, err := info.listResult(chainResponse, "providers", providersListOptions(input, fields, info.blockHeight))
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to marshal response")
 		return nil, fmt.Errorf("failed to marshal providers response: %w", err)
 	}
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			&mcp.TextContent{
-				Text: string(responseJSON),
-			},
-		},
-	}, nil
+	return toolResult, nil
 }
 
 // HandleShow processes the show-provider tool call
@@ -209,13 +209,15 @@ func (h *ProvidersHandler) HandleShow(ctx context.Context, session *mcp.ServerSe
 	start := time.Now()
 	logger.Info().Msg("Processing show-provider request")
 
-	// Apply timeout to the context
-	timeoutCtx, cancel := context.WithTimeout(ctx, h.timeout)
-	defer cancel()
-
 	// Define validation schema using Zog
 	schema := zog.Struct(zog.Shape{
-		"id": zog.Int().GTE(1),
+		"id":      zog.Int().GTE(1),
+		"name":    zog.String().Default(""),
+		"uri":     zog.String().Default(""),
+		"network": zog.String().Default(""),
+		"height":  zog.Int64().GTE(0).Default(0),
+		"source":  zog.String().OneOf([]string{sourceChain, sourceIndex}).Default(sourceChain),
+		"format":  zog.String().OneOf(render.RecordFormats).Default(render.FormatJSON),
 	})
 
 	// Validate input parameters
@@ -230,115 +232,200 @@ func (h *ProvidersHandler) HandleShow(ctx context.Context, session *mcp.ServerSe
 	errs := schema.Parse(arguments, &input)
 	if errs != nil {
 		logger.Error().Interface("errors", errs).Msg("Input validation failed")
-		return nil, fmt.Errorf("validation failed: %v", errs)
+		return invalidInput(errs), nil
 	}
 
-	// Check if ID was provided (required field)
-	if input.Id == 0 {
-		logger.Error().Msg("Provider ID is required")
-		return nil, fmt.Errorf("validation failed: provider ID is required")
+	// Exactly one of id, name or uri selects the provider
+	selector, invalid := selectRecord(watch.KindProvider, input.Id, input.Name, input.Uri, input.Network)
+	if invalid != nil {
+		logger.Error().Msg("Input validation failed")
+		return invalid, nil
+	}
+
+	target, err := h.resolve(selector.network)
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("network", "known network", selector.network, err.Error()), nil
 	}
 
 	logger.Debug().Interface("parsed_input", input).Msg("Input validation successful")
 
+	// Resolve a name to the ID of the single provider carrying it
+	if selector.name != "" {
+		id, result, err := h.providerByName(ctx, logger, target, input)
+		if result != nil || err != nil {
+			return result, err
+		}
+		selector.id = id
+	}
+
 	// Create the request
 	req := &overlockv1beta1.QueryShowProviderRequest{
-		Id: uint64(input.Id),
+		Id: selector.id,
 	}
 
 	// Log request parameters
 	logger.Info().
+		Str("network", target.network).
+		Int64("height", input.Height).
 		Uint64("provider_id", req.Id).
 		Msg("Fetching provider from blockchain")
 
+	// Serve from the local index when requested
+	if input.Source == sourceIndex {
+		return h.showFromIndex(logger, target, req.Id, input.Format)
+	}
+
 	// Check if chain client is available
-	if h.chainClient == nil {
+	if target.chainClient == nil {
 		logger.Error().Msg("gRPC client is not available")
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: "Error: gRPC connection to blockchain is not available. Please check the connection and try again.",
-				},
-			},
-		}, nil
-	}
-
-	// Fetch provider from the chain using circuit breaker protection
-	result, err := h.circuitBreaker.Execute(func() (interface{}, error) {
-		return h.chainClient.ShowProvider(timeoutCtx, req)
-	})
+		return target.unavailableResult(), nil
+	}
 
+	// Fetch provider from the chain using circuit breaker protection, falling back to the last-known-good response
+	result, info, err := target.executeCached(ctx, cacheKey("ShowProvider", input.Height, req), input.Height, func(ctx context.Context, client overlockv1beta1.QueryClient, opts ...grpc.CallOption) (interface{}, error) {
+		return client.ShowProvider(ctx, req, opts...)
+	})
 	if err != nil {
-		logger.Info().Err(err).Msg("Failed to connect to gRPC server - blockchain service unavailable")
-		// Check if it's a circuit breaker error
-		if err == gobreaker.ErrOpenState {
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					&mcp.TextContent{
-						Text: "Blockchain service is currently unavailable (circuit breaker protection active). Please try again later.",
-					},
-				},
-			}, nil
-		}
-		// Return a user-friendly response instead of propagating the error
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: "Unable to connect to blockchain service. The service may be temporarily unavailable. Please check your connection and try again later.",
-				},
-			},
-		}, nil
+		return target.failureResult(logger, err), nil
 	}
 
 	chainResponse, ok := result.(*overlockv1beta1.QueryShowProviderResponse)
 	if !ok || chainResponse == nil {
-		logger.Error().Msg("Received invalid response from blockchain service")
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: "Received invalid response from blockchain service. Please try again later.",
-				},
-			},
-		}, nil
+		return target.invalidResponseResult(logger), nil
 	}
 
 	// Check if provider was found
 	if chainResponse.Provider == nil {
 		logger.Info().Uint64("provider_id", req.Id).Msg("Provider not found")
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Provider with ID '%d' not found.", req.Id),
-				},
-			},
-		}, nil
+		return info.textResult(fmt.Sprintf("Provider with ID '%d' not found.", req.Id)), nil
 	}
 
 	// Log successful response
 	duration := time.Since(start)
 	logger.Info().
 		Uint64("provider_id", req.Id).
+		Int64("block_height", info.blockHeight).
 		Dur("duration", duration).
 		Msg("Successfully fetched provider")
 
 	// Use the official API response directly
-	responseJSON, err := json.MarshalIndent(chainResponse, "", "  ")
+	toolResult, err := info.formattedResult(chainResponse, input.Format)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to marshal response")
 		return nil, fmt.Errorf("failed to marshal provider response: %w", err)
 	}
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			&mcp.TextContent{
-				Text: string(responseJSON),
-			},
+	return toolResult, nil
+}
+
+// providerByName resolves a provider name to an ID by walking the provider listing of the
+// requested source. It returns a result instead when no provider or several carry the name.
+func (h *ProvidersHandler) providerByName(ctx context.Context, logger zerolog.Logger, target *chainTarget, input ProviderShowInput) (uint64, *mcp.CallToolResult, error) {
+	var providers []overlockv1beta1.Provider
+	var info queryInfo
+	var truncated bool
+	if input.Source == sourceIndex {
+		snapshot, indexInfo, unavailable := target.snapshot(logger)
+		if unavailable != nil {
+			return 0, unavailable, nil
+		}
+		providers, info = snapshot.Providers, indexInfo
+	} else {
+		if target.chainClient == nil {
+			logger.Error().Msg("gRPC client is not available")
+			return 0, target.unavailableResult(), nil
+		}
+		var err error
+		providers, truncated, info, err = target.listProviders(ctx, input.Height, "", fullListMaxPages)
+		if err != nil {
+			return 0, target.failureResult(logger, err), nil
+		}
+	}
+
+	matches := matchName(providers, input.Name, providerName)
+	if len(matches) == 1 {
+		return matches[0].Id, nil, nil
+	}
+	result, err := nameMatchResult(logger, info, watch.KindProvider, input.Name, matches, len(matches), !truncated, input.Format)
+	return 0, result, err
+}
+
+// listFromIndex answers get-providers from the target's local index
+func (h *ProvidersHandler) listFromIndex(logger zerolog.Logger, target *chainTarget, input ProvidersListInput, fields []string) (*mcp.CallToolResult, error) {
+	snapshot, info, unavailable := target.snapshot(logger)
+	if unavailable != nil {
+		return unavailable, nil
+	}
+
+	var matched []overlockv1beta1.Provider
+	for _, provider := range snapshot.Providers {
+		if input.Creator == "" || provider.Creator == input.Creator {
+			matched = append(matched, provider)
+		}
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	page := []overlockv1beta1.Provider{}
+	if input.Offset < len(matched) {
+		page = matched[input.Offset:min(input.Offset+limit, len(matched))]
+	}
+
+	logger.Info().
+		Int("provider_count", len(page)).
+		Dur("staleness", snapshot.Staleness()).
+		Msg("Served providers from index")
+
+	toolResult, err := info.listResult(&overlockv1beta1.QueryListProviderResponse{
+		Providers:  page,
+		Pagination: &query.PageResponse{Total: uint64(len(matched))},
+	}, "providers", providersListOptions(input, fields, 0))
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to marshal response")
+		return nil, fmt.Errorf("failed to marshal providers response: %w", err)
+	}
+	return toolResult, nil
+}
+
+// providersListOptions builds the get-providers rendering options; continuation tokens
+// resume at the first omitted provider, pinned to the height the page was read at
+func providersListOptions(input ProvidersListInput, fields []string, height int64) listOptions {
+	return listOptions{
+		format:   input.Format,
+		fields:   fields,
+		maxBytes: byteBudget(input.MaxTokens, input.MaxBytes),
+		continuation: func(returned int) string {
+			return encodeContinuation(continuation{Offset: input.Offset + returned, Height: height})
 		},
-	}, nil
+	}
+}
+
+// showFromIndex answers show-provider from the target's local index
+func (h *ProvidersHandler) showFromIndex(logger zerolog.Logger, target *chainTarget, id uint64, format string) (*mcp.CallToolResult, error) {
+	snapshot, info, unavailable := target.snapshot(logger)
+	if unavailable != nil {
+		return unavailable, nil
+	}
+
+	provider, ok := snapshot.Provider(id)
+	if !ok {
+		logger.Info().Uint64("provider_id", id).Msg("Provider not found in index")
+		return info.textResult(fmt.Sprintf("Provider with ID '%d' not found.", id)), nil
+	}
+
+	toolResult, err := info.formattedResult(&overlockv1beta1.QueryShowProviderResponse{Provider: &provider}, format)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to marshal response")
+		return nil, fmt.Errorf("failed to marshal provider response: %w", err)
+	}
+	return toolResult, nil
 }
 
 // Handle processes tool calls and routes them to the appropriate handler method
 // This maintains backward compatibility with the existing MCP tool registration
 func (h *ProvidersHandler) Handle(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
 	return h.HandleList(ctx, session, params)
-}
\ No newline at end of file
+}
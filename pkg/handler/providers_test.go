@@ -3,9 +3,11 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/cosmos/cosmos-sdk/types/query"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
 	"github.com/sony/gobreaker"
@@ -134,9 +136,8 @@ func TestProvidersHandler_HandleList_ValidationError(t *testing.T) {
 
 	result, err := handler.HandleList(ctx, session, params)
 
-	assert.Error(t, err)
-	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "validation failed")
+	require.NoError(t, err)
+	assert.Contains(t, decodeValidation(t, result).Error, "validation failed")
 }
 
 func TestProvidersHandler_HandleList_DefaultValues(t *testing.T) {
@@ -305,9 +306,8 @@ func TestProvidersHandler_HandleShow_ValidationError_MissingID(t *testing.T) {
 
 	result, err := handler.HandleShow(ctx, session, params)
 
-	assert.Error(t, err)
-	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "validation failed")
+	require.NoError(t, err)
+	assert.Contains(t, decodeValidation(t, result).Error, "validation failed")
 }
 
 func TestProvidersHandler_HandleShow_ValidationError_InvalidID(t *testing.T) {
@@ -326,9 +326,8 @@ func TestProvidersHandler_HandleShow_ValidationError_InvalidID(t *testing.T) {
 
 	result, err := handler.HandleShow(ctx, session, params)
 
-	assert.Error(t, err)
-	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "validation failed")
+	require.NoError(t, err)
+	assert.Contains(t, decodeValidation(t, result).Error, "validation failed")
 }
 
 func TestProvidersHandler_HandleShow_ValidationError_InvalidType(t *testing.T) {
@@ -347,9 +346,8 @@ func TestProvidersHandler_HandleShow_ValidationError_InvalidType(t *testing.T) {
 
 	result, err := handler.HandleShow(ctx, session, params)
 
-	assert.Error(t, err)
-	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "validation failed")
+	require.NoError(t, err)
+	assert.Contains(t, decodeValidation(t, result).Error, "validation failed")
 }
 
 func TestProvidersHandler_HandleShow_ProviderNotFound(t *testing.T) {
@@ -489,4 +487,184 @@ func TestProvidersHandler_Handle_BackwardCompatibility(t *testing.T) {
 	require.NotNil(t, result)
 
 	mockClient.AssertExpectations(t)
-}
\ No newline at end of file
+}
+
+func TestProvidersHandler_HandleList_ProtoJSON(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewProvidersHandler(mockClient, 30*time.Second)
+
+	expectedResponse := &overlockv1beta1.QueryListProviderResponse{
+		Providers: []overlockv1beta1.Provider{
+			{
+				Id:      1,
+				Creator: "test-creator",
+			},
+		},
+		Pagination: &query.PageResponse{NextKey: []byte{0x01, 0xff}, Total: 5},
+	}
+
+	mockClient.On("ListProvider", mock.AnythingOfType("*context.timerCtx"), mock.Anything).Return(expectedResponse, nil)
+
+	result, err := handler.HandleList(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{Name: "get-providers"})
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	assert.Contains(t, response, "providers")
+	assert.NotContains(t, response, "Providers")
+	assert.Equal(t, "01ff", response["pagination"].(map[string]interface{})["next_key"])
+	provider := response["providers"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "", provider["ip"])
+	assert.Nil(t, provider["register_time"])
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestProvidersHandler_HandleList_CSVFormat(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewProvidersHandler(mockClient, 30*time.Second)
+
+	expectedResponse := &overlockv1beta1.QueryListProviderResponse{
+		Providers: []overlockv1beta1.Provider{
+			{Id: 1, Creator: "creator-a", Ip: "203.0.113.10", Port: 8080},
+			{Id: 2, Creator: "creator-b"},
+		},
+	}
+
+	mockClient.On("ListProvider", mock.AnythingOfType("*context.timerCtx"), mock.Anything).Return(expectedResponse, nil)
+
+	params := &mcp.CallToolParams{
+		Name:      "get-providers",
+		Arguments: map[string]interface{}{"format": "csv"},
+	}
+
+	result, err := handler.HandleList(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.True(t, strings.HasPrefix(textContent.Text, "metadata,id,creator,ip,port,"))
+	assert.Contains(t, textContent.Text, "\n,1,creator-a,203.0.113.10,8080,")
+	assert.Contains(t, textContent.Text, "\n,2,creator-b,,0,")
+	// The query metadata is still attached to the result
+	assert.Equal(t, "default", result.Meta["network"])
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestProvidersHandler_HandleShow_MarkdownFormat(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewProvidersHandler(mockClient, 30*time.Second)
+
+	expectedResponse := &overlockv1beta1.QueryShowProviderResponse{
+		Provider: &overlockv1beta1.Provider{
+			Id:       1,
+			Creator:  "test-creator",
+			Metadata: &overlockv1beta1.Metadata{Name: "alpha"},
+		},
+	}
+
+	mockClient.On("ShowProvider", mock.AnythingOfType("*context.timerCtx"), mock.Anything).Return(expectedResponse, nil)
+
+	params := &mcp.CallToolParams{
+		Name:      "show-provider",
+		Arguments: map[string]interface{}{"id": 1, "format": "markdown"},
+	}
+
+	result, err := handler.HandleShow(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "| network | default |")
+	assert.Contains(t, textContent.Text, "### provider\n\n| field | value |\n|---|---|\n| metadata.name | alpha |\n")
+	assert.Contains(t, textContent.Text, "| creator | test-creator |")
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestProvidersHandler_HandleShow_CSVFormatRejected(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewProvidersHandler(mockClient, 30*time.Second)
+
+	params := &mcp.CallToolParams{
+		Name:      "show-provider",
+		Arguments: map[string]interface{}{"id": 1, "format": "csv"},
+	}
+
+	result, err := handler.HandleShow(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	assert.Contains(t, decodeValidation(t, result).Error, "validation failed")
+	mockClient.AssertNotCalled(t, "ShowProvider")
+}
+
+func TestProvidersHandler_HandleList_FieldsAndContinuation(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewProvidersHandler(mockClient, 30*time.Second)
+
+	expectedResponse := &overlockv1beta1.QueryListProviderResponse{
+		Providers: []overlockv1beta1.Provider{
+			{Id: 26, Creator: "creator-a", CountryCode: "DE"},
+		},
+	}
+
+	mockClient.On("ListProvider", mock.AnythingOfType("*context.timerCtx"), mock.MatchedBy(func(req *overlockv1beta1.QueryListProviderRequest) bool {
+		return req.Pagination.Offset == 25 && req.Pagination.Limit == 10
+	})).Return(expectedResponse, nil)
+
+	params := &mcp.CallToolParams{
+		Name: "get-providers",
+		Arguments: map[string]interface{}{
+			"limit":              10,
+			"fields":             "id,country_code",
+			"continuation_token": encodeContinuation(continuation{Offset: 25}),
+		},
+	}
+
+	result, err := handler.HandleList(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": 26.0, "country_code": "DE"}}, response["providers"])
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestProvidersHandler_HandleList_UnknownField(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewProvidersHandler(mockClient, 30*time.Second)
+
+	params := &mcp.CallToolParams{
+		Name:      "get-providers",
+		Arguments: map[string]interface{}{"fields": "id,region"},
+	}
+
+	result, err := handler.HandleList(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	assert.Contains(t, decodeValidation(t, result).Error, "unknown field 'region'")
+	mockClient.AssertNotCalled(t, "ListProvider")
+}
+
+func TestProvidersHandler_HandleList_InvalidContinuationToken(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewProvidersHandler(mockClient, 30*time.Second)
+
+	params := &mcp.CallToolParams{
+		Name:      "get-providers",
+		Arguments: map[string]interface{}{"continuation_token": "garbage!"},
+	}
+
+	result, err := handler.HandleList(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	assert.Contains(t, decodeValidation(t, result).Error, "invalid continuation_token")
+}
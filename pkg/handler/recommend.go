@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"overlock-mcp-server/pkg/network"
+	"overlock-mcp-server/pkg/recommend"
+
+	"github.com/Oudwins/zog"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/rs/zerolog/log"
+)
+
+// RecommendInput represents the input parameters for the recommend-providers tool
+type RecommendInput struct {
+	Countries          []string `json:"countries,omitempty"`
+	EnvironmentType    string   `json:"environment_type,omitempty" zog:"environment_type"`
+	MaxEnvironments    int      `json:"max_environments,omitempty" zog:"max_environments"`
+	IncludeUnavailable bool     `json:"include_unavailable,omitempty" zog:"include_unavailable"`
+	Limit              int      `json:"limit,omitempty"`
+	Network            string   `json:"network,omitempty"`
+	Height             int64    `json:"height,omitempty"`
+	Source             string   `json:"source,omitempty"`
+}
+
+// RecommendResponse is the response of the recommend-providers tool
+type RecommendResponse struct {
+	recommend.Result
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// RecommendHandler handles the recommend-providers tool requests
+type RecommendHandler struct {
+	chainBackend
+}
+
+// NewRecommendHandler creates a new provider recommendation handler
+func NewRecommendHandler(chainClient overlockv1beta1.QueryClient, timeout time.Duration) *RecommendHandler {
+	return &RecommendHandler{
+		chainBackend: newChainBackend("blockchain-client-recommend", chainClient, timeout),
+	}
+}
+
+// NewRecommendHandlerForNetworks creates a provider recommendation handler serving every configured network
+func NewRecommendHandlerForNetworks(networks *network.Registry) *RecommendHandler {
+	return &RecommendHandler{
+		chainBackend: newChainBackendForNetworks("blockchain-client-recommend", networks),
+	}
+}
+
+// Handle processes the recommend-providers tool call
+func (h *RecommendHandler) Handle(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	// Create a logger with request context
+	logger := log.With().
+		Str("tool", "recommend-providers").
+		Str("request_id", fmt.Sprintf("%p", params)).
+		Logger()
+
+	start := time.Now()
+	logger.Info().Msg("Processing recommend-providers request")
+
+	// Define validation schema using Zog
+	schema := zog.Struct(zog.Shape{
+		"countries":          zog.Slice(zog.String().Min(1)),
+		"environmentType":    zog.String().Default(""),
+		"maxEnvironments":    zog.Int().GTE(0),
+		"includeUnavailable": zog.Bool().Default(false),
+		"limit":              zog.Int().GTE(1).LTE(50).Default(5),
+		"network":            zog.String().Default(""),
+		"height":             zog.Int64().GTE(0).Default(0),
+		"source":             zog.String().OneOf([]string{sourceChain, sourceIndex}).Default(sourceChain),
+	})
+
+	// Validate input parameters
+	var input RecommendInput
+	arguments := params.Arguments
+	if arguments == nil {
+		arguments = make(map[string]interface{})
+	}
+
+	logger.Debug().Interface("arguments", arguments).Msg("Validating input arguments")
+	// Parse and validate the arguments
+	errs := schema.Parse(arguments, &input)
+	if errs != nil {
+		logger.Error().Interface("errors", errs).Msg("Input validation failed")
+		return invalidInput(errs), nil
+	}
+
+	requirements := recommend.Requirements{
+		Countries:          input.Countries,
+		EnvironmentType:    input.EnvironmentType,
+		MaxEnvironments:    -1,
+		IncludeUnavailable: input.IncludeUnavailable,
+	}
+	if _, ok := argument(arguments, "max_environments"); ok {
+		requirements.MaxEnvironments = input.MaxEnvironments
+	}
+	annotations, err := annotationsArgument(arguments)
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		received, _ := argument(arguments, "annotations")
+		return invalidArgument("annotations", "object of scalars", received, err.Error()), nil
+	}
+	requirements.Annotations = annotations
+
+	target, err := h.resolve(input.Network)
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("network", "known network", input.Network, err.Error()), nil
+	}
+
+	logger.Info().
+		Str("network", target.network).
+		Int64("height", input.Height).
+		Strs("countries", requirements.Countries).
+		Str("environment_type", requirements.EnvironmentType).
+		Interface("annotations", requirements.Annotations).
+		Int("max_environments", requirements.MaxEnvironments).
+		Msg("Ranking providers")
+
+	var records listing
+	var info queryInfo
+	if input.Source == sourceIndex {
+		snapshot, indexInfo, unavailable := target.snapshot(logger)
+		if unavailable != nil {
+			return unavailable, nil
+		}
+		info = indexInfo
+		records = listing{providers: snapshot.Providers, environments: snapshot.Environments}
+	} else {
+		// Check if chain client is available
+		if target.chainClient == nil {
+			logger.Error().Msg("gRPC client is not available")
+			return target.unavailableResult(), nil
+		}
+		records, info, err = target.listAll(ctx, input.Height, "", fullListMaxPages)
+		if err != nil {
+			return target.failureResult(logger, err), nil
+		}
+	}
+
+	result := recommend.Rank(records.providers, records.environments, requirements, input.Limit)
+
+	logger.Info().
+		Int("evaluated", result.Evaluated).
+		Int("eligible", result.Eligible).
+		Int("candidates", len(result.Candidates)).
+		Int64("block_height", info.blockHeight).
+		Dur("duration", time.Since(start)).
+		Msg("Successfully ranked providers")
+
+	toolResult, err := info.jsonResult(RecommendResponse{Result: result, Truncated: records.truncated})
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to marshal response")
+		return nil, fmt.Errorf("failed to marshal recommendation response: %w", err)
+	}
+	return toolResult, nil
+}
+
+// annotationsArgument reads the required annotation values. Non-string values are compared
+// in their JSON form, the way they appear in provider annotations.
+func annotationsArgument(arguments any) (map[string]string, error) {
+	value, ok := argument(arguments, "annotations")
+	if !ok {
+		return nil, nil
+	}
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("annotations must be an object of annotation names to values")
+	}
+	annotations := make(map[string]string, len(object))
+	for key, v := range object {
+		switch v := v.(type) {
+		case string:
+			annotations[key] = v
+		case map[string]interface{}, []interface{}, nil:
+			return nil, fmt.Errorf("annotation '%s' must be a string, number or boolean", key)
+		default:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("annotation '%s': %w", key, err)
+			}
+			annotations[key] = string(encoded)
+		}
+	}
+	return annotations, nil
+}
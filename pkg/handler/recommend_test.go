@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newRecommendClient() *MockQueryClient {
+	client := &MockQueryClient{}
+	client.On("ListProvider", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryListProviderResponse{
+		Providers: []overlockv1beta1.Provider{
+			{
+				Id: 1, CountryCode: "DE", EnvironmentType: "production", Availability: "available", Ip: "10.0.0.1", Port: 8080,
+				Metadata: &overlockv1beta1.Metadata{Annotations: `{"region":"eu-central-1"}`},
+			},
+			{
+				Id: 2, CountryCode: "DE", EnvironmentType: "production", Availability: "available", Ip: "10.0.0.2", Port: 8080,
+				Metadata: &overlockv1beta1.Metadata{Annotations: `{"region":"eu-central-1"}`},
+			},
+			{
+				Id: 3, CountryCode: "US", EnvironmentType: "production", Availability: "available", Ip: "10.0.0.3", Port: 8080,
+				Metadata: &overlockv1beta1.Metadata{Annotations: `{"region":"us-east-1"}`},
+			},
+		},
+		Pagination: &query.PageResponse{},
+	}, nil)
+	client.On("ListEnvironment", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryListEnvironmentResponse{
+		Environments: []overlockv1beta1.Environment{{Id: 1001, Provider: 1}},
+		Pagination:   &query.PageResponse{},
+	}, nil)
+	return client
+}
+
+func decodeRecommendation(t *testing.T, result *mcp.CallToolResult) RecommendResponse {
+	require.NotNil(t, result)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	var response RecommendResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	return response
+}
+
+func TestRecommendHandler_Handle(t *testing.T) {
+	handler := NewRecommendHandler(newRecommendClient(), 30*time.Second)
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name: "recommend-providers",
+		Arguments: map[string]interface{}{
+			"countries":        []interface{}{"DE"},
+			"environment_type": "production",
+			"annotations":      map[string]interface{}{"region": "eu-central-1"},
+		},
+	})
+
+	require.NoError(t, err)
+	response := decodeRecommendation(t, result)
+	assert.Equal(t, 3, response.Evaluated)
+	assert.Equal(t, 1, response.Excluded["country"])
+	require.Len(t, response.Candidates, 2)
+	// The provider without environments ranks first
+	assert.Equal(t, uint64(2), response.Candidates[0].Provider.Id)
+	assert.Empty(t, response.Candidates[0].Penalties)
+	assert.Contains(t, response.Candidates[0].Matched, "annotation region=eu-central-1")
+	assert.Equal(t, uint64(1), response.Candidates[1].Provider.Id)
+	assert.Equal(t, []string{"already hosts 1 environment (-2)"}, response.Candidates[1].Penalties)
+}
+
+func TestRecommendHandler_Handle_MaxEnvironmentsAndLimit(t *testing.T) {
+	handler := NewRecommendHandler(newRecommendClient(), 30*time.Second)
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "recommend-providers",
+		Arguments: map[string]interface{}{"max_environments": 0, "limit": 1},
+	})
+
+	require.NoError(t, err)
+	response := decodeRecommendation(t, result)
+	assert.Equal(t, 2, response.Eligible)
+	assert.Equal(t, 1, response.Excluded["max_environments"])
+	require.Len(t, response.Candidates, 1)
+	assert.Equal(t, uint64(2), response.Candidates[0].Provider.Id)
+}
+
+func TestRecommendHandler_Handle_InvalidArguments(t *testing.T) {
+	handler := NewRecommendHandler(&MockQueryClient{}, 30*time.Second)
+
+	for name, arguments := range map[string]map[string]interface{}{
+		"negative max environments": {"max_environments": -1},
+		"limit too large":           {"limit": 51},
+		"annotations not an object": {"annotations": "region=eu-central-1"},
+		"nested annotation value":   {"annotations": map[string]interface{}{"region": map[string]interface{}{}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+				Name:      "recommend-providers",
+				Arguments: arguments,
+			})
+			require.NoError(t, err)
+			assert.Contains(t, decodeValidation(t, result).Error, "validation failed")
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"sort"
+
+	"overlock-mcp-server/pkg/render"
+)
+
+// renderJSON renders a response as indented JSON (see package render for how
+// chain protobuf messages are encoded) and adds the metadata fields (such as
+// the network it came from) as top-level keys, so they are visible to the
+// agent alongside the data.
+func renderJSON(response interface{}, meta map[string]interface{}) (string, error) {
+	return renderAs(response, meta, render.FormatJSON)
+}
+
+// renderAs is renderJSON with a selectable output format
+func renderAs(response interface{}, meta map[string]interface{}, format string) (string, error) {
+	tree, err := render.Value(response)
+	if err != nil {
+		return "", err
+	}
+
+	if fields, ok := tree.(render.Object); ok {
+		for _, key := range sortedKeys(meta) {
+			value, err := render.Value(meta[key])
+			if err != nil {
+				return "", err
+			}
+			fields = fields.Set(key, value)
+		}
+		tree = fields
+	}
+
+	return render.Format(tree, format)
+}
+
+// sortedKeys returns the keys of a string keyed map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sony/gobreaker"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultStaleMaxAge is how old a last-known-good response may be and still be served
+const defaultStaleMaxAge = 10 * time.Minute
+
+// lastGoodCapacity bounds the number of responses kept per network
+const lastGoodCapacity = 1000
+
+// Reasons a stale response is served instead of a fresh one
+const (
+	staleReasonBreakerOpen = "circuit breaker open"
+	staleReasonTimeout     = "node timeout"
+)
+
+// lastGoodEntry is a successful chain response and where it was served from
+type lastGoodEntry struct {
+	result   interface{}
+	info     queryInfo
+	storedAt time.Time
+}
+
+// lastGoodCache keeps the last successful response per request key so that it can be
+// served, flagged as stale, while the node cannot be reached
+type lastGoodCache struct {
+	mu      sync.Mutex
+	maxAge  time.Duration // 0 disables serving stale responses
+	entries map[string]lastGoodEntry
+}
+
+// newLastGoodCache creates a cache serving responses up to maxAge old
+func newLastGoodCache(maxAge time.Duration) *lastGoodCache {
+	return &lastGoodCache{
+		maxAge:  maxAge,
+		entries: make(map[string]lastGoodEntry),
+	}
+}
+
+// store records a successful response, evicting the oldest entry when the cache is full
+func (c *lastGoodCache) store(key string, result interface{}, info queryInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.maxAge <= 0 {
+		return
+	}
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= lastGoodCapacity {
+		var oldestKey string
+		var oldest time.Time
+		for k, entry := range c.entries {
+			if oldestKey == "" || entry.storedAt.Before(oldest) {
+				oldestKey, oldest = k, entry.storedAt
+			}
+		}
+		delete(c.entries, oldestKey)
+	}
+	c.entries[key] = lastGoodEntry{result: result, info: info, storedAt: time.Now()}
+}
+
+// load returns the response stored for key when it is not older than the maximum staleness
+func (c *lastGoodCache) load(key string) (lastGoodEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || c.maxAge <= 0 || time.Since(entry.storedAt) > c.maxAge {
+		return lastGoodEntry{}, false
+	}
+	return entry, true
+}
+
+// setMaxAge changes the maximum staleness; 0 disables serving stale responses
+func (c *lastGoodCache) setMaxAge(maxAge time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxAge = maxAge
+	if maxAge <= 0 {
+		c.entries = make(map[string]lastGoodEntry)
+	}
+}
+
+// SetStaleMaxAge sets how old a last-known-good response may be when it is served because
+// the circuit breaker is open or the node timed out; 0 disables serving stale responses
+func (b *chainBackend) SetStaleMaxAge(maxAge time.Duration) {
+	b.lastGood.setMaxAge(maxAge)
+	for _, target := range b.networks {
+		target.lastGood.setMaxAge(maxAge)
+	}
+}
+
+// executeCached runs query like execute and remembers the response under key. When the
+// circuit breaker is open or the node times out, the last successful response for key
+// is returned instead, with its queryInfo flagged as stale.
+func (t *chainTarget) executeCached(ctx context.Context, key string, height int64, query chainQuery) (interface{}, queryInfo, error) {
+	result, info, err := t.execute(ctx, height, query)
+	if t.lastGood == nil {
+		return result, info, err
+	}
+	if err == nil {
+		t.lastGood.store(key, result, info)
+		return result, info, nil
+	}
+
+	reason := staleReason(err)
+	if reason == "" {
+		return result, info, err
+	}
+	entry, ok := t.lastGood.load(key)
+	if !ok {
+		return result, info, err
+	}
+
+	log.Warn().
+		Err(err).
+		Str("network", t.network).
+		Str("reason", reason).
+		Dur("age", time.Since(entry.storedAt)).
+		Msg("Serving last-known-good response")
+	info = entry.info
+	info.staleReason = reason
+	info.cachedAt = entry.storedAt
+	return entry.result, info, nil
+}
+
+// staleReason reports why a failed call may be answered from the last-known-good cache,
+// or returns "" when it may not
+func staleReason(err error) string {
+	switch {
+	case errors.Is(err, gobreaker.ErrOpenState), errors.Is(err, gobreaker.ErrTooManyRequests):
+		return staleReasonBreakerOpen
+	case errors.Is(err, context.DeadlineExceeded), status.Code(err) == codes.DeadlineExceeded:
+		return staleReasonTimeout
+	}
+	return ""
+}
+
+// cacheKey identifies a request in the last-known-good cache
+func cacheKey(method string, height int64, req fmt.Stringer) string {
+	return fmt.Sprintf("%s@%d:%s", method, height, req.String())
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/sony/gobreaker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// openBreaker returns a circuit breaker that is already open
+func openBreaker(t *testing.T) *gobreaker.CircuitBreaker {
+	breaker := gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		Name:    "test-open-breaker",
+		Timeout: time.Hour,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return true
+		},
+	})
+	_, _ = breaker.Execute(func() (interface{}, error) { return nil, errors.New("boom") })
+	require.Equal(t, gobreaker.StateOpen, breaker.State())
+	return breaker
+}
+
+func showProviderParams(id int) *mcp.CallToolParams {
+	return &mcp.CallToolParams{
+		Name:      "show-provider",
+		Arguments: map[string]interface{}{"id": id},
+	}
+}
+
+func TestProvidersHandler_HandleShow_StaleWhenBreakerOpen(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewProvidersHandler(mockClient, 30*time.Second)
+
+	mockClient.On("ShowProvider", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryShowProviderResponse{
+		Provider: &overlockv1beta1.Provider{Id: 7, Ip: "10.0.0.1"},
+	}, nil).Once()
+
+	result, err := handler.HandleShow(context.Background(), &mcp.ServerSession{}, showProviderParams(7))
+	require.NoError(t, err)
+	assert.NotContains(t, result.Meta, "stale")
+
+	handler.circuitBreaker = openBreaker(t)
+
+	result, err = handler.HandleShow(context.Background(), &mcp.ServerSession{}, showProviderParams(7))
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, true, result.Meta["stale"])
+	assert.Equal(t, staleReasonBreakerOpen, result.Meta["stale_reason"])
+	assert.Contains(t, result.Meta, "cached_at")
+	assert.Contains(t, result.Meta, "staleness_seconds")
+
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	var response struct {
+		overlockv1beta1.QueryShowProviderResponse
+		Stale bool `json:"stale"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	assert.True(t, response.Stale)
+	assert.Equal(t, "10.0.0.1", response.Provider.Ip)
+
+	// Nothing was cached for another provider
+	result, err = handler.HandleShow(context.Background(), &mcp.ServerSession{}, showProviderParams(8))
+	require.NoError(t, err)
+	textContent, ok = result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "circuit breaker protection active")
+	mockClient.AssertExpectations(t)
+}
+
+func TestProvidersHandler_HandleList_StaleOnTimeout(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewProvidersHandler(mockClient, 30*time.Second)
+
+	mockClient.On("ListProvider", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryListProviderResponse{
+		Providers: []overlockv1beta1.Provider{{Id: 1}, {Id: 2}},
+	}, nil).Once()
+	mockClient.On("ListProvider", mock.Anything, mock.Anything).Return(
+		(*overlockv1beta1.QueryListProviderResponse)(nil), status.Error(codes.DeadlineExceeded, "context deadline exceeded")).Once()
+
+	params := &mcp.CallToolParams{Name: "get-providers", Arguments: map[string]interface{}{"limit": 2}}
+
+	_, err := handler.HandleList(context.Background(), &mcp.ServerSession{}, params)
+	require.NoError(t, err)
+
+	result, err := handler.HandleList(context.Background(), &mcp.ServerSession{}, params)
+	require.NoError(t, err)
+	assert.Equal(t, true, result.Meta["stale"])
+	assert.Equal(t, staleReasonTimeout, result.Meta["stale_reason"])
+
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	var response overlockv1beta1.QueryListProviderResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	assert.Len(t, response.Providers, 2)
+	mockClient.AssertExpectations(t)
+}
+
+func TestEnvironmentHandler_Handle_NoStaleForOtherErrors(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewEnvironmentHandler(mockClient, 30*time.Second)
+
+	mockClient.On("ShowEnvironment", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryShowEnvironmentResponse{
+		Environment: &overlockv1beta1.Environment{Id: 1001},
+	}, nil).Once()
+	mockClient.On("ShowEnvironment", mock.Anything, mock.Anything).Return(
+		(*overlockv1beta1.QueryShowEnvironmentResponse)(nil), status.Error(codes.Unavailable, "connection refused")).Once()
+
+	params := &mcp.CallToolParams{Name: "show-environment", Arguments: map[string]interface{}{"id": 1001}}
+
+	_, err := handler.Handle(context.Background(), &mcp.ServerSession{}, params)
+	require.NoError(t, err)
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, params)
+	require.NoError(t, err)
+	assert.NotContains(t, result.Meta, "stale")
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "Unable to connect to blockchain service")
+}
+
+func TestProvidersHandler_SetStaleMaxAge(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewProvidersHandler(mockClient, 30*time.Second)
+	handler.SetStaleMaxAge(0)
+
+	mockClient.On("ShowProvider", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryShowProviderResponse{
+		Provider: &overlockv1beta1.Provider{Id: 7},
+	}, nil).Once()
+
+	_, err := handler.HandleShow(context.Background(), &mcp.ServerSession{}, showProviderParams(7))
+	require.NoError(t, err)
+
+	handler.circuitBreaker = openBreaker(t)
+
+	result, err := handler.HandleShow(context.Background(), &mcp.ServerSession{}, showProviderParams(7))
+	require.NoError(t, err)
+	assert.NotContains(t, result.Meta, "stale")
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "circuit breaker protection active")
+}
+
+func TestLastGoodCache_MaxAge(t *testing.T) {
+	cache := newLastGoodCache(time.Minute)
+	cache.store("key", "value", queryInfo{network: "default", blockHeight: 42})
+
+	entry, ok := cache.load("key")
+	require.True(t, ok)
+	assert.Equal(t, "value", entry.result)
+	assert.Equal(t, int64(42), entry.info.blockHeight)
+
+	cache.entries["key"] = lastGoodEntry{result: "value", storedAt: time.Now().Add(-2 * time.Minute)}
+	_, ok = cache.load("key")
+	assert.False(t, ok, "entries older than the maximum staleness are not served")
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"overlock-mcp-server/pkg/network"
+	"overlock-mcp-server/pkg/stats"
+
+	"github.com/Oudwins/zog"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/rs/zerolog/log"
+)
+
+// NetworkStatsInput represents the input parameters for the network-stats tool
+type NetworkStatsInput struct {
+	Network            string   `json:"network,omitempty"`
+	Height             int64    `json:"height,omitempty"`
+	Source             string   `json:"source,omitempty"`
+	ProviderGroupBy    []string `json:"provider_group_by,omitempty" zog:"provider_group_by"`
+	EnvironmentGroupBy []string `json:"environment_group_by,omitempty" zog:"environment_group_by"`
+	Bucket             string   `json:"bucket,omitempty"`
+	Top                int      `json:"top,omitempty"`
+}
+
+// NetworkStatsResponse is the response of the network-stats tool
+type NetworkStatsResponse struct {
+	stats.Stats
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// StatsHandler handles the network-stats tool requests
+type StatsHandler struct {
+	chainBackend
+}
+
+// NewStatsHandler creates a new network statistics handler
+func NewStatsHandler(chainClient overlockv1beta1.QueryClient, timeout time.Duration) *StatsHandler {
+	return &StatsHandler{
+		chainBackend: newChainBackend("blockchain-client-stats", chainClient, timeout),
+	}
+}
+
+// NewStatsHandlerForNetworks creates a network statistics handler serving every configured network
+func NewStatsHandlerForNetworks(networks *network.Registry) *StatsHandler {
+	return &StatsHandler{
+		chainBackend: newChainBackendForNetworks("blockchain-client-stats", networks),
+	}
+}
+
+// Handle processes the network-stats tool call
+func (h *StatsHandler) Handle(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	// Create a logger with request context
+	logger := log.With().
+		Str("tool", "network-stats").
+		Str("request_id", fmt.Sprintf("%p", params)).
+		Logger()
+
+	start := time.Now()
+	logger.Info().Msg("Processing network-stats request")
+
+	// Define validation schema using Zog
+	schema := zog.Struct(zog.Shape{
+		"network":            zog.String().Default(""),
+		"height":             zog.Int64().GTE(0).Default(0),
+		"source":             zog.String().OneOf([]string{sourceChain, sourceIndex}).Default(sourceChain),
+		"providerGroupBy":    zog.Slice(zog.String().OneOf(stats.ProviderGroupings)),
+		"environmentGroupBy": zog.Slice(zog.String().OneOf(stats.EnvironmentGroupings)),
+		"bucket":             zog.String().OneOf(stats.Buckets).Default(stats.BucketMonth),
+		"top":                zog.Int().GTE(0).LTE(1000).Default(25),
+	})
+
+	// Validate input parameters
+	var input NetworkStatsInput
+	arguments := params.Arguments
+	if arguments == nil {
+		arguments = make(map[string]interface{})
+	}
+
+	logger.Debug().Interface("arguments", arguments).Msg("Validating input arguments")
+	// Parse and validate the arguments
+	errs := schema.Parse(arguments, &input)
+	if errs != nil {
+		logger.Error().Interface("errors", errs).Msg("Input validation failed")
+		return invalidInput(errs), nil
+	}
+
+	// Group by every field unless a selection was given
+	if _, ok := argument(arguments, "provider_group_by"); !ok {
+		input.ProviderGroupBy = stats.ProviderGroupings
+	}
+	if _, ok := argument(arguments, "environment_group_by"); !ok {
+		input.EnvironmentGroupBy = stats.EnvironmentGroupings
+	}
+
+	target, err := h.resolve(input.Network)
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("network", "known network", input.Network, err.Error()), nil
+	}
+
+	logger.Info().
+		Str("network", target.network).
+		Int64("height", input.Height).
+		Strs("provider_group_by", input.ProviderGroupBy).
+		Strs("environment_group_by", input.EnvironmentGroupBy).
+		Str("bucket", input.Bucket).
+		Msg("Computing network statistics")
+
+	var records listing
+	var info queryInfo
+	if input.Source == sourceIndex {
+		snapshot, indexInfo, unavailable := target.snapshot(logger)
+		if unavailable != nil {
+			return unavailable, nil
+		}
+		info = indexInfo
+		records = listing{providers: snapshot.Providers, environments: snapshot.Environments}
+	} else {
+		// Check if chain client is available
+		if target.chainClient == nil {
+			logger.Error().Msg("gRPC client is not available")
+			return target.unavailableResult(), nil
+		}
+		records, info, err = target.listAll(ctx, input.Height, "", fullListMaxPages)
+		if err != nil {
+			return target.failureResult(logger, err), nil
+		}
+	}
+
+	computed, err := stats.Compute(records.providers, records.environments, stats.Options{
+		ProviderGroupBy:    input.ProviderGroupBy,
+		EnvironmentGroupBy: input.EnvironmentGroupBy,
+		Bucket:             input.Bucket,
+		Top:                input.Top,
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return validationResult(Problem{Field: "arguments", Constraint: "supported grouping", Hint: err.Error()}), nil
+	}
+
+	logger.Info().
+		Int("provider_count", computed.Providers.Total).
+		Int("environment_count", computed.Environments.Total).
+		Bool("truncated", records.truncated).
+		Int64("block_height", info.blockHeight).
+		Dur("duration", time.Since(start)).
+		Msg("Successfully computed network statistics")
+
+	toolResult, err := info.jsonResult(NetworkStatsResponse{Stats: computed, Truncated: records.truncated})
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to marshal response")
+		return nil, fmt.Errorf("failed to marshal network stats response: %w", err)
+	}
+	return toolResult, nil
+}
+
+// argument returns a tool argument and whether it was given
+func argument(arguments any, name string) (any, bool) {
+	m, ok := arguments.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	value, ok := m[name]
+	return value, ok && value != nil
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"overlock-mcp-server/pkg/indexer"
+	"overlock-mcp-server/pkg/stats"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeStats(t *testing.T, result *mcp.CallToolResult) NetworkStatsResponse {
+	require.NotNil(t, result)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	var response NetworkStatsResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	return response
+}
+
+func TestStatsHandler_Handle(t *testing.T) {
+	registeredAt := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	mockClient := &MockQueryClient{}
+	handler := NewStatsHandler(mockClient, 30*time.Second)
+
+	mockClient.On("ListProvider", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryListProviderResponse{
+		Providers: []overlockv1beta1.Provider{
+			{Id: 1, CountryCode: "DE", RegisterTime: &registeredAt},
+			{Id: 2, CountryCode: "DE", RegisterTime: &registeredAt},
+			{Id: 3, CountryCode: "FR"},
+		},
+		Pagination: &query.PageResponse{},
+	}, nil)
+	mockClient.On("ListEnvironment", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryListEnvironmentResponse{
+		Environments: []overlockv1beta1.Environment{{Id: 1001, Provider: 1}},
+		Pagination:   &query.PageResponse{},
+	}, nil)
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name: "network-stats",
+		Arguments: map[string]interface{}{
+			"provider_group_by":    []interface{}{"country_code"},
+			"environment_group_by": []interface{}{},
+			"bucket":               "year",
+		},
+	})
+
+	require.NoError(t, err)
+	response := decodeStats(t, result)
+	assert.Equal(t, 3, response.Providers.Total)
+	assert.Equal(t, 1, response.Environments.Total)
+	require.Len(t, response.Providers.Groups, 1)
+	assert.Equal(t, []stats.Count{{Value: "DE", Count: 2}, {Value: "FR", Count: 1}}, response.Providers.Groups["country_code"].Values)
+	assert.Empty(t, response.Environments.Groups)
+	assert.Equal(t, "year", response.Registrations.Bucket)
+	assert.Equal(t, []stats.Bucket{{Start: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Count: 2}}, response.Registrations.Buckets)
+	assert.Equal(t, 1, response.Registrations.Unknown)
+}
+
+func TestStatsHandler_Handle_DefaultGroupings(t *testing.T) {
+	handler := NewStatsHandler(&MockQueryClient{}, 30*time.Second)
+	handler.AttachIndexers(map[string]*indexer.Indexer{"default": newSyncedIndexer(t)})
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "network-stats",
+		Arguments: map[string]interface{}{"source": "index"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "index", result.Meta["source"])
+	response := decodeStats(t, result)
+	assert.Len(t, response.Providers.Groups, len(stats.ProviderGroupings))
+	assert.Len(t, response.Environments.Groups, len(stats.EnvironmentGroupings))
+	assert.Equal(t, "month", response.Registrations.Bucket)
+	assert.Equal(t, []stats.Count{{Value: "overlock1alice", Count: 2}, {Value: "overlock1bob", Count: 1}}, response.Providers.Groups["creator"].Values)
+}
+
+func TestStatsHandler_Handle_InvalidArguments(t *testing.T) {
+	handler := NewStatsHandler(&MockQueryClient{}, 30*time.Second)
+
+	for name, arguments := range map[string]map[string]interface{}{
+		"unknown provider field":    {"provider_group_by": []interface{}{"ip"}},
+		"unknown environment field": {"environment_group_by": []interface{}{"country_code"}},
+		"unknown bucket":            {"bucket": "decade"},
+		"negative top":              {"top": -1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+				Name:      "network-stats",
+				Arguments: arguments,
+			})
+			require.NoError(t, err)
+			assert.Contains(t, decodeValidation(t, result).Error, "validation failed")
+		})
+	}
+}
@@ -0,0 +1,479 @@
+package handler
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"overlock-mcp-server/pkg/network"
+	"overlock-mcp-server/pkg/render"
+
+	"github.com/Oudwins/zog"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	gogoproto "github.com/cosmos/gogoproto/proto"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// crossplaneMsgPrefix is the type URL prefix of the Overlock crossplane messages
+const crossplaneMsgPrefix = "/overlock.crossplane.v1beta1."
+
+// Paging of the list-transactions tool
+const (
+	defaultTxLimit = 20
+	maxTxLimit     = 100
+)
+
+// Sort orders of the list-transactions tool
+const (
+	txOrderAsc  = "asc"
+	txOrderDesc = "desc"
+)
+
+var (
+	// txActionPattern accepts message type names, so they can be quoted in an event query safely
+	txActionPattern = regexp.MustCompile(`^/?[A-Za-z_][A-Za-z0-9_.]*$`)
+	// txHashPattern accepts a hex encoded SHA-256 transaction hash
+	txHashPattern = regexp.MustCompile(`^[0-9A-Fa-f]{64}$`)
+)
+
+// TransactionsListInput represents the input parameters for the list-transactions tool
+type TransactionsListInput struct {
+	Action  string `json:"action,omitempty"`
+	Sender  string `json:"sender,omitempty"`
+	Query   string `json:"query,omitempty"`
+	Page    int    `json:"page,omitempty"`
+	Limit   int    `json:"limit,omitempty"`
+	Order   string `json:"order,omitempty"`
+	Network string `json:"network,omitempty"`
+	Format  string `json:"format,omitempty"`
+}
+
+// TransactionShowInput represents the input parameters for the show-transaction tool
+type TransactionShowInput struct {
+	Hash    string `json:"hash,omitempty"`
+	Network string `json:"network,omitempty"`
+	Format  string `json:"format,omitempty"`
+}
+
+// TxMessage is one message of a transaction. Value and response are omitted for
+// message types the server cannot decode.
+type TxMessage struct {
+	Type     string      `json:"type"`
+	Value    interface{} `json:"value,omitempty"`
+	Response interface{} `json:"response,omitempty"`
+}
+
+// Transaction is a transaction included in a block, with its messages decoded
+type Transaction struct {
+	Hash      string      `json:"hash"`
+	Height    int64       `json:"height"`
+	Time      string      `json:"time,omitempty"`
+	Code      uint32      `json:"code"`
+	Codespace string      `json:"codespace,omitempty"`
+	Success   bool        `json:"success"`
+	Log       string      `json:"log,omitempty"`
+	GasWanted int64       `json:"gas_wanted"`
+	GasUsed   int64       `json:"gas_used"`
+	Fee       string      `json:"fee,omitempty"`
+	Memo      string      `json:"memo,omitempty"`
+	Messages  []TxMessage `json:"messages"`
+}
+
+// TransactionsListResponse is the response of the list-transactions tool
+type TransactionsListResponse struct {
+	Query        string        `json:"query"`
+	Page         int           `json:"page"`
+	Limit        int           `json:"limit"`
+	Total        uint64        `json:"total"`
+	Transactions []Transaction `json:"transactions"`
+}
+
+// TransactionsHandler handles the transaction lookup tools
+type TransactionsHandler struct {
+	chainBackend
+	interfaces codectypes.InterfaceRegistry
+}
+
+// NewTransactionsHandler creates a transactions handler querying the tx service over conn
+func NewTransactionsHandler(conn grpc.ClientConnInterface, timeout time.Duration) *TransactionsHandler {
+	backend := newChainBackend("blockchain-client-transactions", nil, timeout)
+	backend.conn = conn
+	return &TransactionsHandler{
+		chainBackend: backend,
+		interfaces:   newTxInterfaceRegistry(),
+	}
+}
+
+// NewTransactionsHandlerForNetworks creates a transactions handler serving every configured network
+func NewTransactionsHandlerForNetworks(networks *network.Registry) *TransactionsHandler {
+	return &TransactionsHandler{
+		chainBackend: newChainBackendForNetworks("blockchain-client-transactions", networks),
+		interfaces:   newTxInterfaceRegistry(),
+	}
+}
+
+// newTxInterfaceRegistry registers the messages, and their responses, that transactions are decoded with
+func newTxInterfaceRegistry() codectypes.InterfaceRegistry {
+	registry := codectypes.NewInterfaceRegistry()
+	overlockv1beta1.RegisterInterfaces(registry)
+	banktypes.RegisterInterfaces(registry)
+	authtypes.RegisterInterfaces(registry)
+	return registry
+}
+
+// HandleList processes the list-transactions tool call
+func (h *TransactionsHandler) HandleList(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	// Create a logger with request context
+	logger := log.With().
+		Str("tool", "list-transactions").
+		Str("request_id", fmt.Sprintf("%p", params)).
+		Logger()
+
+	start := time.Now()
+	logger.Info().Msg("Processing list-transactions request")
+
+	// Define validation schema using Zog
+	schema := zog.Struct(zog.Shape{
+		"action":  zog.String().Trim().Default(""),
+		"sender":  zog.String().Trim().Default(""),
+		"query":   zog.String().Trim().Default(""),
+		"page":    zog.Int().GTE(1).Default(1),
+		"limit":   zog.Int().GTE(1).LTE(maxTxLimit).Default(defaultTxLimit),
+		"order":   zog.String().OneOf([]string{txOrderAsc, txOrderDesc}).Default(txOrderDesc),
+		"network": zog.String().Default(""),
+		"format":  zog.String().OneOf(render.RecordFormats).Default(render.FormatJSON),
+	})
+
+	// Validate input parameters
+	var input TransactionsListInput
+	arguments := params.Arguments
+	if arguments == nil {
+		arguments = make(map[string]interface{})
+	}
+
+	logger.Debug().Interface("arguments", arguments).Msg("Validating input arguments")
+	// Parse and validate the arguments
+	errs := schema.Parse(arguments, &input)
+	if errs != nil {
+		logger.Error().Interface("errors", errs).Msg("Input validation failed")
+		return invalidInput(errs), nil
+	}
+
+	query, invalid := txEventQuery(input)
+	if invalid != nil {
+		logger.Error().Msg("Input validation failed")
+		return invalid, nil
+	}
+
+	target, err := h.resolve(input.Network)
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("network", "known network", input.Network, err.Error()), nil
+	}
+
+	logger.Debug().Interface("parsed_input", input).Msg("Input validation successful")
+
+	// Check if the gRPC connection is available
+	if target.conn == nil {
+		logger.Error().Msg("gRPC connection is not available")
+		return target.unavailableResult(), nil
+	}
+
+	req := &txtypes.GetTxsEventRequest{
+		Query:   query,
+		Page:    uint64(input.Page),
+		Limit:   uint64(input.Limit),
+		OrderBy: txtypes.OrderBy_ORDER_BY_DESC,
+	}
+	if input.Order == txOrderAsc {
+		req.OrderBy = txtypes.OrderBy_ORDER_BY_ASC
+	}
+
+	logger.Info().
+		Str("network", target.network).
+		Str("query", query).
+		Int("page", input.Page).
+		Int("limit", input.Limit).
+		Msg("Searching transactions")
+
+	// Search transactions using circuit breaker protection
+	result, info, err := target.invoke(ctx, 0, func(ctx context.Context, conn grpc.ClientConnInterface, opts ...grpc.CallOption) (interface{}, error) {
+		return txtypes.NewServiceClient(conn).GetTxsEvent(ctx, req, opts...)
+	})
+	if rejected := searchRejection(info, query, input.Page, err); rejected != nil {
+		logger.Error().Err(err).Msg("Node rejected the transaction search")
+		return rejected, nil
+	}
+	if err != nil {
+		return target.failureResult(logger, err), nil
+	}
+
+	chainResponse, ok := result.(*txtypes.GetTxsEventResponse)
+	if !ok || chainResponse == nil {
+		return target.invalidResponseResult(logger), nil
+	}
+
+	response := TransactionsListResponse{
+		Query:        query,
+		Page:         input.Page,
+		Limit:        input.Limit,
+		Total:        chainResponse.Total,
+		Transactions: make([]Transaction, 0, len(chainResponse.TxResponses)),
+	}
+	for i, txResponse := range chainResponse.TxResponses {
+		var tx *txtypes.Tx
+		if i < len(chainResponse.Txs) {
+			tx = chainResponse.Txs[i]
+		}
+		response.Transactions = append(response.Transactions, h.transaction(tx, txResponse))
+	}
+
+	logger.Info().
+		Int("transaction_count", len(response.Transactions)).
+		Uint64("total", response.Total).
+		Int64("block_height", info.blockHeight).
+		Dur("duration", time.Since(start)).
+		Msg("Successfully searched transactions")
+
+	toolResult, err := info.formattedResult(response, input.Format)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to marshal response")
+		return nil, fmt.Errorf("failed to marshal transactions response: %w", err)
+	}
+	return toolResult, nil
+}
+
+// searchRejection explains why the node rejected a transaction search. A page past the last
+// one is reported against page, an unparsable query against query, and any other rejection
+// with the node's message. It returns nil for errors that are not a rejection of the search.
+func searchRejection(info queryInfo, query string, page int, err error) *mcp.CallToolResult {
+	if err == nil {
+		return nil
+	}
+	message := status.Convert(err).Message()
+	switch {
+	case strings.Contains(message, "page should be within") || strings.Contains(message, "page must"):
+		// Reported by CometBFT, which the SDK relays as an internal error
+		return invalidArgument("page", "page within the results", page, message)
+	case status.Code(err) != codes.InvalidArgument:
+		return nil
+	case strings.Contains(message, "query"):
+		return invalidArgument("query", "event query", query, message)
+	default:
+		return info.textResult(fmt.Sprintf("Error: the node rejected the transaction search: %s", message))
+	}
+}
+
+// HandleShow processes the show-transaction tool call
+func (h *TransactionsHandler) HandleShow(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	// Create a logger with request context
+	logger := log.With().
+		Str("tool", "show-transaction").
+		Str("request_id", fmt.Sprintf("%p", params)).
+		Logger()
+
+	start := time.Now()
+	logger.Info().Msg("Processing show-transaction request")
+
+	// Define validation schema using Zog
+	schema := zog.Struct(zog.Shape{
+		"hash":    zog.String().Trim().Required(),
+		"network": zog.String().Default(""),
+		"format":  zog.String().OneOf(render.RecordFormats).Default(render.FormatJSON),
+	})
+
+	// Validate input parameters
+	var input TransactionShowInput
+	arguments := params.Arguments
+	if arguments == nil {
+		arguments = make(map[string]interface{})
+	}
+
+	logger.Debug().Interface("arguments", arguments).Msg("Validating input arguments")
+	// Parse and validate the arguments
+	errs := schema.Parse(arguments, &input)
+	if errs != nil {
+		logger.Error().Interface("errors", errs).Msg("Input validation failed")
+		return invalidInput(errs), nil
+	}
+
+	hash := strings.ToUpper(strings.TrimPrefix(strings.TrimPrefix(input.Hash, "0x"), "0X"))
+	if !txHashPattern.MatchString(hash) {
+		logger.Error().Str("hash", input.Hash).Msg("Input validation failed")
+		return invalidArgument("hash", "64 hex characters", input.Hash, "hash must be the 64 character hex encoded transaction hash"), nil
+	}
+
+	target, err := h.resolve(input.Network)
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("network", "known network", input.Network, err.Error()), nil
+	}
+
+	logger.Debug().Interface("parsed_input", input).Msg("Input validation successful")
+
+	// Check if the gRPC connection is available
+	if target.conn == nil {
+		logger.Error().Msg("gRPC connection is not available")
+		return target.unavailableResult(), nil
+	}
+
+	logger.Info().
+		Str("network", target.network).
+		Str("hash", hash).
+		Msg("Fetching transaction from blockchain")
+
+	// Fetch the transaction using circuit breaker protection
+	result, info, err := target.invoke(ctx, 0, func(ctx context.Context, conn grpc.ClientConnInterface, opts ...grpc.CallOption) (interface{}, error) {
+		return txtypes.NewServiceClient(conn).GetTx(ctx, &txtypes.GetTxRequest{Hash: hash}, opts...)
+	})
+	if status.Code(err) == codes.NotFound {
+		logger.Info().Str("hash", hash).Msg("Transaction not found")
+		return info.textResult(fmt.Sprintf("Transaction with hash '%s' not found.", hash)), nil
+	}
+	if err != nil {
+		return target.failureResult(logger, err), nil
+	}
+
+	chainResponse, ok := result.(*txtypes.GetTxResponse)
+	if !ok || chainResponse == nil || chainResponse.TxResponse == nil {
+		return target.invalidResponseResult(logger), nil
+	}
+
+	response := h.transaction(chainResponse.Tx, chainResponse.TxResponse)
+
+	logger.Info().
+		Str("hash", hash).
+		Int64("height", response.Height).
+		Int("message_count", len(response.Messages)).
+		Dur("duration", time.Since(start)).
+		Msg("Successfully fetched transaction")
+
+	toolResult, err := info.formattedResult(response, input.Format)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to marshal response")
+		return nil, fmt.Errorf("failed to marshal transaction response: %w", err)
+	}
+	return toolResult, nil
+}
+
+// txEventQuery builds the event query of a list-transactions call from its action,
+// sender and raw query arguments, of which at least one is required
+func txEventQuery(input TransactionsListInput) (string, *mcp.CallToolResult) {
+	var conditions []string
+	if input.Action != "" {
+		if !txActionPattern.MatchString(input.Action) {
+			return "", invalidArgument("action", "message type", input.Action,
+				"action must be a message type such as MsgCreateProvider or /cosmos.bank.v1beta1.MsgSend")
+		}
+		conditions = append(conditions, fmt.Sprintf("message.action='%s'", txActionType(input.Action)))
+	}
+	if input.Sender != "" {
+		if err := ValidateAddress(input.Sender); err != nil {
+			return "", invalidArgument("sender", "bech32_address", input.Sender, err.Error())
+		}
+		conditions = append(conditions, fmt.Sprintf("message.sender='%s'", input.Sender))
+	}
+	if input.Query != "" {
+		conditions = append(conditions, input.Query)
+	}
+	if len(conditions) == 0 {
+		return "", requiredArgument("action", "give at least one of action, sender or query, e.g. action 'MsgCreateProvider'")
+	}
+	return strings.Join(conditions, " AND "), nil
+}
+
+// txActionType expands an action into a message type URL. Bare names such as
+// MsgCreateProvider refer to the Overlock crossplane messages.
+func txActionType(action string) string {
+	action = strings.TrimPrefix(action, "/")
+	if !strings.Contains(action, ".") {
+		return crossplaneMsgPrefix + action
+	}
+	return "/" + action
+}
+
+// transaction converts a transaction and its execution result into the response form.
+// tx may be nil, in which case the transaction is decoded from the result.
+func (h *TransactionsHandler) transaction(tx *txtypes.Tx, result *sdk.TxResponse) Transaction {
+	transaction := Transaction{
+		Hash:      result.TxHash,
+		Height:    result.Height,
+		Time:      result.Timestamp,
+		Code:      result.Code,
+		Codespace: result.Codespace,
+		Success:   result.Code == 0,
+		GasWanted: result.GasWanted,
+		GasUsed:   result.GasUsed,
+		Messages:  []TxMessage{},
+	}
+	if result.Code != 0 {
+		transaction.Log = result.RawLog
+	}
+
+	if tx == nil && result.Tx != nil {
+		decoded := &txtypes.Tx{}
+		if err := decoded.Unmarshal(result.Tx.Value); err == nil {
+			tx = decoded
+		}
+	}
+	if tx == nil {
+		return transaction
+	}
+	transaction.Memo = tx.GetBody().GetMemo()
+	if fee := tx.GetAuthInfo().GetFee(); fee != nil {
+		transaction.Fee = fee.Amount.String()
+	}
+
+	responses := h.msgResponses(result.Data)
+	for i, packed := range tx.GetBody().GetMessages() {
+		if packed == nil {
+			continue
+		}
+		message := TxMessage{Type: packed.TypeUrl, Value: h.decode(packed)}
+		if i < len(responses) {
+			message.Response = h.decode(responses[i])
+		}
+		transaction.Messages = append(transaction.Messages, message)
+	}
+	return transaction
+}
+
+// msgResponses reads the packed message responses from the hex encoded result data
+func (h *TransactionsHandler) msgResponses(data string) []*codectypes.Any {
+	raw, err := hex.DecodeString(data)
+	if err != nil || len(raw) == 0 {
+		return nil
+	}
+	var msgData sdk.TxMsgData
+	if err := msgData.Unmarshal(raw); err != nil {
+		return nil
+	}
+	return msgData.MsgResponses
+}
+
+// decode unpacks a message of a registered type, returning nil for unknown types
+func (h *TransactionsHandler) decode(packed *codectypes.Any) interface{} {
+	if packed == nil {
+		return nil
+	}
+	message, err := h.interfaces.Resolve(packed.TypeUrl)
+	if err != nil {
+		return nil
+	}
+	if err := gogoproto.Unmarshal(packed.Value, message); err != nil {
+		return nil
+	}
+	return message
+}
@@ -0,0 +1,385 @@
+package handler
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"cosmossdk.io/math"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	gogoproto "github.com/cosmos/gogoproto/proto"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testTxHash is the hash of the transaction served by fakeTxService
+var testTxHash = strings.Repeat("AB", 32)
+
+// fakeTxService serves a single transaction and records the last event query
+type fakeTxService struct {
+	txtypes.UnimplementedServiceServer
+	tx       *txtypes.Tx
+	response *sdk.TxResponse
+	query    string
+	orderBy  txtypes.OrderBy
+}
+
+func (s *fakeTxService) GetTxsEvent(_ context.Context, req *txtypes.GetTxsEventRequest) (*txtypes.GetTxsEventResponse, error) {
+	s.query = req.Query
+	s.orderBy = req.OrderBy
+	if strings.Contains(req.Query, "!") {
+		return nil, status.Error(codes.InvalidArgument, "failed to parse query")
+	}
+	if strings.Contains(req.Query, "#") {
+		return nil, status.Error(codes.InvalidArgument, "request rejected by the node")
+	}
+	if req.Page > 3 {
+		return nil, status.Errorf(codes.Internal, "failed to search for txs: page should be within [1, 3] range, given %d", req.Page)
+	}
+	return &txtypes.GetTxsEventResponse{Txs: []*txtypes.Tx{s.tx}, TxResponses: []*sdk.TxResponse{s.response}, Total: 1}, nil
+}
+
+func (s *fakeTxService) GetTx(_ context.Context, req *txtypes.GetTxRequest) (*txtypes.GetTxResponse, error) {
+	if req.Hash != s.response.TxHash {
+		return nil, status.Errorf(codes.NotFound, "tx not found: %s", req.Hash)
+	}
+	return &txtypes.GetTxResponse{Tx: s.tx, TxResponse: s.response}, nil
+}
+
+// packAny packs a message for a test transaction
+func packAny(t *testing.T, message gogoproto.Message) *codectypes.Any {
+	t.Helper()
+	packed, err := codectypes.NewAnyWithValue(message)
+	require.NoError(t, err)
+	return packed
+}
+
+// newCreateProviderTx builds a transaction registering provider 7, with the given result code
+func newCreateProviderTx(t *testing.T, creator string, code uint32) (*txtypes.Tx, *sdk.TxResponse) {
+	t.Helper()
+	tx := &txtypes.Tx{
+		Body: &txtypes.TxBody{
+			Messages: []*codectypes.Any{packAny(t, &overlockv1beta1.MsgCreateProvider{
+				Creator:     creator,
+				Metadata:    &overlockv1beta1.Metadata{Name: "edge-1"},
+				Ip:          "10.0.0.7",
+				Port:        8443,
+				CountryCode: "DE",
+			})},
+			Memo: "register edge-1",
+		},
+		AuthInfo: &txtypes.AuthInfo{Fee: &txtypes.Fee{Amount: sdk.NewCoins(sdk.NewCoin("uovl", math.NewInt(500))), GasLimit: 200000}},
+	}
+	msgData, err := (&sdk.TxMsgData{MsgResponses: []*codectypes.Any{packAny(t, &overlockv1beta1.MsgCreateProviderResponse{Id: 7})}}).Marshal()
+	require.NoError(t, err)
+	response := &sdk.TxResponse{
+		Height:    1234,
+		TxHash:    testTxHash,
+		Code:      code,
+		Data:      strings.ToUpper(hex.EncodeToString(msgData)),
+		GasWanted: 200000,
+		GasUsed:   81234,
+		Timestamp: "2026-10-01T12:00:00Z",
+	}
+	if code != 0 {
+		response.Codespace = "crossplane"
+		response.RawLog = "provider already exists"
+		response.Data = ""
+	}
+	return tx, response
+}
+
+// newTransactionsTestHandler serves the tx service over an in-memory listener
+func newTransactionsTestHandler(t *testing.T, service *fakeTxService) *TransactionsHandler {
+	t.Helper()
+	conn := newBufconnConn(t, func(server *grpc.Server) {
+		txtypes.RegisterServiceServer(server, service)
+	})
+	return NewTransactionsHandler(conn, 5*time.Second)
+}
+
+// decodeText unmarshals the JSON text of a successful tool result
+func decodeText(t *testing.T, result *mcp.CallToolResult, v interface{}) {
+	t.Helper()
+	require.NotNil(t, result)
+	require.False(t, result.IsError)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), v), textContent.Text)
+}
+
+// decodedTransaction mirrors Transaction with the decoded messages as JSON objects
+type decodedTransaction struct {
+	Hash      string `json:"hash"`
+	Height    int64  `json:"height"`
+	Time      string `json:"time"`
+	Code      uint32 `json:"code"`
+	Codespace string `json:"codespace"`
+	Success   bool   `json:"success"`
+	Log       string `json:"log"`
+	GasWanted int64  `json:"gas_wanted"`
+	GasUsed   int64  `json:"gas_used"`
+	Fee       string `json:"fee"`
+	Memo      string `json:"memo"`
+	Messages  []struct {
+		Type     string                 `json:"type"`
+		Value    map[string]interface{} `json:"value"`
+		Response map[string]interface{} `json:"response"`
+	} `json:"messages"`
+}
+
+func TestTransactionsHandler_HandleList(t *testing.T) {
+	creator := testAddress(t, AddressPrefix, 1)
+	tx, response := newCreateProviderTx(t, creator, 0)
+	service := &fakeTxService{tx: tx, response: response}
+	handler := newTransactionsTestHandler(t, service)
+
+	params := &mcp.CallToolParams{
+		Name:      "list-transactions",
+		Arguments: map[string]interface{}{"action": "MsgCreateProvider", "sender": creator},
+	}
+
+	result, err := handler.HandleList(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	var list struct {
+		Query        string               `json:"query"`
+		Page         int                  `json:"page"`
+		Limit        int                  `json:"limit"`
+		Total        uint64               `json:"total"`
+		Transactions []decodedTransaction `json:"transactions"`
+	}
+	decodeText(t, result, &list)
+
+	expectedQuery := "message.action='/overlock.crossplane.v1beta1.MsgCreateProvider' AND message.sender='" + creator + "'"
+	assert.Equal(t, expectedQuery, service.query)
+	assert.Equal(t, txtypes.OrderBy_ORDER_BY_DESC, service.orderBy)
+	assert.Equal(t, expectedQuery, list.Query)
+	assert.Equal(t, 1, list.Page)
+	assert.Equal(t, 20, list.Limit)
+	assert.Equal(t, uint64(1), list.Total)
+	require.Len(t, list.Transactions, 1)
+
+	transaction := list.Transactions[0]
+	assert.Equal(t, testTxHash, transaction.Hash)
+	assert.Equal(t, int64(1234), transaction.Height)
+	assert.Equal(t, "2026-10-01T12:00:00Z", transaction.Time)
+	assert.True(t, transaction.Success)
+	assert.Equal(t, int64(81234), transaction.GasUsed)
+	assert.Equal(t, "500uovl", transaction.Fee)
+	assert.Equal(t, "register edge-1", transaction.Memo)
+	require.Len(t, transaction.Messages, 1)
+	assert.Equal(t, "/overlock.crossplane.v1beta1.MsgCreateProvider", transaction.Messages[0].Type)
+	assert.Equal(t, creator, transaction.Messages[0].Value["creator"])
+	assert.Equal(t, "10.0.0.7", transaction.Messages[0].Value["ip"])
+	metadata, ok := transaction.Messages[0].Value["metadata"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "edge-1", metadata["name"])
+	assert.Equal(t, float64(7), transaction.Messages[0].Response["id"])
+}
+
+func TestTransactionsHandler_HandleList_Query(t *testing.T) {
+	tx, response := newCreateProviderTx(t, testAddress(t, AddressPrefix, 1), 0)
+	service := &fakeTxService{tx: tx, response: response}
+	handler := newTransactionsTestHandler(t, service)
+
+	params := &mcp.CallToolParams{
+		Name: "list-transactions",
+		Arguments: map[string]interface{}{
+			"action": "/cosmos.bank.v1beta1.MsgSend",
+			"query":  "tx.height>=100",
+			"order":  "asc",
+		},
+	}
+
+	result, err := handler.HandleList(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Equal(t, "message.action='/cosmos.bank.v1beta1.MsgSend' AND tx.height>=100", service.query)
+	assert.Equal(t, txtypes.OrderBy_ORDER_BY_ASC, service.orderBy)
+}
+
+func TestTransactionsHandler_HandleList_InvalidArguments(t *testing.T) {
+	tx, response := newCreateProviderTx(t, testAddress(t, AddressPrefix, 1), 0)
+	handler := newTransactionsTestHandler(t, &fakeTxService{tx: tx, response: response})
+
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		field     string
+	}{
+		{name: "no condition", arguments: map[string]interface{}{}, field: "action"},
+		{name: "action with quote", arguments: map[string]interface{}{"action": "MsgCreateProvider' OR 1=1"}, field: "action"},
+		{name: "invalid sender", arguments: map[string]interface{}{"sender": "overlock1alice"}, field: "sender"},
+		{name: "limit too large", arguments: map[string]interface{}{"action": "MsgCreateProvider", "limit": 500}, field: "limit"},
+		{name: "invalid order", arguments: map[string]interface{}{"action": "MsgCreateProvider", "order": "newest"}, field: "order"},
+		{name: "query rejected by node", arguments: map[string]interface{}{"query": "tx.height!5"}, field: "query"},
+		{name: "page past the last", arguments: map[string]interface{}{"action": "MsgCreateProvider", "page": 9}, field: "page"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := &mcp.CallToolParams{Name: "list-transactions", Arguments: tt.arguments}
+
+			result, err := handler.HandleList(context.Background(), &mcp.ServerSession{}, params)
+
+			require.NoError(t, err)
+			failure := decodeValidation(t, result)
+			require.Len(t, failure.Problems, 1)
+			assert.Equal(t, tt.field, failure.Problems[0].Field)
+		})
+	}
+}
+
+func TestTransactionsHandler_HandleList_OtherRejection(t *testing.T) {
+	tx, response := newCreateProviderTx(t, testAddress(t, AddressPrefix, 1), 0)
+	handler := newTransactionsTestHandler(t, &fakeTxService{tx: tx, response: response})
+
+	params := &mcp.CallToolParams{Name: "list-transactions", Arguments: map[string]interface{}{"query": "tx.hash#1"}}
+
+	result, err := handler.HandleList(context.Background(), &mcp.ServerSession{}, params)
+
+	// Not attributed to an argument, the node's message is passed on as is
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Equal(t, "Error: the node rejected the transaction search: request rejected by the node", textContent.Text)
+}
+
+func TestTransactionsHandler_HandleShow(t *testing.T) {
+	creator := testAddress(t, AddressPrefix, 1)
+	tx, response := newCreateProviderTx(t, creator, 0)
+	handler := newTransactionsTestHandler(t, &fakeTxService{tx: tx, response: response})
+
+	params := &mcp.CallToolParams{
+		Name:      "show-transaction",
+		Arguments: map[string]interface{}{"hash": "0x" + strings.ToLower(testTxHash)},
+	}
+
+	result, err := handler.HandleShow(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	var transaction decodedTransaction
+	decodeText(t, result, &transaction)
+	assert.Equal(t, testTxHash, transaction.Hash)
+	assert.Equal(t, int64(1234), transaction.Height)
+	assert.Equal(t, int64(200000), transaction.GasWanted)
+	assert.True(t, transaction.Success)
+	require.Len(t, transaction.Messages, 1)
+	assert.Equal(t, creator, transaction.Messages[0].Value["creator"])
+	assert.Equal(t, float64(7), transaction.Messages[0].Response["id"])
+}
+
+func TestTransactionsHandler_HandleShow_FailedTx(t *testing.T) {
+	tx, response := newCreateProviderTx(t, testAddress(t, AddressPrefix, 1), 18)
+	handler := newTransactionsTestHandler(t, &fakeTxService{tx: tx, response: response})
+
+	params := &mcp.CallToolParams{
+		Name:      "show-transaction",
+		Arguments: map[string]interface{}{"hash": testTxHash},
+	}
+
+	result, err := handler.HandleShow(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	var transaction decodedTransaction
+	decodeText(t, result, &transaction)
+	assert.False(t, transaction.Success)
+	assert.Equal(t, uint32(18), transaction.Code)
+	assert.Equal(t, "crossplane", transaction.Codespace)
+	assert.Equal(t, "provider already exists", transaction.Log)
+	require.Len(t, transaction.Messages, 1)
+	assert.Nil(t, transaction.Messages[0].Response)
+}
+
+func TestTransactionsHandler_HandleShow_UndecodedMessage(t *testing.T) {
+	tx, response := newCreateProviderTx(t, testAddress(t, AddressPrefix, 1), 0)
+	tx.Body.Messages = []*codectypes.Any{{TypeUrl: "/example.v1.MsgUnknown", Value: []byte{0x0a, 0x01, 0x61}}}
+	response.Data = ""
+	// Nodes return the transaction in the response only; decode it from there
+	response.Tx = packAny(t, tx)
+	handler := newTransactionsTestHandler(t, &fakeTxService{response: response})
+
+	params := &mcp.CallToolParams{
+		Name:      "show-transaction",
+		Arguments: map[string]interface{}{"hash": testTxHash},
+	}
+
+	result, err := handler.HandleShow(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	var transaction decodedTransaction
+	decodeText(t, result, &transaction)
+	require.Len(t, transaction.Messages, 1)
+	assert.Equal(t, "/example.v1.MsgUnknown", transaction.Messages[0].Type)
+	assert.Nil(t, transaction.Messages[0].Value)
+}
+
+func TestTransactionsHandler_HandleShow_NotFound(t *testing.T) {
+	tx, response := newCreateProviderTx(t, testAddress(t, AddressPrefix, 1), 0)
+	handler := newTransactionsTestHandler(t, &fakeTxService{tx: tx, response: response})
+
+	missing := strings.Repeat("CD", 32)
+	params := &mcp.CallToolParams{
+		Name:      "show-transaction",
+		Arguments: map[string]interface{}{"hash": missing},
+	}
+
+	result, err := handler.HandleShow(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Equal(t, "Transaction with hash '"+missing+"' not found.", textContent.Text)
+}
+
+func TestTransactionsHandler_HandleShow_InvalidHash(t *testing.T) {
+	handler := NewTransactionsHandler(nil, 5*time.Second)
+
+	for _, hash := range []string{"", "ABCD", strings.Repeat("ZZ", 32)} {
+		params := &mcp.CallToolParams{
+			Name:      "show-transaction",
+			Arguments: map[string]interface{}{"hash": hash},
+		}
+
+		result, err := handler.HandleShow(context.Background(), &mcp.ServerSession{}, params)
+
+		require.NoError(t, err)
+		failure := decodeValidation(t, result)
+		require.Len(t, failure.Problems, 1, hash)
+		assert.Equal(t, "hash", failure.Problems[0].Field)
+	}
+}
+
+func TestTransactionsHandler_NoConnection(t *testing.T) {
+	handler := NewTransactionsHandler(nil, 5*time.Second)
+
+	result, err := handler.HandleShow(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "show-transaction",
+		Arguments: map[string]interface{}{"hash": testTxHash},
+	})
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "gRPC connection to blockchain is not available")
+
+	result, err = handler.HandleList(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{
+		Name:      "list-transactions",
+		Arguments: map[string]interface{}{"action": "MsgCreateProvider"},
+	})
+	require.NoError(t, err)
+	textContent, ok = result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "gRPC connection to blockchain is not available")
+}
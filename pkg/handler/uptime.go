@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"overlock-mcp-server/pkg/network"
+	"overlock-mcp-server/pkg/uptime"
+
+	"github.com/Oudwins/zog"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rs/zerolog/log"
+)
+
+// UptimeInput represents the input parameters for the provider-uptime tool
+type UptimeInput struct {
+	Id      int    `json:"id,omitempty"`
+	Network string `json:"network,omitempty"`
+	Since   string `json:"since,omitempty"`
+	Until   string `json:"until,omitempty"`
+}
+
+// UptimeResponse is the response of the provider-uptime tool
+type UptimeResponse struct {
+	Since string `json:"since,omitempty"`
+	Until string `json:"until,omitempty"`
+	uptime.Report
+}
+
+// UptimeHandler handles the provider-uptime tool requests
+type UptimeHandler struct {
+	samples  uptime.Store
+	networks *network.Registry
+}
+
+// NewUptimeHandler creates a new uptime handler reading the samples of the given networks from the store
+func NewUptimeHandler(samples uptime.Store, networks *network.Registry) *UptimeHandler {
+	return &UptimeHandler{
+		samples:  samples,
+		networks: networks,
+	}
+}
+
+// Handle processes the provider-uptime tool call
+func (h *UptimeHandler) Handle(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	// Create a logger with request context
+	logger := log.With().
+		Str("tool", "provider-uptime").
+		Str("request_id", fmt.Sprintf("%p", params)).
+		Logger()
+
+	logger.Info().Msg("Processing provider-uptime request")
+
+	// Define validation schema using Zog
+	schema := zog.Struct(zog.Shape{
+		"id":      zog.Int().GTE(1),
+		"network": zog.String().Default(""),
+		"since":   zog.String().Default("24h"),
+		"until":   zog.String().Default(""),
+	})
+
+	// Validate input parameters
+	var input UptimeInput
+	arguments := params.Arguments
+	if arguments == nil {
+		arguments = make(map[string]interface{})
+	}
+
+	logger.Debug().Interface("arguments", arguments).Msg("Validating input arguments")
+	// Parse and validate the arguments
+	errs := schema.Parse(arguments, &input)
+	if errs != nil {
+		logger.Error().Interface("errors", errs).Msg("Input validation failed")
+		return invalidInput(errs), nil
+	}
+
+	// Check if ID was provided (required field)
+	if input.Id == 0 {
+		logger.Error().Msg("Provider ID is required")
+		return requiredArgument("id", "provider ID is required"), nil
+	}
+
+	n, err := h.networks.Get(input.Network)
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return invalidArgument("network", "known network", input.Network, err.Error()), nil
+	}
+
+	timeRange, err := timeRangeFilter(input.Since, input.Until, time.Now())
+	if err != nil {
+		logger.Error().Err(err).Msg("Input validation failed")
+		return timeRangeProblem(input.Since, input.Until, err), nil
+	}
+
+	if h.samples == nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: "Provider uptime is not available: it is recorded by the background prober. Set OVERLOCK_UPTIME_ENABLED=true to enable it.",
+				},
+			},
+		}, nil
+	}
+
+	filter := uptime.Filter{
+		Network:    n.Name,
+		ProviderID: uint64(input.Id),
+		Since:      timeRange.Since,
+		Until:      timeRange.Until,
+	}
+
+	samples, err := h.samples.Query(filter)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to query uptime samples")
+		return nil, fmt.Errorf("failed to query uptime samples: %w", err)
+	}
+
+	response := UptimeResponse{Report: uptime.Summarize(n.Name, filter.ProviderID, samples)}
+	if !filter.Since.IsZero() {
+		response.Since = filter.Since.UTC().Format(time.RFC3339)
+	}
+	if !filter.Until.IsZero() {
+		response.Until = filter.Until.UTC().Format(time.RFC3339)
+	}
+
+	logger.Info().
+		Str("network", n.Name).
+		Uint64("provider_id", filter.ProviderID).
+		Int("probe_count", len(samples)).
+		Msg("Successfully summarized provider uptime")
+
+	responseJSON, err := renderJSON(response, nil)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to marshal response")
+		return nil, fmt.Errorf("failed to marshal uptime response: %w", err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: responseJSON,
+			},
+		},
+	}, nil
+}
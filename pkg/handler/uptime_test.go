@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"overlock-mcp-server/pkg/network"
+	"overlock-mcp-server/pkg/uptime"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRecordedNetworks returns the networks the test samples and changes are recorded for
+func newRecordedNetworks() *network.Registry {
+	return network.NewRegistry("default", &network.Network{Name: "default"}, &network.Network{Name: "testnet"})
+}
+
+func newTestUptime(t *testing.T) uptime.Store {
+	now := time.Now().UTC()
+	samples := uptime.NewMemoryStore(0)
+	require.NoError(t, samples.Append([]uptime.Sample{
+		{Network: "default", ProviderID: 1, Address: "203.0.113.10:8080", At: now.Add(-48 * time.Hour), Up: false, Error: "connection refused"},
+		{Network: "default", ProviderID: 1, Address: "203.0.113.10:8080", At: now.Add(-3 * time.Hour), Up: true, LatencyMs: 12},
+		{Network: "default", ProviderID: 1, Address: "203.0.113.10:8080", At: now.Add(-2 * time.Hour), Up: false, Error: "i/o timeout"},
+		{Network: "default", ProviderID: 1, Address: "203.0.113.10:8080", At: now.Add(-time.Hour), Up: true, LatencyMs: 20},
+		{Network: "testnet", ProviderID: 1, Address: "198.51.100.1:8080", At: now.Add(-time.Hour), Up: true, LatencyMs: 5},
+	}))
+	return samples
+}
+
+func decodeUptime(t *testing.T, result *mcp.CallToolResult) UptimeResponse {
+	require.NotNil(t, result)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+
+	var response UptimeResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	return response
+}
+
+func TestUptimeHandler_Handle(t *testing.T) {
+	handler := NewUptimeHandler(newTestUptime(t), newRecordedNetworks())
+
+	params := &mcp.CallToolParams{
+		Name:      "provider-uptime",
+		Arguments: map[string]interface{}{"id": 1},
+	}
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	response := decodeUptime(t, result)
+	assert.Equal(t, "default", response.Network)
+	assert.NotEmpty(t, response.Since)
+	// The default 24h window excludes the outage two days ago
+	assert.Equal(t, 3, response.Probes)
+	assert.Equal(t, 2, response.Successful)
+	require.NotNil(t, response.UptimePercent)
+	assert.Equal(t, 66.67, *response.UptimePercent)
+	assert.Equal(t, 12.0, *response.LatencyP50Ms)
+	assert.Equal(t, 20.0, *response.LatencyP95Ms)
+	require.Len(t, response.Outages, 1)
+	assert.Equal(t, "1h0m0s", response.Outages[0].Duration)
+	assert.Equal(t, "i/o timeout", response.Outages[0].LastError)
+}
+
+func TestUptimeHandler_Handle_NetworkAndRange(t *testing.T) {
+	handler := NewUptimeHandler(newTestUptime(t), newRecordedNetworks())
+
+	params := &mcp.CallToolParams{
+		Name:      "provider-uptime",
+		Arguments: map[string]interface{}{"id": 1, "network": "testnet", "since": "72h", "until": "30m"},
+	}
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	response := decodeUptime(t, result)
+	assert.Equal(t, "testnet", response.Network)
+	assert.NotEmpty(t, response.Until)
+	assert.Equal(t, 1, response.Probes)
+	assert.Equal(t, []string{"198.51.100.1:8080"}, response.Addresses)
+}
+
+func TestUptimeHandler_Handle_NoSamples(t *testing.T) {
+	handler := NewUptimeHandler(newTestUptime(t), newRecordedNetworks())
+
+	params := &mcp.CallToolParams{
+		Name:      "provider-uptime",
+		Arguments: map[string]interface{}{"id": 99},
+	}
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	response := decodeUptime(t, result)
+	assert.Equal(t, 0, response.Probes)
+	assert.Nil(t, response.UptimePercent)
+	assert.Empty(t, response.Outages)
+}
+
+func TestUptimeHandler_Handle_UnknownNetwork(t *testing.T) {
+	handler := NewUptimeHandler(newTestUptime(t), newRecordedNetworks())
+
+	params := &mcp.CallToolParams{
+		Name:      "provider-uptime",
+		Arguments: map[string]interface{}{"id": 1, "network": "devnet"},
+	}
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	failure := decodeValidation(t, result)
+	require.Len(t, failure.Problems, 1)
+	assert.Equal(t, "network", failure.Problems[0].Field)
+}
+
+func TestUptimeHandler_Handle_MissingID(t *testing.T) {
+	handler := NewUptimeHandler(newTestUptime(t), newRecordedNetworks())
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{Name: "provider-uptime"})
+
+	require.NoError(t, err)
+	assert.Contains(t, decodeValidation(t, result).Error, "provider ID is required")
+}
+
+func TestUptimeHandler_Handle_InvalidRange(t *testing.T) {
+	handler := NewUptimeHandler(newTestUptime(t), newRecordedNetworks())
+
+	params := &mcp.CallToolParams{
+		Name:      "provider-uptime",
+		Arguments: map[string]interface{}{"id": 1, "since": "yesterday"},
+	}
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	assert.Contains(t, decodeValidation(t, result).Error, "since: 'yesterday'")
+}
+
+func TestUptimeHandler_Handle_Disabled(t *testing.T) {
+	handler := NewUptimeHandler(nil, newRecordedNetworks())
+
+	params := &mcp.CallToolParams{
+		Name:      "provider-uptime",
+		Arguments: map[string]interface{}{"id": 1},
+	}
+
+	result, err := handler.Handle(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "OVERLOCK_UPTIME_ENABLED=true")
+}
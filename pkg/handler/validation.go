@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"overlock-mcp-server/pkg/render"
+
+	"github.com/Oudwins/zog"
+	"github.com/Oudwins/zog/zconst"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Problem describes one invalid tool argument
+type Problem struct {
+	Field      string      `json:"field"`
+	Constraint string      `json:"constraint"`
+	Received   interface{} `json:"received,omitempty"`
+	Hint       string      `json:"hint"`
+}
+
+// ValidationFailure is the content of the error result returned for invalid arguments
+type ValidationFailure struct {
+	Error    string    `json:"error"`
+	Problems []Problem `json:"problems"`
+}
+
+// invalidInput returns the error result for the issues of a zog schema parse
+func invalidInput(errs zog.ZogIssueMap) *mcp.CallToolResult {
+	var problems []Problem
+	for key, issues := range errs {
+		if key == zconst.ISSUE_KEY_FIRST {
+			continue
+		}
+		for _, issue := range issues {
+			problems = append(problems, issueProblem(key, issue))
+		}
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Field < problems[j].Field
+	})
+	return validationResult(problems...)
+}
+
+// invalidArgument returns the error result for a single invalid argument
+func invalidArgument(field, constraint string, received interface{}, hint string) *mcp.CallToolResult {
+	return validationResult(Problem{Field: field, Constraint: constraint, Received: received, Hint: hint})
+}
+
+// requiredArgument returns the error result for a missing required argument
+func requiredArgument(field, hint string) *mcp.CallToolResult {
+	return invalidArgument(field, "required", nil, hint)
+}
+
+// validationResult renders the problems as an error tool result the model can correct its call from
+func validationResult(problems ...Problem) *mcp.CallToolResult {
+	hints := make([]string, len(problems))
+	for i, problem := range problems {
+		hints[i] = problem.Field + ": " + problem.Hint
+	}
+	failure := ValidationFailure{
+		Error:    "validation failed: " + strings.Join(hints, "; "),
+		Problems: problems,
+	}
+	text, err := renderJSON(failure, nil)
+	if err != nil {
+		text = failure.Error
+	}
+	return &mcp.CallToolResult{
+		IsError: true,
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: text,
+			},
+		},
+	}
+}
+
+// issueProblem converts a zog issue into a problem named after the snake_case argument
+func issueProblem(key string, issue *zog.ZogIssue) Problem {
+	problem := Problem{
+		Field:      render.SnakeCase(key),
+		Constraint: issue.Code,
+		Received:   issue.Value,
+		Hint:       issue.Message,
+	}
+	if param, ok := issue.Params[issue.Code]; ok {
+		problem.Constraint = fmt.Sprintf("%s %v", issue.Code, param)
+	}
+	switch issue.Code {
+	case zconst.IssueCodeCoerce:
+		problem.Constraint = "type " + issue.Dtype
+		problem.Hint = fmt.Sprintf("expected a %s, received %T", issue.Dtype, issue.Value)
+	case zconst.IssueCodeOneOf:
+		problem.Hint = fmt.Sprintf("must be one of: %s", oneOfOptions(issue.Params[issue.Code]))
+	}
+	return problem
+}
+
+// oneOfOptions lists the allowed values of a one_of_options issue, leaving out the empty default
+func oneOfOptions(param interface{}) string {
+	var options []string
+	switch values := param.(type) {
+	case []string:
+		for _, value := range values {
+			if value != "" {
+				options = append(options, value)
+			}
+		}
+	default:
+		return fmt.Sprint(param)
+	}
+	return strings.Join(options, ", ")
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeValidation checks that result is a validation error result and decodes it
+func decodeValidation(t *testing.T, result *mcp.CallToolResult) ValidationFailure {
+	t.Helper()
+	require.NotNil(t, result)
+	assert.True(t, result.IsError, "validation failures are error results")
+	require.Len(t, result.Content, 1)
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	var failure ValidationFailure
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &failure))
+	assert.Contains(t, failure.Error, "validation failed")
+	return failure
+}
+
+func TestValidation_NegativeOffset(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewProvidersHandler(mockClient, 30*time.Second)
+
+	params := &mcp.CallToolParams{
+		Name:      "get-providers",
+		Arguments: map[string]interface{}{"offset": -5},
+	}
+
+	result, err := handler.HandleList(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	failure := decodeValidation(t, result)
+	require.Len(t, failure.Problems, 1)
+	problem := failure.Problems[0]
+	assert.Equal(t, "offset", problem.Field)
+	assert.Equal(t, "gte 0", problem.Constraint)
+	assert.EqualValues(t, -5, problem.Received)
+	assert.NotEmpty(t, problem.Hint)
+	mockClient.AssertNotCalled(t, "ListProvider")
+}
+
+func TestValidation_WrongTypes(t *testing.T) {
+	mockClient := &MockQueryClient{}
+	handler := NewProvidersHandler(mockClient, 30*time.Second)
+
+	params := &mcp.CallToolParams{
+		Name: "get-providers",
+		Arguments: map[string]interface{}{
+			"limit":      "ten",
+			"max_tokens": -1,
+			"source":     "cache",
+		},
+	}
+
+	result, err := handler.HandleList(context.Background(), &mcp.ServerSession{}, params)
+
+	require.NoError(t, err)
+	failure := decodeValidation(t, result)
+	require.Len(t, failure.Problems, 3)
+
+	// Problems are sorted by field and named after the snake_case argument
+	assert.Equal(t, "limit", failure.Problems[0].Field)
+	assert.Equal(t, "type number", failure.Problems[0].Constraint)
+	assert.Equal(t, "ten", failure.Problems[0].Received)
+	assert.Equal(t, "expected a number, received string", failure.Problems[0].Hint)
+
+	assert.Equal(t, "max_tokens", failure.Problems[1].Field)
+	assert.Equal(t, "gte 0", failure.Problems[1].Constraint)
+
+	assert.Equal(t, "source", failure.Problems[2].Field)
+	assert.Equal(t, "cache", failure.Problems[2].Received)
+	assert.Equal(t, "must be one of: chain, index", failure.Problems[2].Hint)
+
+	assert.Contains(t, failure.Error, "limit: expected a number, received string")
+	mockClient.AssertNotCalled(t, "ListProvider")
+}
+
+func TestValidation_RequiredArgument(t *testing.T) {
+	handler := NewProvidersHandler(&MockQueryClient{}, 30*time.Second)
+
+	result, err := handler.HandleShow(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{Name: "show-provider"})
+
+	require.NoError(t, err)
+	failure := decodeValidation(t, result)
+	assert.Equal(t, []Problem{{Field: "id", Constraint: "required", Hint: "provider ID is required (or pass name or uri instead)"}}, failure.Problems)
+}
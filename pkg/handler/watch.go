@@ -0,0 +1,446 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"overlock-mcp-server/pkg/network"
+	"overlock-mcp-server/pkg/render"
+	"overlock-mcp-server/pkg/watch"
+
+	"github.com/Oudwins/zog"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// watchLogger is the logger name of the change notifications sent to subscribed sessions
+const watchLogger = "overlock.resources"
+
+// SubscribeInput represents the input parameters for the subscribe-resource tool
+type SubscribeInput struct {
+	Uri         string `json:"uri,omitempty"`
+	Kind        string `json:"kind,omitempty"`
+	Id          int    `json:"id,omitempty"`
+	Network     string `json:"network,omitempty"`
+	MinInterval string `json:"min_interval,omitempty" zog:"min_interval"`
+}
+
+// UnsubscribeInput represents the input parameters for the unsubscribe-resource tool
+type UnsubscribeInput struct {
+	Subscription string `json:"subscription,omitempty"`
+}
+
+// SubscriptionView is a subscription as reported by the watch tools
+type SubscriptionView struct {
+	watch.Subscription
+	Interval string `json:"interval"`
+}
+
+// SubscriptionsResponse is the response of the list-subscriptions tool
+type SubscriptionsResponse struct {
+	Count         int                `json:"count"`
+	Subscriptions []SubscriptionView `json:"subscriptions"`
+}
+
+// WatchHandler lets sessions subscribe to providers, environments and their collections
+// and notifies them when a background poll sees a change
+type WatchHandler struct {
+	chainBackend
+	server  *mcp.Server
+	manager *watch.Manager
+}
+
+// NewWatchHandlerForNetworks creates a watch handler that polls the given networks and
+// notifies the sessions of server
+func NewWatchHandlerForNetworks(networks *network.Registry, server *mcp.Server, opts watch.Options) *WatchHandler {
+	h := &WatchHandler{
+		chainBackend: newChainBackendForNetworks("blockchain-client-watch", networks),
+		server:       server,
+	}
+	if opts.SessionAlive == nil && server != nil {
+		opts.SessionAlive = h.sessionAlive
+	}
+	if opts.Released == nil && server != nil {
+		opts.Released = h.unlist
+	}
+	h.manager = watch.NewManager(h.fetch, h.notify, opts)
+	return h
+}
+
+// Run polls the watched resources until ctx is cancelled
+func (h *WatchHandler) Run(ctx context.Context) {
+	h.manager.Run(ctx)
+}
+
+// ResourceTemplates returns the resource templates served by ReadResource
+func (h *WatchHandler) ResourceTemplates() []*mcp.ResourceTemplate {
+	return []*mcp.ResourceTemplate{
+		{
+			Name:        "provider",
+			URITemplate: watch.URIScheme + "://{network}/providers/{id}",
+			Description: "A provider registered on an Overlock network",
+			MIMEType:    "application/json",
+		},
+		{
+			Name:        "environment",
+			URITemplate: watch.URIScheme + "://{network}/environments/{id}",
+			Description: "An environment registered on an Overlock network",
+			MIMEType:    "application/json",
+		},
+		{
+			Name:        "providers",
+			URITemplate: watch.URIScheme + "://{network}/providers",
+			Description: "Every provider registered on an Overlock network",
+			MIMEType:    "application/json",
+		},
+		{
+			Name:        "environments",
+			URITemplate: watch.URIScheme + "://{network}/environments",
+			Description: "Every environment registered on an Overlock network",
+			MIMEType:    "application/json",
+		},
+	}
+}
+
+// ReadResource serves resources/read for overlock:// URIs
+func (h *WatchHandler) ReadResource(ctx context.Context, session *mcp.ServerSession, params *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error) {
+	target, err := watch.ParseURI(params.URI)
+	if err != nil {
+		return nil, mcp.ResourceNotFoundError(params.URI)
+	}
+	state, err := h.fetch(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, mcp.ResourceNotFoundError(params.URI)
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{
+				URI:      params.URI,
+				MIMEType: "application/json",
+				Text:     string(state),
+			},
+		},
+	}, nil
+}
+
+// HandleSubscribe processes the subscribe-resource tool call
+func (h *WatchHandler) HandleSubscribe(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	// Create a logger with request context
+	logger := log.With().
+		Str("tool", "subscribe-resource").
+		Str("request_id", fmt.Sprintf("%p", params)).
+		Logger()
+
+	logger.Info().Msg("Processing subscribe-resource request")
+
+	// Define validation schema using Zog
+	schema := zog.Struct(zog.Shape{
+		"uri":         zog.String().Default(""),
+		"kind":        zog.String().OneOf([]string{"", watch.KindProvider, watch.KindEnvironment, watch.KindProviders, watch.KindEnvironments}).Default(""),
+		"id":          zog.Int().GTE(0).Default(0),
+		"network":     zog.String().Default(""),
+		"minInterval": zog.String().Default("30s"),
+	})
+
+	// Validate input parameters
+	var input SubscribeInput
+	arguments := params.Arguments
+	if arguments == nil {
+		arguments = make(map[string]interface{})
+	}
+
+	logger.Debug().Interface("arguments", arguments).Msg("Validating input arguments")
+	// Parse and validate the arguments
+	errs := schema.Parse(arguments, &input)
+	if errs != nil {
+		logger.Error().Interface("errors", errs).Msg("Input validation failed")
+		return invalidInput(errs), nil
+	}
+
+	target, invalid := h.subscribeTarget(input)
+	if invalid != nil {
+		logger.Error().Msg("Input validation failed")
+		return invalid, nil
+	}
+
+	interval, err := time.ParseDuration(input.MinInterval)
+	if err != nil || interval <= 0 {
+		logger.Error().Str("min_interval", input.MinInterval).Msg("Input validation failed")
+		return invalidArgument("min_interval", "positive duration", input.MinInterval, "must be a positive duration such as '30s'"), nil
+	}
+
+	sub, err := h.manager.Subscribe(sessionID(session), target, interval)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to subscribe")
+		if errors.Is(err, watch.ErrTooManySubscriptions) {
+			return invalidArgument("uri", "subscription limit", target.URI(), err.Error()), nil
+		}
+		return nil, err
+	}
+
+	// List the resource so clients can read it and learn about it through list-changed notifications
+	if h.server != nil {
+		h.server.AddResource(h.resource(target), h.ReadResource)
+	}
+
+	logger.Info().
+		Str("subscription_id", sub.ID).
+		Str("uri", sub.URI).
+		Dur("interval", sub.Interval).
+		Msg("Subscribed to resource")
+
+	return jsonToolResult(newSubscriptionView(sub), "subscription")
+}
+
+// subscribeTarget resolves the watched target from either a URI or kind, id and network
+func (h *WatchHandler) subscribeTarget(input SubscribeInput) (watch.Target, *mcp.CallToolResult) {
+	var target watch.Target
+	switch {
+	case input.Uri != "":
+		if input.Kind != "" || input.Id != 0 {
+			return target, invalidArgument("uri", "exclusive with kind and id", input.Uri, "pass either uri or kind and id, not both")
+		}
+		parsed, err := watch.ParseURI(input.Uri)
+		if err != nil {
+			return target, invalidArgument("uri", "overlock uri", input.Uri, err.Error())
+		}
+		target = parsed
+	case input.Kind != "":
+		target = watch.Target{Network: input.Network, Kind: input.Kind, ID: uint64(input.Id)}
+		collection := input.Kind == watch.KindProviders || input.Kind == watch.KindEnvironments
+		if collection && input.Id != 0 {
+			return target, invalidArgument("id", "unset for collections", input.Id, fmt.Sprintf("id cannot be used when watching the %s collection", input.Kind))
+		}
+		if !collection && input.Id == 0 {
+			return target, requiredArgument("id", fmt.Sprintf("%s ID is required", input.Kind))
+		}
+	default:
+		return target, requiredArgument("uri", "either uri or kind is required")
+	}
+
+	resolved, err := h.resolve(target.Network)
+	if err != nil {
+		return target, invalidArgument("network", "known network", target.Network, err.Error())
+	}
+	target.Network = resolved.network
+	return target, nil
+}
+
+// HandleUnsubscribe processes the unsubscribe-resource tool call
+func (h *WatchHandler) HandleUnsubscribe(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	// Create a logger with request context
+	logger := log.With().
+		Str("tool", "unsubscribe-resource").
+		Str("request_id", fmt.Sprintf("%p", params)).
+		Logger()
+
+	logger.Info().Msg("Processing unsubscribe-resource request")
+
+	// Define validation schema using Zog
+	schema := zog.Struct(zog.Shape{
+		"subscription": zog.String().Required(),
+	})
+
+	// Validate input parameters
+	var input UnsubscribeInput
+	arguments := params.Arguments
+	if arguments == nil {
+		arguments = make(map[string]interface{})
+	}
+
+	// Parse and validate the arguments
+	errs := schema.Parse(arguments, &input)
+	if errs != nil || input.Subscription == "" {
+		logger.Error().Interface("errors", errs).Msg("Input validation failed")
+		return requiredArgument("subscription", "subscription ID or URI is required"), nil
+	}
+
+	text := fmt.Sprintf("Subscription '%s' removed.", input.Subscription)
+	if !h.manager.Unsubscribe(sessionID(session), input.Subscription) {
+		text = fmt.Sprintf("No subscription '%s' found for this session.", input.Subscription)
+	}
+	logger.Info().Str("subscription", input.Subscription).Msg(text)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: text,
+			},
+		},
+	}, nil
+}
+
+// HandleList processes the list-subscriptions tool call
+func (h *WatchHandler) HandleList(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	subs := h.manager.List(sessionID(session))
+	response := SubscriptionsResponse{
+		Count:         len(subs),
+		Subscriptions: make([]SubscriptionView, 0, len(subs)),
+	}
+	for _, sub := range subs {
+		response.Subscriptions = append(response.Subscriptions, newSubscriptionView(sub))
+	}
+	return jsonToolResult(response, "subscriptions")
+}
+
+// fetch reads the current state of a target from its network as indented JSON
+func (h *WatchHandler) fetch(ctx context.Context, target watch.Target) ([]byte, error) {
+	chain, err := h.resolve(target.Network)
+	if err != nil {
+		return nil, err
+	}
+	if chain.chainClient == nil {
+		return nil, errors.New("gRPC connection to blockchain is not available")
+	}
+
+	var state interface{}
+	switch target.Kind {
+	case watch.KindProvider:
+		result, _, err := chain.execute(ctx, 0, func(ctx context.Context, client overlockv1beta1.QueryClient, opts ...grpc.CallOption) (interface{}, error) {
+			return client.ShowProvider(ctx, &overlockv1beta1.QueryShowProviderRequest{Id: target.ID}, opts...)
+		})
+		if err != nil {
+			return notFound(err)
+		}
+		resp, ok := result.(*overlockv1beta1.QueryShowProviderResponse)
+		if !ok || resp == nil || resp.Provider == nil {
+			return nil, nil
+		}
+		state = resp.Provider
+	case watch.KindEnvironment:
+		result, _, err := chain.execute(ctx, 0, func(ctx context.Context, client overlockv1beta1.QueryClient, opts ...grpc.CallOption) (interface{}, error) {
+			return client.ShowEnvironment(ctx, &overlockv1beta1.QueryShowEnvironmentRequest{Id: target.ID}, opts...)
+		})
+		if err != nil {
+			return notFound(err)
+		}
+		resp, ok := result.(*overlockv1beta1.QueryShowEnvironmentResponse)
+		if !ok || resp == nil || resp.Environment == nil {
+			return nil, nil
+		}
+		state = resp.Environment
+	case watch.KindProviders:
+		var truncated bool
+		state, truncated, _, err = chain.listProviders(ctx, 0, "", fullListMaxPages)
+		warnTruncated(target, truncated)
+	case watch.KindEnvironments:
+		var truncated bool
+		state, truncated, _, err = chain.listEnvironments(ctx, 0, "", fullListMaxPages)
+		warnTruncated(target, truncated)
+	default:
+		err = fmt.Errorf("unsupported resource kind '%s'", target.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return render.JSON(state)
+}
+
+// notify sends the subscriber a logging notification naming the updated URI. The SDK cannot
+// send notifications/resources/updated, so the log message carries that method name; like
+// every log message it is only delivered once the client has called logging/setLevel.
+func (h *WatchHandler) notify(ctx context.Context, sub watch.Subscription, state []byte) {
+	if h.server == nil {
+		return
+	}
+
+	for session := range h.server.Sessions() {
+		if session.ID() != sub.SessionID {
+			continue
+		}
+		err := session.Log(ctx, &mcp.LoggingMessageParams{
+			Level:  "notice",
+			Logger: watchLogger,
+			Data: map[string]interface{}{
+				"method":          "notifications/resources/updated",
+				"uri":             sub.URI,
+				"subscription_id": sub.ID,
+				"changed_at":      sub.LastChangedAt.UTC().Format(time.RFC3339),
+				"exists":          state != nil,
+			},
+		})
+		if err != nil {
+			log.Warn().Err(err).Str("subscription_id", sub.ID).Msg("Failed to notify subscriber")
+		}
+	}
+}
+
+// unlist removes a resource nobody watches anymore from the resource list
+func (h *WatchHandler) unlist(uri string) {
+	h.server.RemoveResources(uri)
+}
+
+// resource describes a watched target as an MCP resource
+func (h *WatchHandler) resource(target watch.Target) *mcp.Resource {
+	name := fmt.Sprintf("%s %d on %s", target.Kind, target.ID, target.Network)
+	if target.ID == 0 {
+		name = fmt.Sprintf("%s on %s", target.Kind, target.Network)
+	}
+	return &mcp.Resource{
+		URI:      target.URI(),
+		Name:     name,
+		MIMEType: "application/json",
+	}
+}
+
+// sessionAlive reports whether the server still has the session
+func (h *WatchHandler) sessionAlive(id string) bool {
+	for session := range h.server.Sessions() {
+		if session.ID() == id {
+			return true
+		}
+	}
+	return false
+}
+
+// sessionID returns the ID of the calling session, empty for sessionless transports
+func sessionID(session *mcp.ServerSession) string {
+	if session == nil {
+		return ""
+	}
+	return session.ID()
+}
+
+// warnTruncated logs a watched collection that had more pages than a full walk reads
+func warnTruncated(target watch.Target, truncated bool) {
+	if truncated {
+		log.Warn().Str("uri", target.URI()).Int("max_pages", fullListMaxPages).Msg("Watched collection is larger than a full listing reads, changes past the last page are not seen")
+	}
+}
+
+// notFound maps a NotFound gRPC status to a missing record
+func notFound(err error) ([]byte, error) {
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// newSubscriptionView adds the human-readable polling interval to a subscription
+func newSubscriptionView(sub watch.Subscription) SubscriptionView {
+	return SubscriptionView{Subscription: sub, Interval: sub.Interval.String()}
+}
+
+// jsonToolResult renders a value as an indented JSON tool result
+func jsonToolResult(value interface{}, what string) (*mcp.CallToolResult, error) {
+	text, err := renderJSON(value, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s response: %w", what, err)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: text,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"overlock-mcp-server/pkg/watch"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWatchHandler_SubscribeListUnsubscribe(t *testing.T) {
+	handler := NewWatchHandlerForNetworks(newTestRegistry(&MockQueryClient{}, &MockQueryClient{}), nil, watch.Options{MinInterval: time.Minute})
+
+	params := &mcp.CallToolParams{
+		Name:      "subscribe-resource",
+		Arguments: map[string]interface{}{"kind": "provider", "id": 7, "min_interval": "5s"},
+	}
+	result, err := handler.HandleSubscribe(context.Background(), &mcp.ServerSession{}, params)
+	require.NoError(t, err)
+
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	var sub SubscriptionView
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &sub))
+	assert.Equal(t, "overlock://mainnet/providers/7", sub.URI, "the default network is filled in")
+	assert.Equal(t, "1m0s", sub.Interval, "the interval is raised to the minimum")
+
+	params = &mcp.CallToolParams{
+		Name:      "subscribe-resource",
+		Arguments: map[string]interface{}{"uri": "overlock://testnet/environments"},
+	}
+	_, err = handler.HandleSubscribe(context.Background(), &mcp.ServerSession{}, params)
+	require.NoError(t, err)
+
+	result, err = handler.HandleList(context.Background(), &mcp.ServerSession{}, &mcp.CallToolParams{Name: "list-subscriptions"})
+	require.NoError(t, err)
+	textContent, ok = result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	var list SubscriptionsResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &list))
+	require.Equal(t, 2, list.Count)
+	assert.Equal(t, sub.ID, list.Subscriptions[0].ID)
+	assert.Equal(t, "overlock://testnet/environments", list.Subscriptions[1].URI)
+
+	params = &mcp.CallToolParams{
+		Name:      "unsubscribe-resource",
+		Arguments: map[string]interface{}{"subscription": sub.ID},
+	}
+	result, err = handler.HandleUnsubscribe(context.Background(), &mcp.ServerSession{}, params)
+	require.NoError(t, err)
+	textContent, ok = result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "removed")
+
+	result, err = handler.HandleUnsubscribe(context.Background(), &mcp.ServerSession{}, params)
+	require.NoError(t, err)
+	textContent, ok = result.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "No subscription")
+}
+
+func TestWatchHandler_SubscribeValidation(t *testing.T) {
+	handler := NewWatchHandlerForNetworks(newTestRegistry(&MockQueryClient{}, &MockQueryClient{}), nil, watch.Options{})
+
+	for name, arguments := range map[string]map[string]interface{}{
+		"nothing to watch":     {},
+		"missing id":           {"kind": "provider"},
+		"id on collection":     {"kind": "providers", "id": 3},
+		"uri and kind":         {"uri": "overlock://mainnet/providers/1", "kind": "provider"},
+		"invalid uri":          {"uri": "overlock://mainnet/validators/1"},
+		"unknown network":      {"uri": "overlock://devnet/providers/1"},
+		"invalid kind":         {"kind": "validator", "id": 1},
+		"invalid interval":     {"kind": "providers", "min_interval": "often"},
+		"nonpositive interval": {"kind": "providers", "min_interval": "0s"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			params := &mcp.CallToolParams{Name: "subscribe-resource", Arguments: arguments}
+
+			result, err := handler.HandleSubscribe(context.Background(), &mcp.ServerSession{}, params)
+
+			require.NoError(t, err)
+			assert.Contains(t, decodeValidation(t, result).Error, "validation failed")
+		})
+	}
+}
+
+func TestWatchHandler_Fetch(t *testing.T) {
+	mainnetClient := &MockQueryClient{}
+	mainnetClient.On("ShowProvider", mock.Anything, &overlockv1beta1.QueryShowProviderRequest{Id: 7}).Return(&overlockv1beta1.QueryShowProviderResponse{
+		Provider: &overlockv1beta1.Provider{Id: 7, Ip: "10.0.0.1"},
+	}, nil)
+	mainnetClient.On("ShowProvider", mock.Anything, &overlockv1beta1.QueryShowProviderRequest{Id: 8}).Return(
+		(*overlockv1beta1.QueryShowProviderResponse)(nil), status.Error(codes.NotFound, "provider not found"))
+	mainnetClient.On("ListProvider", mock.Anything, mock.Anything).Return(&overlockv1beta1.QueryListProviderResponse{
+		Providers: []overlockv1beta1.Provider{{Id: 7, Ip: "10.0.0.1"}, {Id: 9, Ip: "10.0.0.9"}},
+	}, nil)
+	handler := NewWatchHandlerForNetworks(newTestRegistry(mainnetClient, nil), nil, watch.Options{})
+
+	state, err := handler.fetch(context.Background(), watch.Target{Network: "mainnet", Kind: watch.KindProviders})
+	require.NoError(t, err)
+	assert.Contains(t, string(state), "10.0.0.9")
+
+	state, err = handler.fetch(context.Background(), watch.Target{Network: "mainnet", Kind: watch.KindProvider, ID: 7})
+	require.NoError(t, err)
+	assert.Contains(t, string(state), "10.0.0.1")
+
+	state, err = handler.fetch(context.Background(), watch.Target{Network: "mainnet", Kind: watch.KindProvider, ID: 8})
+	require.NoError(t, err)
+	assert.Nil(t, state, "a missing record has no state")
+
+	_, err = handler.fetch(context.Background(), watch.Target{Network: "testnet", Kind: watch.KindProviders})
+	assert.Error(t, err, "testnet has no client")
+}
+
+func TestWatchHandler_NotifiesSubscribedSession(t *testing.T) {
+	response := &overlockv1beta1.QueryShowProviderResponse{Provider: &overlockv1beta1.Provider{Id: 7, Ip: "10.0.0.1"}}
+	mainnetClient := &MockQueryClient{}
+	mainnetClient.On("ShowProvider", mock.Anything, mock.Anything).Return(response, nil)
+
+	srv := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	handler := NewWatchHandlerForNetworks(newTestRegistry(mainnetClient, nil), srv, watch.Options{MinInterval: time.Millisecond})
+	for _, template := range handler.ResourceTemplates() {
+		srv.AddResourceTemplate(template, handler.ReadResource)
+	}
+	mcp.AddTool(srv, &mcp.Tool{Name: "subscribe-resource"}, handler.HandleSubscribe)
+	mcp.AddTool(srv, &mcp.Tool{Name: "unsubscribe-resource"}, handler.HandleUnsubscribe)
+
+	listChanged := make(chan struct{}, 10)
+	updated := make(chan map[string]interface{}, 10)
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "1.0.0"}, &mcp.ClientOptions{
+		ResourceListChangedHandler: func(context.Context, *mcp.ClientSession, *mcp.ResourceListChangedParams) {
+			listChanged <- struct{}{}
+		},
+		LoggingMessageHandler: func(_ context.Context, _ *mcp.ClientSession, params *mcp.LoggingMessageParams) {
+			if params.Logger == watchLogger {
+				data, _ := params.Data.(map[string]interface{})
+				updated <- data
+			}
+		},
+	})
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := srv.Connect(ctx, serverTransport)
+	require.NoError(t, err)
+	defer serverSession.Close()
+	session, err := client.Connect(ctx, clientTransport)
+	require.NoError(t, err)
+	defer session.Close()
+	require.NoError(t, session.SetLevel(ctx, &mcp.SetLevelParams{Level: "info"}))
+
+	_, err = session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "subscribe-resource",
+		Arguments: map[string]interface{}{"uri": "overlock://mainnet/providers/7", "min_interval": "1ms"},
+	})
+	require.NoError(t, err)
+
+	resource, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "overlock://mainnet/providers/7"})
+	require.NoError(t, err)
+	require.Len(t, resource.Contents, 1)
+	assert.Contains(t, resource.Contents[0].Text, "10.0.0.1")
+
+	start := time.Now()
+	handler.manager.Poll(ctx, start)
+	response.Provider = &overlockv1beta1.Provider{Id: 7, Ip: "10.0.0.2"}
+	handler.manager.Poll(ctx, start.Add(time.Second))
+
+	select {
+	case data := <-updated:
+		assert.Equal(t, "notifications/resources/updated", data["method"])
+		assert.Equal(t, "overlock://mainnet/providers/7", data["uri"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("no update notification received")
+	}
+	select {
+	case <-listChanged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no list-changed notification received")
+	}
+
+	// Unsubscribing removes the resource from the resource list
+	_, err = session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "unsubscribe-resource",
+		Arguments: map[string]interface{}{"subscription": "overlock://mainnet/providers/7"},
+	})
+	require.NoError(t, err)
+	resources, err := session.ListResources(ctx, &mcp.ListResourcesParams{})
+	require.NoError(t, err)
+	assert.Empty(t, resources.Resources)
+}
@@ -0,0 +1,247 @@
+package history
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"overlock-mcp-server/pkg/indexer"
+
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/rs/zerolog/log"
+)
+
+// Record kinds
+const (
+	KindProvider    = "provider"
+	KindEnvironment = "environment"
+)
+
+// Change types
+const (
+	TypeCreated = "created"
+	TypeUpdated = "updated"
+	TypeRemoved = "removed"
+)
+
+// FieldChange is a single field that differs between two versions of a record
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+// Change is a created, updated or removed record detected between two index syncs
+type Change struct {
+	Sequence    uint64        `json:"sequence"`
+	Network     string        `json:"network"`
+	Kind        string        `json:"kind"`
+	RecordID    uint64        `json:"record_id"`
+	Type        string        `json:"type"`
+	DetectedAt  time.Time     `json:"detected_at"`
+	BlockHeight int64         `json:"block_height,omitempty"`
+	Fields      []FieldChange `json:"fields,omitempty"`
+}
+
+// Filter selects changes from a Log. Zero values match everything.
+type Filter struct {
+	Network  string
+	Kind     string
+	RecordID uint64
+	Type     string
+	Since    time.Time
+	Until    time.Time
+	Limit    int
+}
+
+// Matches reports whether the change passes the filter (Limit is not considered)
+func (f Filter) Matches(c Change) bool {
+	if f.Network != "" && c.Network != f.Network {
+		return false
+	}
+	if f.Kind != "" && c.Kind != f.Kind {
+		return false
+	}
+	if f.RecordID != 0 && c.RecordID != f.RecordID {
+		return false
+	}
+	if f.Type != "" && c.Type != f.Type {
+		return false
+	}
+	if !f.Since.IsZero() && c.DetectedAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && c.DetectedAt.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// Log stores detected changes
+type Log interface {
+	// Append stores changes, assigning their sequence numbers
+	Append(changes []Change) error
+	// Query returns the changes matching the filter, newest first
+	Query(filter Filter) ([]Change, error)
+	// Prune removes changes detected before cutoff
+	Prune(cutoff time.Time) error
+	// Close releases the log's resources
+	Close() error
+}
+
+// Diff compares two snapshots of a network and returns the changes between them, ordered by kind and record ID
+func Diff(network string, previous, current *indexer.Snapshot) []Change {
+	if previous == nil || current == nil {
+		return nil
+	}
+
+	base := Change{
+		Network:     network,
+		DetectedAt:  current.SyncedAt,
+		BlockHeight: current.BlockHeight,
+	}
+
+	oldProviders := make(map[uint64]map[string]string, len(previous.Providers))
+	for i := range previous.Providers {
+		oldProviders[previous.Providers[i].Id] = ProviderFields(&previous.Providers[i])
+	}
+	newProviders := make(map[uint64]map[string]string, len(current.Providers))
+	for i := range current.Providers {
+		newProviders[current.Providers[i].Id] = ProviderFields(&current.Providers[i])
+	}
+
+	oldEnvironments := make(map[uint64]map[string]string, len(previous.Environments))
+	for i := range previous.Environments {
+		oldEnvironments[previous.Environments[i].Id] = EnvironmentFields(&previous.Environments[i])
+	}
+	newEnvironments := make(map[uint64]map[string]string, len(current.Environments))
+	for i := range current.Environments {
+		newEnvironments[current.Environments[i].Id] = EnvironmentFields(&current.Environments[i])
+	}
+
+	changes := diffRecords(base, KindProvider, oldProviders, newProviders)
+	return append(changes, diffRecords(base, KindEnvironment, oldEnvironments, newEnvironments)...)
+}
+
+// diffRecords compares two sets of flattened records of the same kind
+func diffRecords(base Change, kind string, previous, current map[uint64]map[string]string) []Change {
+	ids := make([]uint64, 0, len(previous)+len(current))
+	for id := range previous {
+		ids = append(ids, id)
+	}
+	for id := range current {
+		if _, ok := previous[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(a, b int) bool { return ids[a] < ids[b] })
+
+	var changes []Change
+	for _, id := range ids {
+		change := base
+		change.Kind = kind
+		change.RecordID = id
+
+		oldFields, existed := previous[id]
+		newFields, exists := current[id]
+		switch {
+		case !existed:
+			change.Type = TypeCreated
+			change.Fields = diffFields(nil, newFields)
+		case !exists:
+			change.Type = TypeRemoved
+			change.Fields = diffFields(oldFields, nil)
+		default:
+			change.Type = TypeUpdated
+			change.Fields = diffFields(oldFields, newFields)
+			if len(change.Fields) == 0 {
+				continue
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// diffFields returns the fields whose values differ, sorted by field name
+func diffFields(previous, current map[string]string) []FieldChange {
+	names := make(map[string]struct{}, len(previous)+len(current))
+	for name := range previous {
+		names[name] = struct{}{}
+	}
+	for name := range current {
+		names[name] = struct{}{}
+	}
+
+	var fields []FieldChange
+	for name := range names {
+		if previous[name] != current[name] {
+			fields = append(fields, FieldChange{Field: name, Old: previous[name], New: current[name]})
+		}
+	}
+	sort.Slice(fields, func(a, b int) bool { return fields[a].Field < fields[b].Field })
+	return fields
+}
+
+// ProviderFields flattens a provider into its comparable fields, keyed by JSON field path
+func ProviderFields(p *overlockv1beta1.Provider) map[string]string {
+	fields := map[string]string{
+		"creator":          p.Creator,
+		"ip":               p.Ip,
+		"port":             strconv.FormatUint(uint64(p.Port), 10),
+		"country_code":     p.CountryCode,
+		"environment_type": p.EnvironmentType,
+		"availability":     p.Availability,
+	}
+	if p.Metadata != nil {
+		fields["metadata.name"] = p.Metadata.Name
+		fields["metadata.annotations"] = p.Metadata.Annotations
+	}
+	if p.RegisterTime != nil {
+		fields["register_time"] = p.RegisterTime.UTC().Format(time.RFC3339)
+	}
+	return dropEmpty(fields)
+}
+
+// EnvironmentFields flattens an environment into its comparable fields, keyed by JSON field path
+func EnvironmentFields(e *overlockv1beta1.Environment) map[string]string {
+	fields := map[string]string{
+		"creator":  e.Creator,
+		"provider": strconv.FormatUint(e.Provider, 10),
+	}
+	if e.Metadata != nil {
+		fields["metadata.name"] = e.Metadata.Name
+		fields["metadata.annotations"] = e.Metadata.Annotations
+	}
+	return dropEmpty(fields)
+}
+
+// dropEmpty removes empty values so that unset and empty fields compare equal
+func dropEmpty(fields map[string]string) map[string]string {
+	for name, value := range fields {
+		if value == "" {
+			delete(fields, name)
+		}
+	}
+	return fields
+}
+
+// Recorder returns an indexer OnSync hook that appends the changes between successive
+// syncs of a network to the log and prunes changes older than retention (0 keeps everything)
+func Recorder(network string, changeLog Log, retention time.Duration) func(previous, current *indexer.Snapshot) {
+	return func(previous, current *indexer.Snapshot) {
+		changes := Diff(network, previous, current)
+		if len(changes) > 0 {
+			if err := changeLog.Append(changes); err != nil {
+				log.Warn().Err(err).Str("network", network).Msg("Failed to record index changes")
+				return
+			}
+			log.Info().Str("network", network).Int("change_count", len(changes)).Msg("Recorded index changes")
+		}
+		if retention > 0 {
+			if err := changeLog.Prune(time.Now().Add(-retention)); err != nil {
+				log.Warn().Err(err).Str("network", network).Msg("Failed to prune change history")
+			}
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"overlock-mcp-server/pkg/indexer"
+
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	syncedAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	previous := &indexer.Snapshot{
+		Providers: []overlockv1beta1.Provider{
+			{Id: 1, Creator: "overlock1a", Ip: "10.0.0.1", Port: 443, Metadata: &overlockv1beta1.Metadata{Name: "provider-1"}},
+			{Id: 2, Creator: "overlock1b", Ip: "10.0.0.2"},
+		},
+		Environments: []overlockv1beta1.Environment{
+			{Id: 1001, Creator: "overlock1a", Provider: 1},
+		},
+	}
+	current := &indexer.Snapshot{
+		Providers: []overlockv1beta1.Provider{
+			{Id: 1, Creator: "overlock1a", Ip: "10.0.0.9", Port: 443, Metadata: &overlockv1beta1.Metadata{Name: "provider-1"}},
+			{Id: 3, Creator: "overlock1c", CountryCode: "DE"},
+		},
+		Environments: []overlockv1beta1.Environment{
+			{Id: 1001, Creator: "overlock1a", Provider: 1},
+		},
+		BlockHeight: 700,
+		SyncedAt:    syncedAt,
+	}
+
+	changes := Diff("mainnet", previous, current)
+	require.Len(t, changes, 3)
+
+	assert.Equal(t, KindProvider, changes[0].Kind)
+	assert.Equal(t, uint64(1), changes[0].RecordID)
+	assert.Equal(t, TypeUpdated, changes[0].Type)
+	assert.Equal(t, []FieldChange{{Field: "ip", Old: "10.0.0.1", New: "10.0.0.9"}}, changes[0].Fields)
+	assert.Equal(t, "mainnet", changes[0].Network)
+	assert.Equal(t, int64(700), changes[0].BlockHeight)
+	assert.Equal(t, syncedAt, changes[0].DetectedAt)
+
+	assert.Equal(t, uint64(2), changes[1].RecordID)
+	assert.Equal(t, TypeRemoved, changes[1].Type)
+	assert.Contains(t, changes[1].Fields, FieldChange{Field: "ip", Old: "10.0.0.2"})
+
+	assert.Equal(t, uint64(3), changes[2].RecordID)
+	assert.Equal(t, TypeCreated, changes[2].Type)
+	assert.Contains(t, changes[2].Fields, FieldChange{Field: "country_code", New: "DE"})
+}
+
+func TestDiff_FirstSync(t *testing.T) {
+	current := &indexer.Snapshot{Providers: []overlockv1beta1.Provider{{Id: 1}}}
+	assert.Nil(t, Diff("mainnet", nil, current))
+}
+
+func testChanges(now time.Time) []Change {
+	return []Change{
+		{Network: "mainnet", Kind: KindProvider, RecordID: 1, Type: TypeCreated, DetectedAt: now.Add(-72 * time.Hour)},
+		{Network: "mainnet", Kind: KindEnvironment, RecordID: 1001, Type: TypeCreated, DetectedAt: now.Add(-48 * time.Hour)},
+		{Network: "testnet", Kind: KindProvider, RecordID: 1, Type: TypeUpdated, DetectedAt: now.Add(-24 * time.Hour)},
+		{Network: "mainnet", Kind: KindProvider, RecordID: 1, Type: TypeUpdated, DetectedAt: now.Add(-time.Hour)},
+	}
+}
+
+func testLog(t *testing.T, changeLog Log) {
+	now := time.Now().UTC()
+	require.NoError(t, changeLog.Append(testChanges(now)))
+
+	all, err := changeLog.Query(Filter{})
+	require.NoError(t, err)
+	require.Len(t, all, 4)
+	assert.Equal(t, uint64(4), all[0].Sequence, "newest first")
+	assert.Equal(t, uint64(1), all[3].Sequence)
+
+	provider, err := changeLog.Query(Filter{Network: "mainnet", Kind: KindProvider, RecordID: 1})
+	require.NoError(t, err)
+	require.Len(t, provider, 2)
+	assert.Equal(t, TypeUpdated, provider[0].Type)
+	assert.Equal(t, TypeCreated, provider[1].Type)
+
+	recent, err := changeLog.Query(Filter{Since: now.Add(-30 * time.Hour)})
+	require.NoError(t, err)
+	assert.Len(t, recent, 2)
+
+	limited, err := changeLog.Query(Filter{Type: TypeCreated, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, limited, 1)
+	assert.Equal(t, KindEnvironment, limited[0].Kind)
+
+	require.NoError(t, changeLog.Prune(now.Add(-36*time.Hour)))
+	remaining, err := changeLog.Query(Filter{})
+	require.NoError(t, err)
+	require.Len(t, remaining, 2)
+	assert.Equal(t, uint64(3), remaining[1].Sequence)
+}
+
+// testLogOutOfOrder appends a slower network's older change after a newer one
+func testLogOutOfOrder(t *testing.T, changeLog Log) {
+	now := time.Now().UTC()
+	require.NoError(t, changeLog.Append([]Change{{Network: "mainnet", Kind: KindProvider, RecordID: 1, Type: TypeCreated, DetectedAt: now.Add(-time.Minute)}}))
+	require.NoError(t, changeLog.Append([]Change{{Network: "testnet", Kind: KindProvider, RecordID: 1, Type: TypeCreated, DetectedAt: now.Add(-2 * time.Minute)}}))
+	require.NoError(t, changeLog.Append([]Change{{Network: "mainnet", Kind: KindProvider, RecordID: 2, Type: TypeCreated, DetectedAt: now.Add(-3 * time.Hour)}}))
+
+	recent, err := changeLog.Query(Filter{Since: now.Add(-time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, recent, 2)
+	assert.Equal(t, "testnet", recent[0].Network)
+	assert.Equal(t, "mainnet", recent[1].Network)
+
+	require.NoError(t, changeLog.Prune(now.Add(-90*time.Second)))
+	remaining, err := changeLog.Query(Filter{})
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "mainnet", remaining[0].Network)
+	assert.Equal(t, uint64(1), remaining[0].RecordID)
+}
+
+func TestMemoryLog(t *testing.T) {
+	testLog(t, NewMemoryLog(0))
+	testLogOutOfOrder(t, NewMemoryLog(0))
+}
+
+func TestMemoryLog_Capacity(t *testing.T) {
+	changeLog := NewMemoryLog(2)
+	require.NoError(t, changeLog.Append(testChanges(time.Now())))
+
+	changes, err := changeLog.Query(Filter{})
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, uint64(4), changes[0].Sequence)
+}
+
+func TestBoltLog(t *testing.T) {
+	changeLog, err := OpenBoltLog(filepath.Join(t.TempDir(), "history.db"))
+	require.NoError(t, err)
+	defer changeLog.Close()
+
+	testLog(t, changeLog)
+
+	outOfOrder, err := OpenBoltLog(filepath.Join(t.TempDir(), "history.db"))
+	require.NoError(t, err)
+	defer outOfOrder.Close()
+
+	testLogOutOfOrder(t, outOfOrder)
+}
+
+func TestRecorder(t *testing.T) {
+	changeLog := NewMemoryLog(0)
+	record := Recorder("mainnet", changeLog, time.Hour)
+
+	previous := &indexer.Snapshot{SyncedAt: time.Now().Add(-2 * time.Hour)}
+	current := &indexer.Snapshot{
+		Providers: []overlockv1beta1.Provider{{Id: 5, Creator: "overlock1e"}},
+		SyncedAt:  time.Now().Add(-2 * time.Hour),
+	}
+	record(nil, previous)
+	record(previous, current)
+
+	// The change was detected before the retention window and is pruned right away
+	changes, err := changeLog.Query(Filter{})
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	current.SyncedAt = time.Now()
+	record(previous, current)
+	changes, err = changeLog.Query(Filter{})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, uint64(5), changes[0].RecordID)
+	assert.Equal(t, TypeCreated, changes[0].Type)
+}
@@ -0,0 +1,189 @@
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// defaultMemoryCapacity bounds the number of changes a MemoryLog keeps
+const defaultMemoryCapacity = 10000
+
+// MemoryLog keeps the most recent changes in memory
+type MemoryLog struct {
+	mu       sync.RWMutex
+	changes  []Change
+	sequence uint64
+	capacity int
+}
+
+// NewMemoryLog creates an in-memory log holding at most capacity changes (0 uses the default)
+func NewMemoryLog(capacity int) *MemoryLog {
+	if capacity <= 0 {
+		capacity = defaultMemoryCapacity
+	}
+	return &MemoryLog{capacity: capacity}
+}
+
+// Append implements Log
+func (l *MemoryLog) Append(changes []Change) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, change := range changes {
+		l.sequence++
+		change.Sequence = l.sequence
+		l.changes = append(l.changes, change)
+	}
+	if overflow := len(l.changes) - l.capacity; overflow > 0 {
+		l.changes = append([]Change(nil), l.changes[overflow:]...)
+	}
+	return nil
+}
+
+// Query implements Log
+func (l *MemoryLog) Query(filter Filter) ([]Change, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	var matched []Change
+	for i := len(l.changes) - 1; i >= 0; i-- {
+		if filter.Matches(l.changes[i]) {
+			matched = append(matched, l.changes[i])
+			if filter.Limit > 0 && len(matched) >= filter.Limit {
+				break
+			}
+		}
+	}
+	return matched, nil
+}
+
+// Prune implements Log
+func (l *MemoryLog) Prune(cutoff time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	kept := make([]Change, 0, len(l.changes))
+	for _, change := range l.changes {
+		if !change.DetectedAt.Before(cutoff) {
+			kept = append(kept, change)
+		}
+	}
+	l.changes = kept
+	return nil
+}
+
+// Close implements Log
+func (l *MemoryLog) Close() error {
+	return nil
+}
+
+var changesBucket = []byte("changes")
+
+// BoltLog persists changes in a bbolt database, keyed by sequence number
+type BoltLog struct {
+	db *bolt.DB
+}
+
+// OpenBoltLog opens (or creates) the bbolt change log at path
+func OpenBoltLog(path string) (*BoltLog, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history database '%s': %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(changesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize history database '%s': %w", path, err)
+	}
+	return &BoltLog{db: db}, nil
+}
+
+// Append implements Log
+func (l *BoltLog) Append(changes []Change) error {
+	return l.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(changesBucket)
+		for _, change := range changes {
+			sequence, err := bucket.NextSequence()
+			if err != nil {
+				return err
+			}
+			change.Sequence = sequence
+			value, err := json.Marshal(change)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(sequenceKey(sequence), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Query implements Log
+func (l *BoltLog) Query(filter Filter) ([]Change, error) {
+	var matched []Change
+	err := l.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(changesBucket).Cursor()
+		for key, value := cursor.Last(); key != nil; key, value = cursor.Prev() {
+			var change Change
+			if err := json.Unmarshal(value, &change); err != nil {
+				return fmt.Errorf("failed to decode change %d: %w", binary.BigEndian.Uint64(key), err)
+			}
+			// Networks append independently, so an older change may follow a newer one
+			if filter.Matches(change) {
+				matched = append(matched, change)
+				if filter.Limit > 0 && len(matched) >= filter.Limit {
+					break
+				}
+			}
+		}
+		return nil
+	})
+	return matched, err
+}
+
+// Prune implements Log
+func (l *BoltLog) Prune(cutoff time.Time) error {
+	return l.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(changesBucket)
+		// Networks append independently, so expired changes may follow newer ones
+		var expired [][]byte
+		err := bucket.ForEach(func(key, value []byte) error {
+			var change Change
+			if err := json.Unmarshal(value, &change); err != nil {
+				return err
+			}
+			if change.DetectedAt.Before(cutoff) {
+				expired = append(expired, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range expired {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Close implements Log
+func (l *BoltLog) Close() error {
+	return l.db.Close()
+}
+
+// sequenceKey encodes a sequence number as a big-endian key so bbolt iterates changes in order
+func sequenceKey(sequence uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, sequence)
+	return key
+}
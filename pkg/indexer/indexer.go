@@ -0,0 +1,332 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// ErrNotSynced is returned when the index is queried before its first successful sync
+var ErrNotSynced = errors.New("index has not completed its first sync")
+
+// Options configures an Indexer
+type Options struct {
+	Interval time.Duration // time between syncs
+	Timeout  time.Duration // timeout applied to each page request
+	PageSize uint64        // records requested per page
+	Store    *Store        // optional on-disk persistence, nil keeps the index in memory only
+
+	// OnSync is called after every successful sync with the snapshot it replaced
+	// (nil on the very first sync) and the new snapshot
+	OnSync func(previous, current *Snapshot)
+}
+
+// Snapshot is the indexed state of one network at a single block height
+type Snapshot struct {
+	Providers    []overlockv1beta1.Provider    // sorted by ID
+	Environments []overlockv1beta1.Environment // sorted by ID
+	BlockHeight  int64
+	SyncedAt     time.Time
+}
+
+// Status reports the indexer's sync progress for the sync-status tool
+type Status struct {
+	Network          string    `json:"network"`
+	Synced           bool      `json:"synced"`
+	Syncing          bool      `json:"syncing"`
+	LastSyncAt       time.Time `json:"last_sync_at,omitzero"`
+	LastSuccessAt    time.Time `json:"last_success_at,omitzero"`
+	LastDuration     string    `json:"last_duration,omitempty"`
+	LastError        string    `json:"last_error,omitempty"`
+	ProviderCount    int       `json:"provider_count"`
+	EnvironmentCount int       `json:"environment_count"`
+	BlockHeight      int64     `json:"block_height,omitempty"`
+	SyncCount        int       `json:"sync_count"`
+	ErrorCount       int       `json:"error_count"`
+	Interval         string    `json:"interval"`
+	Persistent       bool      `json:"persistent"`
+}
+
+// Indexer periodically copies every provider and environment of a network into memory
+type Indexer struct {
+	network string
+	client  overlockv1beta1.QueryClient
+	opts    Options
+
+	syncMu sync.Mutex // serializes syncs
+
+	mu       sync.RWMutex
+	snapshot *Snapshot
+	status   Status
+}
+
+// New creates an indexer for the named network
+func New(network string, client overlockv1beta1.QueryClient, opts Options) *Indexer {
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Minute
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Second
+	}
+	if opts.PageSize == 0 {
+		opts.PageSize = 100
+	}
+
+	idx := &Indexer{
+		network: network,
+		client:  client,
+		opts:    opts,
+		status: Status{
+			Network:    network,
+			Interval:   opts.Interval.String(),
+			Persistent: opts.Store != nil,
+		},
+	}
+
+	// Warm the index from disk so tools can answer before the first sync completes
+	if opts.Store != nil {
+		snapshot, err := opts.Store.Load()
+		if err != nil {
+			log.Warn().Err(err).Str("network", network).Msg("Failed to load persisted index")
+		} else if snapshot != nil {
+			idx.setSnapshot(snapshot)
+			log.Info().
+				Str("network", network).
+				Int("provider_count", len(snapshot.Providers)).
+				Int("environment_count", len(snapshot.Environments)).
+				Time("synced_at", snapshot.SyncedAt).
+				Msg("Loaded persisted index")
+		}
+	}
+
+	return idx
+}
+
+// Network returns the name of the indexed network
+func (i *Indexer) Network() string {
+	return i.network
+}
+
+// Run syncs immediately and then on every interval until ctx is cancelled
+func (i *Indexer) Run(ctx context.Context) {
+	ticker := time.NewTicker(i.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := i.Sync(ctx); err != nil && ctx.Err() == nil {
+			log.Warn().Err(err).Str("network", i.network).Msg("Index sync failed")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync walks every page of ListProvider and ListEnvironment and replaces the snapshot.
+// All pages after the first are pinned to the block height of the first response so
+// the snapshot is consistent.
+func (i *Indexer) Sync(ctx context.Context) error {
+	i.syncMu.Lock()
+	defer i.syncMu.Unlock()
+
+	start := time.Now()
+	i.mu.Lock()
+	i.status.Syncing = true
+	i.mu.Unlock()
+
+	snapshot, err := i.fetch(ctx)
+
+	i.mu.Lock()
+	i.status.Syncing = false
+	i.status.LastSyncAt = start
+	i.status.LastDuration = time.Since(start).Round(time.Millisecond).String()
+	if err != nil {
+		i.status.LastError = err.Error()
+		i.status.ErrorCount++
+		i.mu.Unlock()
+		return err
+	}
+
+	previous := i.snapshot
+	i.status.LastError = ""
+	i.status.SyncCount++
+	i.setSnapshotLocked(snapshot)
+	duration := i.status.LastDuration
+	i.mu.Unlock()
+
+	// Persist outside the lock so index-backed queries do not wait on disk I/O.
+	// syncMu still orders the saves of concurrent syncs.
+	if i.opts.Store != nil {
+		if err := i.opts.Store.Save(snapshot); err != nil {
+			log.Warn().Err(err).Str("network", i.network).Msg("Failed to persist index")
+		}
+	}
+
+	log.Info().
+		Str("network", i.network).
+		Int("provider_count", len(snapshot.Providers)).
+		Int("environment_count", len(snapshot.Environments)).
+		Int64("block_height", snapshot.BlockHeight).
+		Str("duration", duration).
+		Msg("Index sync completed")
+
+	if i.opts.OnSync != nil {
+		i.opts.OnSync(previous, snapshot)
+	}
+	return nil
+}
+
+// fetch reads the full provider and environment sets from the chain
+func (i *Indexer) fetch(ctx context.Context) (*Snapshot, error) {
+	if i.client == nil {
+		return nil, errors.New("gRPC connection to blockchain is not available")
+	}
+
+	snapshot := &Snapshot{}
+
+	var nextKey []byte
+	for {
+		var resp *overlockv1beta1.QueryListProviderResponse
+		height, err := i.page(ctx, snapshot.BlockHeight, func(ctx context.Context, opts ...grpc.CallOption) (err error) {
+			resp, err = i.client.ListProvider(ctx, &overlockv1beta1.QueryListProviderRequest{
+				Pagination: &query.PageRequest{Key: nextKey, Limit: i.opts.PageSize},
+			}, opts...)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list providers: %w", err)
+		}
+		if snapshot.BlockHeight == 0 {
+			snapshot.BlockHeight = height
+		}
+		if resp == nil {
+			break
+		}
+		snapshot.Providers = append(snapshot.Providers, resp.Providers...)
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 || len(resp.Providers) == 0 {
+			break
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+
+	nextKey = nil
+	for {
+		var resp *overlockv1beta1.QueryListEnvironmentResponse
+		height, err := i.page(ctx, snapshot.BlockHeight, func(ctx context.Context, opts ...grpc.CallOption) (err error) {
+			resp, err = i.client.ListEnvironment(ctx, &overlockv1beta1.QueryListEnvironmentRequest{
+				Pagination: &query.PageRequest{Key: nextKey, Limit: i.opts.PageSize},
+			}, opts...)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list environments: %w", err)
+		}
+		if snapshot.BlockHeight == 0 {
+			snapshot.BlockHeight = height
+		}
+		if resp == nil {
+			break
+		}
+		snapshot.Environments = append(snapshot.Environments, resp.Environments...)
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 || len(resp.Environments) == 0 {
+			break
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+
+	sort.Slice(snapshot.Providers, func(a, b int) bool { return snapshot.Providers[a].Id < snapshot.Providers[b].Id })
+	sort.Slice(snapshot.Environments, func(a, b int) bool { return snapshot.Environments[a].Id < snapshot.Environments[b].Id })
+	snapshot.SyncedAt = time.Now().UTC()
+	return snapshot, nil
+}
+
+// page runs a single page request pinned to height (when positive) and returns
+// the block height reported by the node
+func (i *Indexer) page(ctx context.Context, height int64, call func(ctx context.Context, opts ...grpc.CallOption) error) (int64, error) {
+	if height > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, grpctypes.GRPCBlockHeightHeader, strconv.FormatInt(height, 10))
+	}
+	pageCtx, cancel := context.WithTimeout(ctx, i.opts.Timeout)
+	defer cancel()
+
+	var header metadata.MD
+	if err := call(pageCtx, grpc.Header(&header)); err != nil {
+		return 0, err
+	}
+	if values := header.Get(grpctypes.GRPCBlockHeightHeader); len(values) > 0 {
+		if h, err := strconv.ParseInt(values[0], 10, 64); err == nil {
+			return h, nil
+		}
+	}
+	return height, nil
+}
+
+// setSnapshot replaces the current snapshot
+func (i *Indexer) setSnapshot(snapshot *Snapshot) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.setSnapshotLocked(snapshot)
+}
+
+func (i *Indexer) setSnapshotLocked(snapshot *Snapshot) {
+	i.snapshot = snapshot
+	i.status.Synced = true
+	i.status.LastSuccessAt = snapshot.SyncedAt
+	i.status.ProviderCount = len(snapshot.Providers)
+	i.status.EnvironmentCount = len(snapshot.Environments)
+	i.status.BlockHeight = snapshot.BlockHeight
+}
+
+// Snapshot returns the latest snapshot, or ErrNotSynced before the first sync.
+// The returned snapshot is shared and must not be modified.
+func (i *Indexer) Snapshot() (*Snapshot, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if i.snapshot == nil {
+		return nil, ErrNotSynced
+	}
+	return i.snapshot, nil
+}
+
+// Status returns the indexer's current sync status
+func (i *Indexer) Status() Status {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.status
+}
+
+// Provider looks up a provider by ID in the snapshot
+func (s *Snapshot) Provider(id uint64) (overlockv1beta1.Provider, bool) {
+	n := sort.Search(len(s.Providers), func(i int) bool { return s.Providers[i].Id >= id })
+	if n < len(s.Providers) && s.Providers[n].Id == id {
+		return s.Providers[n], true
+	}
+	return overlockv1beta1.Provider{}, false
+}
+
+// Environment looks up an environment by ID in the snapshot
+func (s *Snapshot) Environment(id uint64) (overlockv1beta1.Environment, bool) {
+	n := sort.Search(len(s.Environments), func(i int) bool { return s.Environments[i].Id >= id })
+	if n < len(s.Environments) && s.Environments[n].Id == id {
+		return s.Environments[n], true
+	}
+	return overlockv1beta1.Environment{}, false
+}
+
+// Staleness returns how long ago the snapshot was synced
+func (s *Snapshot) Staleness() time.Duration {
+	return time.Since(s.SyncedAt)
+}
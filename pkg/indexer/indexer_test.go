@@ -0,0 +1,206 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// fakeQueryClient serves providers and environments in pages keyed by the next record's index
+type fakeQueryClient struct {
+	providers    []overlockv1beta1.Provider
+	environments []overlockv1beta1.Environment
+	height       int64
+	err          error
+
+	requestedHeights []string
+}
+
+func (c *fakeQueryClient) respond(ctx context.Context, opts []grpc.CallOption) {
+	if md, ok := metadata.FromOutgoingContext(ctx); ok {
+		c.requestedHeights = append(c.requestedHeights, md.Get("x-cosmos-block-height")...)
+	}
+	for _, opt := range opts {
+		if header, ok := opt.(grpc.HeaderCallOption); ok {
+			*header.HeaderAddr = metadata.Pairs("x-cosmos-block-height", strconv.FormatInt(c.height, 10))
+		}
+	}
+}
+
+func pageBounds(page *query.PageRequest, total int) (int, int, []byte) {
+	start := 0
+	if len(page.Key) > 0 {
+		start, _ = strconv.Atoi(string(page.Key))
+	}
+	end := min(start+int(page.Limit), total)
+	var next []byte
+	if end < total {
+		next = []byte(strconv.Itoa(end))
+	}
+	return start, end, next
+}
+
+func (c *fakeQueryClient) ListProvider(ctx context.Context, req *overlockv1beta1.QueryListProviderRequest, opts ...grpc.CallOption) (*overlockv1beta1.QueryListProviderResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.respond(ctx, opts)
+	start, end, next := pageBounds(req.Pagination, len(c.providers))
+	return &overlockv1beta1.QueryListProviderResponse{
+		Providers:  c.providers[start:end],
+		Pagination: &query.PageResponse{NextKey: next},
+	}, nil
+}
+
+func (c *fakeQueryClient) ListEnvironment(ctx context.Context, req *overlockv1beta1.QueryListEnvironmentRequest, opts ...grpc.CallOption) (*overlockv1beta1.QueryListEnvironmentResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.respond(ctx, opts)
+	start, end, next := pageBounds(req.Pagination, len(c.environments))
+	return &overlockv1beta1.QueryListEnvironmentResponse{
+		Environments: c.environments[start:end],
+		Pagination:   &query.PageResponse{NextKey: next},
+	}, nil
+}
+
+func (c *fakeQueryClient) ShowProvider(ctx context.Context, req *overlockv1beta1.QueryShowProviderRequest, opts ...grpc.CallOption) (*overlockv1beta1.QueryShowProviderResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *fakeQueryClient) ShowEnvironment(ctx context.Context, req *overlockv1beta1.QueryShowEnvironmentRequest, opts ...grpc.CallOption) (*overlockv1beta1.QueryShowEnvironmentResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func newFakeClient() *fakeQueryClient {
+	registered := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	return &fakeQueryClient{
+		providers: []overlockv1beta1.Provider{
+			{Id: 3, Creator: "overlock1c", Metadata: &overlockv1beta1.Metadata{Name: "provider-3"}},
+			{Id: 1, Creator: "overlock1a", Metadata: &overlockv1beta1.Metadata{Name: "provider-1"}, RegisterTime: &registered},
+			{Id: 2, Creator: "overlock1b", Metadata: &overlockv1beta1.Metadata{Name: "provider-2"}},
+		},
+		environments: []overlockv1beta1.Environment{
+			{Id: 1001, Creator: "overlock1a", Provider: 1},
+			{Id: 1002, Creator: "overlock1b", Provider: 2},
+		},
+		height: 500,
+	}
+}
+
+func TestIndexer_Sync(t *testing.T) {
+	client := newFakeClient()
+	idx := New("testnet", client, Options{PageSize: 2})
+
+	_, err := idx.Snapshot()
+	assert.ErrorIs(t, err, ErrNotSynced)
+
+	require.NoError(t, idx.Sync(context.Background()))
+
+	snapshot, err := idx.Snapshot()
+	require.NoError(t, err)
+	require.Len(t, snapshot.Providers, 3)
+	require.Len(t, snapshot.Environments, 2)
+	assert.Equal(t, uint64(1), snapshot.Providers[0].Id)
+	assert.Equal(t, uint64(3), snapshot.Providers[2].Id)
+	assert.Equal(t, int64(500), snapshot.BlockHeight)
+
+	// Every page after the first is pinned to the first page's height
+	assert.Equal(t, []string{"500", "500"}, client.requestedHeights)
+
+	provider, ok := snapshot.Provider(2)
+	assert.True(t, ok)
+	assert.Equal(t, "provider-2", provider.Metadata.Name)
+	_, ok = snapshot.Provider(99)
+	assert.False(t, ok)
+
+	environment, ok := snapshot.Environment(1002)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(2), environment.Provider)
+
+	status := idx.Status()
+	assert.Equal(t, "testnet", status.Network)
+	assert.True(t, status.Synced)
+	assert.Equal(t, 3, status.ProviderCount)
+	assert.Equal(t, 2, status.EnvironmentCount)
+	assert.Equal(t, 1, status.SyncCount)
+	assert.Empty(t, status.LastError)
+}
+
+func TestIndexer_SyncError_KeepsPreviousSnapshot(t *testing.T) {
+	client := newFakeClient()
+	idx := New("testnet", client, Options{})
+	require.NoError(t, idx.Sync(context.Background()))
+
+	client.err = errors.New("connection refused")
+	assert.Error(t, idx.Sync(context.Background()))
+
+	snapshot, err := idx.Snapshot()
+	require.NoError(t, err)
+	assert.Len(t, snapshot.Providers, 3)
+
+	status := idx.Status()
+	assert.Equal(t, 1, status.ErrorCount)
+	assert.Contains(t, status.LastError, "connection refused")
+}
+
+func TestIndexer_NilClient(t *testing.T) {
+	idx := New("testnet", nil, Options{})
+	assert.Error(t, idx.Sync(context.Background()))
+	assert.False(t, idx.Status().Synced)
+}
+
+func TestStore_RoundTrip(t *testing.T) {
+	store, err := OpenStore(filepath.Join(t.TempDir(), "testnet.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	empty, err := store.Load()
+	require.NoError(t, err)
+	assert.Nil(t, empty)
+
+	idx := New("testnet", newFakeClient(), Options{Store: store})
+	require.NoError(t, idx.Sync(context.Background()))
+
+	// A new indexer on the same store starts warm
+	warm := New("testnet", nil, Options{Store: store})
+	snapshot, err := warm.Snapshot()
+	require.NoError(t, err)
+	require.Len(t, snapshot.Providers, 3)
+	require.Len(t, snapshot.Environments, 2)
+	assert.Equal(t, int64(500), snapshot.BlockHeight)
+	assert.Equal(t, "provider-1", snapshot.Providers[0].Metadata.Name)
+	require.NotNil(t, snapshot.Providers[0].RegisterTime)
+	assert.True(t, snapshot.Providers[0].RegisterTime.Equal(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)))
+	assert.True(t, warm.Status().Persistent)
+}
+
+func TestIndexer_OnSync(t *testing.T) {
+	var calls [][2]*Snapshot
+	idx := New("testnet", newFakeClient(), Options{
+		PageSize: 2,
+		OnSync: func(previous, current *Snapshot) {
+			calls = append(calls, [2]*Snapshot{previous, current})
+		},
+	})
+
+	require.NoError(t, idx.Sync(context.Background()))
+	require.NoError(t, idx.Sync(context.Background()))
+
+	require.Len(t, calls, 2)
+	assert.Nil(t, calls[0][0])
+	assert.Same(t, calls[0][1], calls[1][0])
+	current, err := idx.Snapshot()
+	require.NoError(t, err)
+	assert.Same(t, current, calls[1][1])
+}
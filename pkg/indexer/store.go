@@ -0,0 +1,144 @@
+package indexer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	providersBucket    = []byte("providers")
+	environmentsBucket = []byte("environments")
+	metaBucket         = []byte("meta")
+
+	blockHeightKey = []byte("block_height")
+	syncedAtKey    = []byte("synced_at")
+)
+
+// Store persists index snapshots to a bbolt database
+type Store struct {
+	db *bolt.DB
+}
+
+// OpenStore opens (or creates) the bbolt database at path
+func OpenStore(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open index database '%s': %w", path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Save replaces the persisted snapshot
+func (s *Store) Save(snapshot *Snapshot) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{providersBucket, environmentsBucket, metaBucket} {
+			if tx.Bucket(name) != nil {
+				if err := tx.DeleteBucket(name); err != nil {
+					return err
+				}
+			}
+			if _, err := tx.CreateBucket(name); err != nil {
+				return err
+			}
+		}
+
+		providers := tx.Bucket(providersBucket)
+		for i := range snapshot.Providers {
+			value, err := snapshot.Providers[i].Marshal()
+			if err != nil {
+				return fmt.Errorf("failed to encode provider %d: %w", snapshot.Providers[i].Id, err)
+			}
+			if err := providers.Put(idKey(snapshot.Providers[i].Id), value); err != nil {
+				return err
+			}
+		}
+
+		environments := tx.Bucket(environmentsBucket)
+		for i := range snapshot.Environments {
+			value, err := snapshot.Environments[i].Marshal()
+			if err != nil {
+				return fmt.Errorf("failed to encode environment %d: %w", snapshot.Environments[i].Id, err)
+			}
+			if err := environments.Put(idKey(snapshot.Environments[i].Id), value); err != nil {
+				return err
+			}
+		}
+
+		meta := tx.Bucket(metaBucket)
+		if err := meta.Put(blockHeightKey, idKey(uint64(snapshot.BlockHeight))); err != nil {
+			return err
+		}
+		syncedAt, err := snapshot.SyncedAt.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		return meta.Put(syncedAtKey, syncedAt)
+	})
+}
+
+// Load reads the persisted snapshot, returning nil when nothing has been saved yet
+func (s *Store) Load() (*Snapshot, error) {
+	var snapshot *Snapshot
+	err := s.db.View(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(metaBucket)
+		if meta == nil {
+			return nil
+		}
+
+		snapshot = &Snapshot{}
+		if value := meta.Get(blockHeightKey); len(value) == 8 {
+			snapshot.BlockHeight = int64(binary.BigEndian.Uint64(value))
+		}
+		if value := meta.Get(syncedAtKey); value != nil {
+			if err := snapshot.SyncedAt.UnmarshalBinary(value); err != nil {
+				return err
+			}
+		}
+
+		if providers := tx.Bucket(providersBucket); providers != nil {
+			err := providers.ForEach(func(_, value []byte) error {
+				var provider overlockv1beta1.Provider
+				if err := provider.Unmarshal(value); err != nil {
+					return err
+				}
+				snapshot.Providers = append(snapshot.Providers, provider)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if environments := tx.Bucket(environmentsBucket); environments != nil {
+			return environments.ForEach(func(_, value []byte) error {
+				var environment overlockv1beta1.Environment
+				if err := environment.Unmarshal(value); err != nil {
+					return err
+				}
+				snapshot.Environments = append(snapshot.Environments, environment)
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load index: %w", err)
+	}
+	return snapshot, nil
+}
+
+// idKey encodes an ID as a big-endian key so bbolt iterates records in ID order
+func idKey(id uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, id)
+	return key
+}
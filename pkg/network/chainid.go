@@ -0,0 +1,137 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client/grpc/cmtservice"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrChainIDMismatch is returned when a node reports a different chain than the network expects
+var ErrChainIDMismatch = errors.New("chain-id mismatch")
+
+// ErrChainIDUnverified is returned when the chain-id of a node could not be checked
+var ErrChainIDUnverified = errors.New("chain-id could not be verified")
+
+// statusServicePrefix is the node status service, which stays callable on a refused
+// network so its chain-id can be verified again
+const statusServicePrefix = "/cosmos.base.tendermint.v1beta1.Service/"
+
+// CheckChainID compares the chain-id reported by a node with the expected one.
+// An empty expectation accepts any chain.
+func CheckChainID(expected, reported string) error {
+	if expected == "" || expected == reported {
+		return nil
+	}
+	return fmt.Errorf("%w: node reports '%s', expected '%s'", ErrChainIDMismatch, reported, expected)
+}
+
+// VerifyChainID asks the node for its chain-id and checks it against ExpectedChainID.
+// Networks without an expected chain-id are not queried.
+func (n *Network) VerifyChainID(ctx context.Context) error {
+	if n.ExpectedChainID == "" {
+		return nil
+	}
+	if n.Conn == nil {
+		return fmt.Errorf("%w for network '%s': gRPC connection is not available", ErrChainIDUnverified, n.Name)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.Timeout)
+	defer cancel()
+
+	resp, err := cmtservice.NewServiceClient(n.Conn).GetNodeInfo(ctx, &cmtservice.GetNodeInfoRequest{})
+	if err != nil {
+		return fmt.Errorf("%w for network '%s': %w", ErrChainIDUnverified, n.Name, err)
+	}
+	return CheckChainID(n.ExpectedChainID, resp.GetDefaultNodeInfo().GetNetwork())
+}
+
+// WatchChainID verifies the chain-id on every interval until ctx is cancelled. A network is
+// refused while its node reports another chain or cannot be checked, and served again once a
+// verification succeeds. Networks without an expected chain-id are not watched.
+func (n *Network) WatchChainID(ctx context.Context, interval time.Duration) {
+	if n.ExpectedChainID == "" {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		err := n.VerifyChainID(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			if n.Refuse(err) {
+				log.Error().Err(err).Str("network", n.Name).Msg("Refusing to serve network")
+			}
+		case n.Admit():
+			log.Info().Str("network", n.Name).Str("chain_id", n.ExpectedChainID).Msg("Chain-id verified, serving network")
+		}
+	}
+}
+
+// Refuse stops the network from being served, keeping the connection open for the node
+// status service only. It reports whether the network was being served until now.
+func (n *Network) Refuse(reason error) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	served := n.refused == nil
+	n.refused = reason
+	return served
+}
+
+// Admit serves a refused network again and reports whether it was refused
+func (n *Network) Admit() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	refused := n.refused != nil
+	n.refused = nil
+	return refused
+}
+
+// RefusedReason returns why the network is not served, nil while it is
+func (n *Network) RefusedReason() error {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.refused
+}
+
+// refusalError fails calls to a refused network, except those to the node status service
+func (n *Network) refusalError(method string) error {
+	if strings.HasPrefix(method, statusServicePrefix) {
+		return nil
+	}
+	if reason := n.RefusedReason(); reason != nil {
+		return status.Errorf(codes.Unavailable, "network '%s' is refused: %v", n.Name, reason)
+	}
+	return nil
+}
+
+// refusalUnaryInterceptor keeps background callers, such as the indexer, off a refused network
+func (n *Network) refusalUnaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if err := n.refusalError(method); err != nil {
+		return err
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// refusalStreamInterceptor is refusalUnaryInterceptor for streaming calls
+func (n *Network) refusalStreamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if err := n.refusalError(method); err != nil {
+		return nil, err
+	}
+	return streamer(ctx, desc, cc, method, opts...)
+}
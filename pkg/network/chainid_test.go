@@ -0,0 +1,117 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	cmtp2p "github.com/cometbft/cometbft/proto/tendermint/p2p"
+	"github.com/cosmos/cosmos-sdk/client/grpc/cmtservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeNodeInfo reports a fixed chain-id
+type fakeNodeInfo struct {
+	cmtservice.UnimplementedServiceServer
+	chainID string
+}
+
+func (s *fakeNodeInfo) GetNodeInfo(context.Context, *cmtservice.GetNodeInfoRequest) (*cmtservice.GetNodeInfoResponse, error) {
+	return &cmtservice.GetNodeInfoResponse{DefaultNodeInfo: &cmtp2p.DefaultNodeInfo{Network: s.chainID}}, nil
+}
+
+// newNodeInfoConn serves a node reporting chainID over an in-memory listener
+func newNodeInfoConn(t *testing.T, chainID string) *grpc.ClientConn {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	cmtservice.RegisterServiceServer(server, &fakeNodeInfo{chainID: chainID})
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestCheckChainID(t *testing.T) {
+	assert.NoError(t, CheckChainID("", "overlock-1"))
+	assert.NoError(t, CheckChainID("overlock-1", "overlock-1"))
+
+	err := CheckChainID("overlock-1", "overlock-testnet-3")
+	assert.True(t, errors.Is(err, ErrChainIDMismatch))
+	assert.Contains(t, err.Error(), "node reports 'overlock-testnet-3', expected 'overlock-1'")
+}
+
+func TestNetwork_VerifyChainID(t *testing.T) {
+	conn := newNodeInfoConn(t, "overlock-1")
+
+	n := &Network{Name: "mainnet", Conn: conn, Timeout: 5 * time.Second, ExpectedChainID: "overlock-1"}
+	assert.NoError(t, n.VerifyChainID(context.Background()))
+
+	n.ExpectedChainID = "overlock-2"
+	err := n.VerifyChainID(context.Background())
+	assert.True(t, errors.Is(err, ErrChainIDMismatch))
+
+	// Nothing is verified without an expectation, even without a connection
+	assert.NoError(t, (&Network{Name: "devnet"}).VerifyChainID(context.Background()))
+
+	err = (&Network{Name: "devnet", ExpectedChainID: "overlock-1"}).VerifyChainID(context.Background())
+	assert.True(t, errors.Is(err, ErrChainIDUnverified))
+	assert.False(t, errors.Is(err, ErrChainIDMismatch))
+}
+
+func TestNetwork_Refuse(t *testing.T) {
+	n := &Network{Name: "mainnet", Client: NewCoalescingClient(nil)}
+
+	assert.True(t, n.Refuse(ErrChainIDUnverified))
+	assert.False(t, n.Refuse(ErrChainIDMismatch))
+	assert.NotNil(t, n.Client)
+	assert.Equal(t, ErrChainIDMismatch, n.RefusedReason())
+
+	assert.True(t, n.Admit())
+	assert.False(t, n.Admit())
+	assert.NoError(t, n.RefusedReason())
+}
+
+func TestNetwork_WatchChainID(t *testing.T) {
+	conn := newNodeInfoConn(t, "overlock-1")
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	// A network refused while its node could not be checked is served once it verifies
+	unverified := &Network{Name: "mainnet", Conn: conn, Timeout: 5 * time.Second, ExpectedChainID: "overlock-1"}
+	unverified.Refuse(ErrChainIDUnverified)
+	go unverified.WatchChainID(ctx, 10*time.Millisecond)
+	assert.Eventually(t, func() bool { return unverified.RefusedReason() == nil }, time.Second, 10*time.Millisecond)
+
+	// A served network whose node switches chains is refused
+	switched := &Network{Name: "testnet", Conn: conn, Timeout: 5 * time.Second, ExpectedChainID: "overlock-testnet-3"}
+	go switched.WatchChainID(ctx, 10*time.Millisecond)
+	assert.Eventually(t, func() bool { return errors.Is(switched.RefusedReason(), ErrChainIDMismatch) }, time.Second, 10*time.Millisecond)
+}
+
+func TestNetwork_RefusalInterceptor(t *testing.T) {
+	n := &Network{Name: "mainnet"}
+	assert.NoError(t, n.refusalError("/overlock.crossplane.v1beta1.Query/ShowProvider"))
+
+	n.Refuse(ErrChainIDMismatch)
+	err := n.refusalError("/overlock.crossplane.v1beta1.Query/ShowProvider")
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Contains(t, err.Error(), "chain-id mismatch")
+
+	// The node status service stays reachable, so the chain-id can be verified again
+	assert.NoError(t, n.refusalError("/cosmos.base.tendermint.v1beta1.Service/GetNodeInfo"))
+}
@@ -0,0 +1,171 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// CoalesceStats counts the calls made through a CoalescingClient
+type CoalesceStats struct {
+	Calls     int64 `json:"calls"`     // calls made by callers
+	Coalesced int64 `json:"coalesced"` // calls served by another caller's in-flight RPC
+}
+
+// CoalescingClient is a QueryClient that deduplicates concurrent identical calls, so one
+// RPC to the node serves every caller waiting for the same method and request
+type CoalescingClient struct {
+	next  overlockv1beta1.QueryClient
+	group singleflight.Group
+
+	calls     atomic.Int64
+	coalesced atomic.Int64
+}
+
+var _ overlockv1beta1.QueryClient = (*CoalescingClient)(nil)
+
+// NewCoalescingClient wraps next so concurrent identical calls share a single RPC
+func NewCoalescingClient(next overlockv1beta1.QueryClient) *CoalescingClient {
+	return &CoalescingClient{next: next}
+}
+
+// Stats returns the client's call counters
+func (c *CoalescingClient) Stats() CoalesceStats {
+	return CoalesceStats{
+		Calls:     c.calls.Load(),
+		Coalesced: c.coalesced.Load(),
+	}
+}
+
+// marshaler is implemented by every gogoproto request message
+type marshaler interface {
+	Marshal() ([]byte, error)
+}
+
+// sharedResponse is the outcome of a shared RPC
+type sharedResponse struct {
+	response interface{}
+	header   metadata.MD
+}
+
+// do runs call once for all concurrent callers with the same method, request and outgoing
+// metadata. The shared RPC is not cancelled when one caller gives up; each caller instead
+// stops waiting when its own context is done.
+func (c *CoalescingClient) do(ctx context.Context, method string, req marshaler, opts []grpc.CallOption, call func(ctx context.Context, opts ...grpc.CallOption) (interface{}, error)) (interface{}, error) {
+	c.calls.Add(1)
+
+	key, err := coalesceKey(ctx, method, req)
+	if err != nil {
+		// Requests that cannot be keyed are not coalesced
+		return call(ctx, opts...)
+	}
+
+	// Response headers are collected once and copied to every caller asking for them
+	var headers []*metadata.MD
+	var callOpts []grpc.CallOption
+	for _, opt := range opts {
+		if header, ok := opt.(grpc.HeaderCallOption); ok {
+			headers = append(headers, header.HeaderAddr)
+			continue
+		}
+		callOpts = append(callOpts, opt)
+	}
+
+	leader := false
+	results := c.group.DoChan(key, func() (interface{}, error) {
+		leader = true
+		sharedCtx := context.WithoutCancel(ctx)
+		if deadline, ok := ctx.Deadline(); ok {
+			var cancel context.CancelFunc
+			sharedCtx, cancel = context.WithDeadline(sharedCtx, deadline)
+			defer cancel()
+		}
+		var header metadata.MD
+		response, err := call(sharedCtx, append(callOpts, grpc.Header(&header))...)
+		return sharedResponse{response: response, header: header}, err
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-results:
+		if !leader {
+			c.coalesced.Add(1)
+		}
+		shared, _ := result.Val.(sharedResponse)
+		for _, header := range headers {
+			*header = shared.header.Copy()
+		}
+		return shared.response, result.Err
+	}
+}
+
+// coalesceKey identifies a call by method, encoded request and the outgoing metadata
+// (which carries the block height a query is pinned to)
+func coalesceKey(ctx context.Context, method string, req marshaler) (string, error) {
+	body, err := req.Marshal()
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s|%x", method, body)
+	if md, ok := metadata.FromOutgoingContext(ctx); ok {
+		for _, k := range sortedMetadataKeys(md) {
+			fmt.Fprintf(&b, "|%s=%s", k, strings.Join(md[k], ","))
+		}
+	}
+	return b.String(), nil
+}
+
+// sortedMetadataKeys returns the metadata keys in a stable order
+func sortedMetadataKeys(md metadata.MD) []string {
+	keys := make([]string, 0, len(md))
+	for k := range md {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ShowEnvironment implements overlockv1beta1.QueryClient
+func (c *CoalescingClient) ShowEnvironment(ctx context.Context, in *overlockv1beta1.QueryShowEnvironmentRequest, opts ...grpc.CallOption) (*overlockv1beta1.QueryShowEnvironmentResponse, error) {
+	resp, err := c.do(ctx, "ShowEnvironment", in, opts, func(ctx context.Context, opts ...grpc.CallOption) (interface{}, error) {
+		return c.next.ShowEnvironment(ctx, in, opts...)
+	})
+	out, _ := resp.(*overlockv1beta1.QueryShowEnvironmentResponse)
+	return out, err
+}
+
+// ListEnvironment implements overlockv1beta1.QueryClient
+func (c *CoalescingClient) ListEnvironment(ctx context.Context, in *overlockv1beta1.QueryListEnvironmentRequest, opts ...grpc.CallOption) (*overlockv1beta1.QueryListEnvironmentResponse, error) {
+	resp, err := c.do(ctx, "ListEnvironment", in, opts, func(ctx context.Context, opts ...grpc.CallOption) (interface{}, error) {
+		return c.next.ListEnvironment(ctx, in, opts...)
+	})
+	out, _ := resp.(*overlockv1beta1.QueryListEnvironmentResponse)
+	return out, err
+}
+
+// ShowProvider implements overlockv1beta1.QueryClient
+func (c *CoalescingClient) ShowProvider(ctx context.Context, in *overlockv1beta1.QueryShowProviderRequest, opts ...grpc.CallOption) (*overlockv1beta1.QueryShowProviderResponse, error) {
+	resp, err := c.do(ctx, "ShowProvider", in, opts, func(ctx context.Context, opts ...grpc.CallOption) (interface{}, error) {
+		return c.next.ShowProvider(ctx, in, opts...)
+	})
+	out, _ := resp.(*overlockv1beta1.QueryShowProviderResponse)
+	return out, err
+}
+
+// ListProvider implements overlockv1beta1.QueryClient
+func (c *CoalescingClient) ListProvider(ctx context.Context, in *overlockv1beta1.QueryListProviderRequest, opts ...grpc.CallOption) (*overlockv1beta1.QueryListProviderResponse, error) {
+	resp, err := c.do(ctx, "ListProvider", in, opts, func(ctx context.Context, opts ...grpc.CallOption) (interface{}, error) {
+		return c.next.ListProvider(ctx, in, opts...)
+	})
+	out, _ := resp.(*overlockv1beta1.QueryListProviderResponse)
+	return out, err
+}
@@ -0,0 +1,160 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// blockingClient holds every ShowProvider call until release is closed
+type blockingClient struct {
+	overlockv1beta1.QueryClient
+	calls   atomic.Int64
+	started chan struct{}
+	release chan struct{}
+	err     error
+}
+
+func newBlockingClient() *blockingClient {
+	return &blockingClient{started: make(chan struct{}, 100), release: make(chan struct{})}
+}
+
+func (c *blockingClient) ShowProvider(ctx context.Context, req *overlockv1beta1.QueryShowProviderRequest, opts ...grpc.CallOption) (*overlockv1beta1.QueryShowProviderResponse, error) {
+	c.calls.Add(1)
+	c.started <- struct{}{}
+	for _, opt := range opts {
+		if header, ok := opt.(grpc.HeaderCallOption); ok {
+			*header.HeaderAddr = metadata.Pairs("x-cosmos-block-height", "77")
+		}
+	}
+	select {
+	case <-c.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &overlockv1beta1.QueryShowProviderResponse{Provider: &overlockv1beta1.Provider{Id: req.Id}}, nil
+}
+
+// waitForCalls waits until n callers are either running or waiting on the shared call
+func waitForCalls(t *testing.T, c *CoalescingClient, n int64) {
+	require.Eventually(t, func() bool { return c.Stats().Calls == n }, time.Second, time.Millisecond)
+}
+
+func TestCoalescingClient_SharesInFlightCalls(t *testing.T) {
+	upstream := newBlockingClient()
+	client := NewCoalescingClient(upstream)
+
+	const callers = 5
+	var wg sync.WaitGroup
+	responses := make([]*overlockv1beta1.QueryShowProviderResponse, callers)
+	headers := make([]metadata.MD, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := client.ShowProvider(context.Background(), &overlockv1beta1.QueryShowProviderRequest{Id: 7}, grpc.Header(&headers[i]))
+			assert.NoError(t, err)
+			responses[i] = resp
+		}(i)
+	}
+
+	<-upstream.started
+	waitForCalls(t, client, callers)
+	close(upstream.release)
+	wg.Wait()
+
+	assert.Equal(t, int64(1), upstream.calls.Load())
+	assert.Equal(t, CoalesceStats{Calls: callers, Coalesced: callers - 1}, client.Stats())
+	for i := 0; i < callers; i++ {
+		require.NotNil(t, responses[i])
+		assert.Equal(t, uint64(7), responses[i].Provider.Id)
+		assert.Equal(t, []string{"77"}, headers[i].Get("x-cosmos-block-height"))
+	}
+}
+
+func TestCoalescingClient_DistinctRequestsAreNotShared(t *testing.T) {
+	upstream := newBlockingClient()
+	close(upstream.release)
+	client := NewCoalescingClient(upstream)
+
+	pinned := metadata.AppendToOutgoingContext(context.Background(), "x-cosmos-block-height", "10")
+	for _, call := range []struct {
+		ctx context.Context
+		id  uint64
+	}{
+		{context.Background(), 1},
+		{context.Background(), 2},
+		{pinned, 1},
+	} {
+		_, err := client.ShowProvider(call.ctx, &overlockv1beta1.QueryShowProviderRequest{Id: call.id})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int64(3), upstream.calls.Load())
+	assert.Equal(t, int64(0), client.Stats().Coalesced)
+}
+
+func TestCoalescingClient_CancellationIsPerCaller(t *testing.T) {
+	upstream := newBlockingClient()
+	client := NewCoalescingClient(upstream)
+
+	// The first caller starts the shared RPC and then gives up
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderDone := make(chan error, 1)
+	go func() {
+		_, err := client.ShowProvider(leaderCtx, &overlockv1beta1.QueryShowProviderRequest{Id: 7})
+		leaderDone <- err
+	}()
+	<-upstream.started
+
+	followerDone := make(chan error, 1)
+	go func() {
+		resp, err := client.ShowProvider(context.Background(), &overlockv1beta1.QueryShowProviderRequest{Id: 7})
+		if err == nil && resp.Provider.Id != 7 {
+			err = errors.New("unexpected response")
+		}
+		followerDone <- err
+	}()
+	waitForCalls(t, client, 2)
+
+	cancelLeader()
+	assert.ErrorIs(t, <-leaderDone, context.Canceled)
+
+	// The shared RPC keeps running for the remaining caller
+	close(upstream.release)
+	assert.NoError(t, <-followerDone)
+	assert.Equal(t, int64(1), upstream.calls.Load())
+}
+
+func TestCoalescingClient_SharesErrors(t *testing.T) {
+	upstream := newBlockingClient()
+	upstream.err = errors.New("node unavailable")
+	client := NewCoalescingClient(upstream)
+
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := client.ShowProvider(context.Background(), &overlockv1beta1.QueryShowProviderRequest{Id: 7})
+			errs <- err
+		}()
+	}
+	<-upstream.started
+	waitForCalls(t, client, 2)
+	close(upstream.release)
+
+	assert.EqualError(t, <-errs, "node unavailable")
+	assert.EqualError(t, <-errs, "node unavailable")
+	assert.Equal(t, int64(1), upstream.calls.Load())
+}
@@ -0,0 +1,170 @@
+package network
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"overlock-mcp-server/pkg/config"
+
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// ErrUnknownNetwork is returned when a tool call names a network that is not configured
+var ErrUnknownNetwork = errors.New("unknown network")
+
+// Network is a single Overlock chain the server can query
+type Network struct {
+	Name    string
+	GRPCURL string
+	TLS     bool
+	Timeout time.Duration
+
+	// ExpectedChainID is the chain-id the node must report, empty accepts any chain
+	ExpectedChainID string
+
+	// refused is set while the network is reachable but deliberately not served, see Refuse
+	mu      sync.RWMutex
+	refused error
+
+	// Client is nil when the connection could not be established or validated
+	Client overlockv1beta1.QueryClient
+	Conn   *grpc.ClientConn
+
+	// Coalescer deduplicates concurrent identical calls made through Client, nil when not dialed
+	Coalescer *CoalescingClient
+}
+
+// Dial creates the gRPC connection and query client for a configured network
+func Dial(cfg config.NetworkConfig) (*Network, error) {
+	creds, err := transportCredentials(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &Network{
+		Name:            cfg.Name,
+		GRPCURL:         cfg.GRPCURL,
+		TLS:             cfg.TLS,
+		Timeout:         cfg.APITimeout,
+		ExpectedChainID: cfg.ExpectedChainID,
+	}
+	conn, err := grpc.NewClient(cfg.GRPCURL,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithChainUnaryInterceptor(n.refusalUnaryInterceptor),
+		grpc.WithChainStreamInterceptor(n.refusalStreamInterceptor),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	n.Conn = conn
+	n.Coalescer = NewCoalescingClient(overlockv1beta1.NewQueryClient(conn))
+	n.Client = n.Coalescer
+	return n, nil
+}
+
+// transportCredentials builds the gRPC transport credentials for a network
+func transportCredentials(cfg config.NetworkConfig) (credentials.TransportCredentials, error) {
+	if !cfg.TLS {
+		return insecure.NewCredentials(), nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         cfg.TLSServerName,
+		InsecureSkipVerify: cfg.TLSInsecureSkipVerify, // #nosec G402 -- opt-in for devnets with self-signed certificates
+	}
+
+	if cfg.TLSCAFile != "" {
+		pem, err := os.ReadFile(cfg.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file for network '%s': %w", cfg.Name, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file for network '%s'", cfg.Name)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// Close closes the network's gRPC connection
+func (n *Network) Close() error {
+	if n.Conn == nil {
+		return nil
+	}
+	return n.Conn.Close()
+}
+
+// Registry holds the configured networks, keyed by name
+type Registry struct {
+	networks    map[string]*Network
+	defaultName string
+}
+
+// NewRegistry creates a registry from the given networks
+func NewRegistry(defaultName string, networks ...*Network) *Registry {
+	r := &Registry{
+		networks:    make(map[string]*Network, len(networks)),
+		defaultName: defaultName,
+	}
+	for _, n := range networks {
+		r.networks[n.Name] = n
+	}
+	return r
+}
+
+// Get returns the named network, or the default network when name is empty
+func (r *Registry) Get(name string) (*Network, error) {
+	if name == "" {
+		name = r.defaultName
+	}
+	n, ok := r.networks[name]
+	if !ok {
+		return nil, fmt.Errorf("%w '%s' (available: %s)", ErrUnknownNetwork, name, strings.Join(r.Names(), ", "))
+	}
+	return n, nil
+}
+
+// Default returns the default network
+func (r *Registry) Default() *Network {
+	return r.networks[r.defaultName]
+}
+
+// DefaultName returns the name of the default network
+func (r *Registry) DefaultName() string {
+	return r.defaultName
+}
+
+// Names returns the configured network names in sorted order
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.networks))
+	for name := range r.networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Close closes every network's gRPC connection
+func (r *Registry) Close() error {
+	var errs []error
+	for _, n := range r.networks {
+		if err := n.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("network '%s': %w", n.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,61 @@
+package network
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"overlock-mcp-server/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Get(t *testing.T) {
+	registry := NewRegistry("mainnet",
+		&Network{Name: "mainnet", GRPCURL: "mainnet:9090"},
+		&Network{Name: "testnet", GRPCURL: "testnet:9090"},
+	)
+
+	n, err := registry.Get("")
+	require.NoError(t, err)
+	assert.Equal(t, "mainnet", n.Name)
+
+	n, err = registry.Get("testnet")
+	require.NoError(t, err)
+	assert.Equal(t, "testnet", n.Name)
+
+	_, err = registry.Get("devnet")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrUnknownNetwork))
+	assert.Contains(t, err.Error(), "mainnet, testnet")
+
+	assert.Equal(t, []string{"mainnet", "testnet"}, registry.Names())
+	assert.Equal(t, "mainnet", registry.Default().Name)
+	assert.Equal(t, "mainnet", registry.DefaultName())
+}
+
+func TestDial(t *testing.T) {
+	n, err := Dial(config.NetworkConfig{
+		Name:       "devnet",
+		GRPCURL:    "localhost:9090",
+		APITimeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+	defer n.Close()
+
+	assert.Equal(t, "devnet", n.Name)
+	assert.Equal(t, 5*time.Second, n.Timeout)
+	assert.NotNil(t, n.Client)
+	assert.NotNil(t, n.Conn)
+}
+
+func TestDial_MissingCAFile(t *testing.T) {
+	_, err := Dial(config.NetworkConfig{
+		Name:      "mainnet",
+		GRPCURL:   "grpc.example.com:443",
+		TLS:       true,
+		TLSCAFile: "/nonexistent/ca.pem",
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,221 @@
+package recommend
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+)
+
+// Available is the availability a provider reports when it accepts new environments
+const Available = "available"
+
+// Scoring weights
+const (
+	baseScore                  = 100
+	unreportedAvailabilityCost = 20
+	unavailableCost            = 50
+	missingEndpointCost        = 30
+	perEnvironmentCost         = 2
+	maxLoadCost                = 40
+)
+
+// Exclusion reasons
+const (
+	ExcludedCountry         = "country"
+	ExcludedEnvironmentType = "environment_type"
+	ExcludedAnnotations     = "annotations"
+	ExcludedCapacity        = "max_environments"
+	ExcludedUnavailable     = "availability"
+)
+
+// Requirements describe where a new environment may be placed
+type Requirements struct {
+	Countries          []string          // allowed country codes, any when empty
+	EnvironmentType    string            // required environment type, any when empty
+	Annotations        map[string]string // annotation values the provider must carry
+	MaxEnvironments    int               // maximum environments already hosted, negative for no limit
+	IncludeUnavailable bool              // rank providers that are not available instead of excluding them
+}
+
+// Candidate is a provider that satisfies the requirements
+type Candidate struct {
+	Provider     overlockv1beta1.Provider `json:"provider"`
+	Score        int                      `json:"score"`
+	Environments int                      `json:"hosted_environments"`
+	Matched      []string                 `json:"matched"`
+	Penalties    []string                 `json:"penalties"`
+}
+
+// Result is a ranked shortlist of providers
+type Result struct {
+	Evaluated  int            `json:"evaluated"`
+	Eligible   int            `json:"eligible"`
+	Candidates []Candidate    `json:"candidates"`
+	Excluded   map[string]int `json:"excluded"` // excluded providers per reason
+}
+
+// Rank scores every provider meeting the requirements and returns the best limit of them,
+// highest score first. Ties go to the provider hosting fewer environments, then the lower ID.
+func Rank(providers []overlockv1beta1.Provider, environments []overlockv1beta1.Environment, req Requirements, limit int) Result {
+	hosted := make(map[uint64]int)
+	for _, environment := range environments {
+		hosted[environment.Provider]++
+	}
+
+	result := Result{
+		Evaluated:  len(providers),
+		Candidates: []Candidate{},
+		Excluded:   make(map[string]int),
+	}
+	var candidates []Candidate
+	for _, provider := range providers {
+		candidate, reason := evaluate(provider, hosted[provider.Id], req)
+		if reason != "" {
+			result.Excluded[reason]++
+			continue
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	sort.Slice(candidates, func(a, b int) bool {
+		if candidates[a].Score != candidates[b].Score {
+			return candidates[a].Score > candidates[b].Score
+		}
+		if candidates[a].Environments != candidates[b].Environments {
+			return candidates[a].Environments < candidates[b].Environments
+		}
+		return candidates[a].Provider.Id < candidates[b].Provider.Id
+	})
+	result.Eligible = len(candidates)
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	if candidates != nil {
+		result.Candidates = candidates
+	}
+	return result
+}
+
+// evaluate checks a provider against the requirements, returning the exclusion reason when it does not meet them
+func evaluate(provider overlockv1beta1.Provider, environments int, req Requirements) (Candidate, string) {
+	candidate := Candidate{
+		Provider:     provider,
+		Score:        baseScore,
+		Environments: environments,
+		Matched:      []string{},
+		Penalties:    []string{},
+	}
+
+	if len(req.Countries) > 0 {
+		if !containsFold(req.Countries, provider.CountryCode) {
+			return candidate, ExcludedCountry
+		}
+		candidate.Matched = append(candidate.Matched, fmt.Sprintf("country %s is allowed", provider.CountryCode))
+	}
+
+	if req.EnvironmentType != "" {
+		if !strings.EqualFold(req.EnvironmentType, provider.EnvironmentType) {
+			return candidate, ExcludedEnvironmentType
+		}
+		candidate.Matched = append(candidate.Matched, fmt.Sprintf("environment type is %s", provider.EnvironmentType))
+	}
+
+	if len(req.Annotations) > 0 {
+		annotations, err := ParseAnnotations(provider.Metadata)
+		if err != nil {
+			return candidate, ExcludedAnnotations
+		}
+		for _, key := range sortedKeys(req.Annotations) {
+			if annotations[key] != req.Annotations[key] {
+				return candidate, ExcludedAnnotations
+			}
+			candidate.Matched = append(candidate.Matched, fmt.Sprintf("annotation %s=%s", key, req.Annotations[key]))
+		}
+	}
+
+	if req.MaxEnvironments >= 0 {
+		if environments > req.MaxEnvironments {
+			return candidate, ExcludedCapacity
+		}
+		candidate.Matched = append(candidate.Matched, fmt.Sprintf("hosts %d of at most %d environments", environments, req.MaxEnvironments))
+	}
+
+	switch {
+	case provider.Availability == Available:
+		candidate.Matched = append(candidate.Matched, "reports itself available")
+	case provider.Availability == "":
+		candidate.penalize(unreportedAvailabilityCost, "does not report its availability")
+	case !req.IncludeUnavailable:
+		return candidate, ExcludedUnavailable
+	default:
+		candidate.penalize(unavailableCost, fmt.Sprintf("reports availability '%s'", provider.Availability))
+	}
+
+	if provider.Ip == "" || provider.Port == 0 {
+		candidate.penalize(missingEndpointCost, "has no registered endpoint")
+	}
+
+	if environments > 0 {
+		candidate.penalize(min(environments*perEnvironmentCost, maxLoadCost), fmt.Sprintf("already hosts %d environment%s", environments, plural(environments)))
+	}
+	return candidate, ""
+}
+
+// penalize lowers the candidate's score and records why
+func (c *Candidate) penalize(cost int, reason string) {
+	c.Score -= cost
+	c.Penalties = append(c.Penalties, fmt.Sprintf("%s (-%d)", reason, cost))
+}
+
+// ParseAnnotations decodes the JSON object a provider or environment keeps in its annotations.
+// Non-string values are kept in their JSON form.
+func ParseAnnotations(metadata *overlockv1beta1.Metadata) (map[string]string, error) {
+	annotations := make(map[string]string)
+	if metadata == nil || strings.TrimSpace(metadata.Annotations) == "" {
+		return annotations, nil
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(metadata.Annotations), &raw); err != nil {
+		return nil, fmt.Errorf("annotations are not a JSON object: %w", err)
+	}
+	for key, value := range raw {
+		var s string
+		if err := json.Unmarshal(value, &s); err == nil {
+			annotations[key] = s
+			continue
+		}
+		annotations[key] = string(value)
+	}
+	return annotations, nil
+}
+
+// plural returns the plural suffix for a count
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
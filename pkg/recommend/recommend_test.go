@@ -0,0 +1,122 @@
+package recommend
+
+import (
+	"testing"
+
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testProviders() []overlockv1beta1.Provider {
+	return []overlockv1beta1.Provider{
+		{
+			Id: 1, CountryCode: "US", EnvironmentType: "production", Availability: Available, Ip: "10.0.0.1", Port: 8080,
+			Metadata: &overlockv1beta1.Metadata{Annotations: `{"region":"us-east-1","gpu":true}`},
+		},
+		{
+			Id: 2, CountryCode: "DE", EnvironmentType: "production", Availability: Available, Ip: "10.0.0.2", Port: 8080,
+			Metadata: &overlockv1beta1.Metadata{Annotations: `{"region":"eu-central-1"}`},
+		},
+		{
+			Id: 3, CountryCode: "DE", EnvironmentType: "production", Availability: "", Ip: "10.0.0.3", Port: 8080,
+			Metadata: &overlockv1beta1.Metadata{Annotations: `{"region":"eu-central-1"}`},
+		},
+		{
+			Id: 4, CountryCode: "DE", EnvironmentType: "staging", Availability: Available, Ip: "10.0.0.4", Port: 8080,
+		},
+		{
+			Id: 5, CountryCode: "DE", EnvironmentType: "production", Availability: "maintenance", Ip: "10.0.0.5", Port: 8080,
+		},
+	}
+}
+
+func testEnvironments() []overlockv1beta1.Environment {
+	return []overlockv1beta1.Environment{
+		{Id: 1001, Provider: 2},
+		{Id: 1002, Provider: 2},
+		{Id: 1003, Provider: 2},
+	}
+}
+
+func TestRank(t *testing.T) {
+	result := Rank(testProviders(), testEnvironments(), Requirements{
+		Countries:       []string{"de"},
+		EnvironmentType: "Production",
+		MaxEnvironments: -1,
+	}, 10)
+
+	assert.Equal(t, 5, result.Evaluated)
+	assert.Equal(t, 2, result.Eligible)
+	assert.Equal(t, map[string]int{ExcludedCountry: 1, ExcludedEnvironmentType: 1, ExcludedUnavailable: 1}, result.Excluded)
+
+	require.Len(t, result.Candidates, 2)
+	// Provider 2 hosts three environments (-6), provider 3 does not report availability (-20)
+	assert.Equal(t, uint64(2), result.Candidates[0].Provider.Id)
+	assert.Equal(t, 94, result.Candidates[0].Score)
+	assert.Equal(t, 3, result.Candidates[0].Environments)
+	assert.Contains(t, result.Candidates[0].Matched, "country DE is allowed")
+	assert.Equal(t, []string{"already hosts 3 environments (-6)"}, result.Candidates[0].Penalties)
+
+	assert.Equal(t, uint64(3), result.Candidates[1].Provider.Id)
+	assert.Equal(t, 80, result.Candidates[1].Score)
+	assert.Equal(t, []string{"does not report its availability (-20)"}, result.Candidates[1].Penalties)
+}
+
+func TestRank_Annotations(t *testing.T) {
+	result := Rank(testProviders(), nil, Requirements{
+		Annotations:     map[string]string{"region": "us-east-1", "gpu": "true"},
+		MaxEnvironments: -1,
+	}, 10)
+
+	require.Len(t, result.Candidates, 1)
+	assert.Equal(t, uint64(1), result.Candidates[0].Provider.Id)
+	assert.Contains(t, result.Candidates[0].Matched, "annotation gpu=true")
+	assert.Contains(t, result.Candidates[0].Matched, "annotation region=us-east-1")
+	assert.Equal(t, 4, result.Excluded[ExcludedAnnotations])
+}
+
+func TestRank_MaxEnvironments(t *testing.T) {
+	result := Rank(testProviders(), testEnvironments(), Requirements{MaxEnvironments: 2}, 10)
+
+	assert.Equal(t, 1, result.Excluded[ExcludedCapacity])
+	for _, candidate := range result.Candidates {
+		assert.NotEqual(t, uint64(2), candidate.Provider.Id)
+	}
+}
+
+func TestRank_IncludeUnavailableAndLimit(t *testing.T) {
+	result := Rank(testProviders(), nil, Requirements{MaxEnvironments: -1, IncludeUnavailable: true}, 2)
+
+	assert.Equal(t, 5, result.Eligible)
+	assert.Empty(t, result.Excluded)
+	require.Len(t, result.Candidates, 2)
+	// Equal scores are ordered by ID
+	assert.Equal(t, uint64(1), result.Candidates[0].Provider.Id)
+	assert.Equal(t, uint64(2), result.Candidates[1].Provider.Id)
+
+	all := Rank(testProviders(), nil, Requirements{MaxEnvironments: -1, IncludeUnavailable: true}, 0)
+	last := all.Candidates[len(all.Candidates)-1]
+	assert.Equal(t, uint64(5), last.Provider.Id)
+	assert.Equal(t, []string{"reports availability 'maintenance' (-50)"}, last.Penalties)
+}
+
+func TestRank_NoCandidates(t *testing.T) {
+	result := Rank(nil, nil, Requirements{MaxEnvironments: -1}, 5)
+
+	assert.NotNil(t, result.Candidates)
+	assert.Empty(t, result.Candidates)
+}
+
+func TestParseAnnotations(t *testing.T) {
+	annotations, err := ParseAnnotations(&overlockv1beta1.Metadata{Annotations: `{"region":"us-east-1","replicas":3}`})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"region": "us-east-1", "replicas": "3"}, annotations)
+
+	annotations, err = ParseAnnotations(nil)
+	require.NoError(t, err)
+	assert.Empty(t, annotations)
+
+	_, err = ParseAnnotations(&overlockv1beta1.Metadata{Annotations: "region=us-east-1"})
+	assert.Error(t, err)
+}
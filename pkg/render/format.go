@@ -0,0 +1,244 @@
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Output formats
+const (
+	FormatJSON        = "json"         // indented JSON
+	FormatCompactJSON = "compact_json" // JSON without whitespace
+	FormatMarkdown    = "markdown"     // tables for lists, key/value tables for single records
+	FormatCSV         = "csv"          // the list of a list response, one row per item
+)
+
+// ListFormats are the formats supported by list responses
+var ListFormats = []string{FormatJSON, FormatCompactJSON, FormatMarkdown, FormatCSV}
+
+// RecordFormats are the formats supported by single-record responses
+var RecordFormats = []string{FormatJSON, FormatCompactJSON, FormatMarkdown}
+
+// ErrNoList is returned when CSV is requested for a response without a list of records
+var ErrNoList = errors.New("csv output needs a list of records")
+
+// Format renders a JSON tree, as returned by Value, in the given format
+func Format(tree interface{}, format string) (string, error) {
+	switch format {
+	case FormatJSON, "":
+		encoded, err := json.MarshalIndent(tree, "", "  ")
+		return string(encoded), err
+	case FormatCompactJSON:
+		encoded, err := json.Marshal(tree)
+		return string(encoded), err
+	case FormatMarkdown:
+		return markdown(tree)
+	case FormatCSV:
+		return csvTable(tree)
+	default:
+		return "", fmt.Errorf("unsupported format '%s'", format)
+	}
+}
+
+// markdown renders top-level scalars as a key/value table followed by one
+// section per nested record or list
+func markdown(tree interface{}) (string, error) {
+	var b strings.Builder
+	switch tree := tree.(type) {
+	case Object:
+		var scalars Object
+		var sections []Member
+		for _, member := range tree {
+			switch member.Value.(type) {
+			case Object, []interface{}:
+				sections = append(sections, member)
+			default:
+				scalars = append(scalars, member)
+			}
+		}
+		if len(scalars) > 0 {
+			writeKeyValues(&b, scalars)
+		}
+		for _, section := range sections {
+			if b.Len() > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "### %s\n\n", section.Key)
+			switch value := section.Value.(type) {
+			case Object:
+				writeKeyValues(&b, flatten(value))
+			case []interface{}:
+				writeList(&b, value)
+			}
+		}
+	case []interface{}:
+		writeList(&b, tree)
+	default:
+		b.WriteString(cell(tree))
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+// writeKeyValues writes a two-column field/value table
+func writeKeyValues(b *strings.Builder, object Object) {
+	b.WriteString("| field | value |\n|---|---|\n")
+	for _, member := range object {
+		fmt.Fprintf(b, "| %s | %s |\n", markdownCell(member.Key), markdownCell(cell(member.Value)))
+	}
+}
+
+// writeList writes a list of records as a table, or any other list as bullets
+func writeList(b *strings.Builder, list []interface{}) {
+	if len(list) == 0 {
+		b.WriteString("_none_\n")
+		return
+	}
+	columns, rows, ok := table(list)
+	if !ok {
+		for _, item := range list {
+			fmt.Fprintf(b, "- %s\n", markdownCell(cell(item)))
+		}
+		return
+	}
+	escaped := make([]string, len(columns))
+	for i, column := range columns {
+		escaped[i] = markdownCell(column)
+	}
+	fmt.Fprintf(b, "| %s |\n|%s\n", strings.Join(escaped, " | "), strings.Repeat("---|", len(columns)))
+	for _, row := range rows {
+		for i := range row {
+			row[i] = markdownCell(row[i])
+		}
+		fmt.Fprintf(b, "| %s |\n", strings.Join(row, " | "))
+	}
+}
+
+// csvTable renders the first list of records in the tree as CSV
+func csvTable(tree interface{}) (string, error) {
+	list, ok := tree.([]interface{})
+	if object, isObject := tree.(Object); isObject {
+		for _, member := range object {
+			if list, ok = member.Value.([]interface{}); ok {
+				break
+			}
+		}
+	}
+	if !ok {
+		return "", ErrNoList
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if len(list) > 0 {
+		columns, rows, isTable := table(list)
+		if !isTable {
+			return "", ErrNoList
+		}
+		if err := writer.Write(columns); err != nil {
+			return "", err
+		}
+		if err := writer.WriteAll(rows); err != nil {
+			return "", err
+		}
+	}
+	writer.Flush()
+	return buf.String(), writer.Error()
+}
+
+// table flattens a list of records into columns, in the order they first appear, and rows.
+// A record without a nested record other records have (such as a provider without
+// metadata) leaves the nested columns empty rather than adding a column of its own.
+func table(list []interface{}) ([]string, [][]string, bool) {
+	records := make([]Object, len(list))
+	nested := make(map[string]bool)
+	for i, item := range list {
+		object, ok := item.(Object)
+		if !ok {
+			return nil, nil, false
+		}
+		records[i] = flatten(object)
+		for _, member := range records[i] {
+			for key := member.Key; strings.Contains(key, "."); {
+				key = key[:strings.LastIndex(key, ".")]
+				nested[key] = true
+			}
+		}
+	}
+
+	var columns []string
+	index := make(map[string]int)
+	for _, record := range records {
+		for _, member := range record {
+			if _, seen := index[member.Key]; seen || (member.Value == nil && nested[member.Key]) {
+				continue
+			}
+			index[member.Key] = len(columns)
+			columns = append(columns, member.Key)
+		}
+	}
+
+	rows := make([][]string, len(records))
+	for i, record := range records {
+		row := make([]string, len(columns))
+		for _, member := range record {
+			if column, ok := index[member.Key]; ok {
+				row[column] = cell(member.Value)
+			}
+		}
+		rows[i] = row
+	}
+	return columns, rows, true
+}
+
+// flatten turns nested records into dotted keys such as metadata.name
+func flatten(object Object) Object {
+	var flat Object
+	for _, member := range object {
+		if nested, ok := member.Value.(Object); ok && len(nested) > 0 {
+			for _, inner := range flatten(nested) {
+				flat = append(flat, Member{Key: member.Key + "." + inner.Key, Value: inner.Value})
+			}
+			continue
+		}
+		flat = append(flat, member)
+	}
+	return flat
+}
+
+// cell renders a scalar as text and anything else as compact JSON
+func cell(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case bool:
+		return strconv.FormatBool(value)
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case uint64:
+		return strconv.FormatUint(value, 10)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case json.Number:
+		return value.String()
+	default:
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		return string(encoded)
+	}
+}
+
+// markdownCell escapes text for a markdown table cell
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.Join(strings.Fields(strings.ReplaceAll(text, "\n", " ")), " ")
+}
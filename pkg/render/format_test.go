@@ -0,0 +1,96 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func listTree(t *testing.T) interface{} {
+	tree, err := Value(&overlockv1beta1.QueryListProviderResponse{
+		Providers: []overlockv1beta1.Provider{
+			{Id: 1, Creator: "overlock1abc", Metadata: &overlockv1beta1.Metadata{Name: "alpha | one"}, Port: 8080},
+			{Id: 2, Creator: "overlock1def"},
+		},
+		Pagination: &query.PageResponse{Total: 2},
+	})
+	require.NoError(t, err)
+	return tree.(Object).Set("network", "default")
+}
+
+func TestFormat_CompactJSON(t *testing.T) {
+	text, err := Format(Object{{Key: "id", Value: uint64(1)}, {Key: "name", Value: "alpha"}}, FormatCompactJSON)
+
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":1,"name":"alpha"}`, text)
+}
+
+func TestFormat_MarkdownList(t *testing.T) {
+	text, err := Format(listTree(t), FormatMarkdown)
+
+	require.NoError(t, err)
+	assert.Contains(t, text, "| field | value |\n|---|---|\n| network | default |\n")
+	assert.Contains(t, text, "### providers\n\n| metadata.name | metadata.annotations | id | creator |")
+	// Cells are escaped so they cannot break the table
+	assert.Contains(t, text, "| alpha \\| one |  | 1 | overlock1abc |")
+	assert.Contains(t, text, "### pagination\n\n| field | value |\n|---|---|\n| next_key |  |\n| total | 2 |\n")
+}
+
+func TestFormat_MarkdownRecord(t *testing.T) {
+	tree, err := Value(&overlockv1beta1.QueryShowProviderResponse{
+		Provider: &overlockv1beta1.Provider{Id: 7, Metadata: &overlockv1beta1.Metadata{Name: "alpha"}},
+	})
+	require.NoError(t, err)
+
+	text, err := Format(tree, FormatMarkdown)
+
+	require.NoError(t, err)
+	assert.Contains(t, text, "### provider\n\n| field | value |\n|---|---|\n| metadata.name | alpha |\n")
+	assert.Contains(t, text, "| id | 7 |\n")
+}
+
+func TestFormat_MarkdownEmptyList(t *testing.T) {
+	text, err := Format(Object{{Key: "providers", Value: []interface{}{}}}, FormatMarkdown)
+
+	require.NoError(t, err)
+	assert.Equal(t, "### providers\n\n_none_\n", text)
+}
+
+func TestFormat_CSV(t *testing.T) {
+	text, err := Format(listTree(t), FormatCSV)
+
+	require.NoError(t, err)
+	lines := splitLines(text)
+	require.Len(t, lines, 3)
+	assert.Equal(t, "metadata.name,metadata.annotations,id,creator,ip,port,country_code,environment_type,availability,register_time", lines[0])
+	assert.Equal(t, "alpha | one,,1,overlock1abc,,8080,,,,", lines[1])
+	// The second provider has no metadata record, its columns stay empty
+	assert.Equal(t, ",,2,overlock1def,,0,,,,", lines[2])
+}
+
+func TestFormat_CSVNeedsList(t *testing.T) {
+	_, err := Format(Object{{Key: "id", Value: uint64(1)}}, FormatCSV)
+
+	assert.ErrorIs(t, err, ErrNoList)
+}
+
+func TestFormat_Unsupported(t *testing.T) {
+	_, err := Format(Object{}, "yaml")
+
+	assert.ErrorContains(t, err, "unsupported format 'yaml'")
+}
+
+func splitLines(text string) []string {
+	var lines []string
+	start := 0
+	for i, r := range text {
+		if r == '\n' {
+			lines = append(lines, text[start:i])
+			start = i + 1
+		}
+	}
+	return lines
+}
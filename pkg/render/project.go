@@ -0,0 +1,81 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseFields splits a comma separated list of dotted field paths such as
+// "id,metadata.name" and checks each against the paths a record can have
+func ParseFields(value string, known []string) ([]string, error) {
+	allowed := make(map[string]bool, len(known))
+	for _, path := range known {
+		allowed[path] = true
+	}
+	var fields []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || seen[field] {
+			continue
+		}
+		if !allowed[field] {
+			return nil, fmt.Errorf("unknown field '%s' (available: %s)", field, strings.Join(known, ", "))
+		}
+		seen[field] = true
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// Paths returns every dotted path of a record, parents before their children
+func Paths(record Object) []string {
+	var paths []string
+	for _, member := range record {
+		paths = append(paths, member.Key)
+		if nested, ok := member.Value.(Object); ok {
+			for _, path := range Paths(nested) {
+				paths = append(paths, member.Key+"."+path)
+			}
+		}
+	}
+	return paths
+}
+
+// Project keeps only the given dotted paths of a record, in the order requested.
+// A path under an unset parent renders as null so every record has the same shape.
+func Project(record Object, fields []string) Object {
+	projected := Object{}
+	for _, field := range fields {
+		projected = setPath(projected, strings.Split(field, "."), lookup(record, field))
+	}
+	return projected
+}
+
+// lookup returns the value at a dotted path, or nil when the path is not set
+func lookup(record Object, path string) interface{} {
+	var current interface{} = record
+	for _, key := range strings.Split(path, ".") {
+		object, ok := current.(Object)
+		if !ok {
+			return nil
+		}
+		if current, ok = object.Get(key); !ok {
+			return nil
+		}
+	}
+	return current
+}
+
+// setPath stores value at the path, creating the intermediate objects
+func setPath(object Object, path []string, value interface{}) Object {
+	if len(path) == 1 {
+		return object.Set(path[0], value)
+	}
+	nested, _ := object.Get(path[0])
+	child, ok := nested.(Object)
+	if !ok {
+		child = Object{}
+	}
+	return object.Set(path[0], setPath(child, path[1:], value))
+}
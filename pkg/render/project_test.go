@@ -0,0 +1,62 @@
+package render
+
+import (
+	"testing"
+
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func providerRecord(t *testing.T, provider overlockv1beta1.Provider) Object {
+	tree, err := Value(provider)
+	require.NoError(t, err)
+	return tree.(Object)
+}
+
+func TestPaths(t *testing.T) {
+	paths := Paths(providerRecord(t, overlockv1beta1.Provider{Metadata: &overlockv1beta1.Metadata{}}))
+
+	assert.Equal(t, []string{"metadata", "metadata.name", "metadata.annotations", "id", "creator"}, paths[:5])
+	assert.Contains(t, paths, "country_code")
+}
+
+func TestParseFields(t *testing.T) {
+	known := []string{"id", "metadata", "metadata.name", "country_code"}
+
+	fields, err := ParseFields(" id, metadata.name ,,id", known)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "metadata.name"}, fields)
+
+	fields, err = ParseFields("", known)
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+
+	_, err = ParseFields("id,name", known)
+	assert.ErrorContains(t, err, "unknown field 'name'")
+}
+
+func TestProject(t *testing.T) {
+	record := providerRecord(t, overlockv1beta1.Provider{
+		Id:          1,
+		CountryCode: "DE",
+		Metadata:    &overlockv1beta1.Metadata{Name: "alpha", Annotations: "{}"},
+	})
+
+	projected := Project(record, []string{"country_code", "metadata.name", "id"})
+
+	assert.Equal(t, Object{
+		{Key: "country_code", Value: "DE"},
+		{Key: "metadata", Value: Object{{Key: "name", Value: "alpha"}}},
+		{Key: "id", Value: uint64(1)},
+	}, projected)
+}
+
+func TestProject_UnsetParent(t *testing.T) {
+	projected := Project(providerRecord(t, overlockv1beta1.Provider{Id: 2}), []string{"id", "metadata.name"})
+
+	assert.Equal(t, Object{
+		{Key: "id", Value: uint64(2)},
+		{Key: "metadata", Value: Object{{Key: "name", Value: nil}}},
+	}, projected)
+}
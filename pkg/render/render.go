@@ -0,0 +1,394 @@
+// Package render converts tool responses, including the gogo protobuf structs
+// returned by the chain, into a uniform JSON tree.
+//
+// Fields of protobuf messages are named after their snake_case proto field
+// name and are always present: unset scalars render as their zero value, unset
+// messages as null and unset repeated fields as []. Timestamps render as UTC
+// RFC3339 strings and bytes (such as pagination next_key cursors) as hex.
+// Other structs follow their json tags.
+package render
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Member is a key and value of an Object
+type Member struct {
+	Key   string
+	Value interface{}
+}
+
+// Object is a JSON object that keeps its keys in insertion order
+type Object []Member
+
+// Get returns the value stored under key
+func (o Object) Get(key string) (interface{}, bool) {
+	for _, member := range o {
+		if member.Key == key {
+			return member.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Set replaces the value stored under key, or appends it when the key is new
+func (o Object) Set(key string, value interface{}) Object {
+	for i := range o {
+		if o[i].Key == key {
+			o[i].Value = value
+			return o
+		}
+	}
+	return append(o, Member{Key: key, Value: value})
+}
+
+// MarshalJSON encodes the object with its keys in order
+func (o Object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, member := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(member.Key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		value, err := json.Marshal(member.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// JSON renders a value as indented JSON
+func JSON(v interface{}) ([]byte, error) {
+	tree, err := Value(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(tree, "", "  ")
+}
+
+// Value converts a value into a JSON tree made of nil, bool, numbers, string,
+// []interface{} and Object
+func Value(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return convert(reflect.ValueOf(v), false)
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	objectType    = reflect.TypeOf(Object{})
+	numberType    = reflect.TypeOf(json.Number(""))
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// convert converts a single value; proto marks values held by a protobuf message
+// field, whose nil repeated fields render as [] instead of null
+func convert(v reflect.Value, proto bool) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	switch v.Type() {
+	case timeType:
+		return formatTime(v.Interface().(time.Time)), nil
+	case objectType, numberType:
+		// Already part of a JSON tree
+		return v.Interface(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return convert(v.Elem(), proto)
+	}
+
+	if v.Type().Implements(marshalerType) && !isMessage(v.Type()) {
+		return viaJSON(v.Interface())
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return convertStruct(v)
+	case reflect.Map:
+		return convertMap(v)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return hex.EncodeToString(v.Bytes()), nil
+		}
+		if v.IsNil() {
+			if proto {
+				return []interface{}{}, nil
+			}
+			return nil, nil
+		}
+		return convertList(v)
+	case reflect.Array:
+		return convertList(v)
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	default:
+		return nil, fmt.Errorf("render: unsupported type %s", v.Type())
+	}
+}
+
+// convertStruct converts a struct into an Object
+func convertStruct(v reflect.Value) (interface{}, error) {
+	object := Object{}
+	if err := appendFields(&object, v, make(map[string]bool)); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// appendFields adds the fields of v to object. Fields declared directly on a struct
+// win over fields of the same name promoted from an embedded struct.
+func appendFields(object *Object, v reflect.Value, taken map[string]bool) error {
+	t := v.Type()
+	var promoted []reflect.Value
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if strings.HasPrefix(field.Name, "XXX_") {
+			continue
+		}
+		value := v.Field(i)
+
+		if protoTag := field.Tag.Get("protobuf"); protoTag != "" && field.IsExported() {
+			name := SnakeCase(protoName(protoTag, field.Name))
+			if taken[name] {
+				continue
+			}
+			taken[name] = true
+			converted, err := convert(value, true)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			*object = object.Set(name, converted)
+			continue
+		}
+		if oneofTag := field.Tag.Get("protobuf_oneof"); oneofTag != "" {
+			// A oneof holds a pointer to a wrapper struct with the single field that is set
+			if value.IsNil() {
+				continue
+			}
+			if err := appendFields(object, value.Elem().Elem(), taken); err != nil {
+				return err
+			}
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			inner := value
+			if inner.Kind() == reflect.Pointer {
+				if inner.IsNil() {
+					continue
+				}
+				inner = inner.Elem()
+			}
+			if inner.Kind() == reflect.Struct {
+				promoted = append(promoted, inner)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if taken[name] {
+			continue
+		}
+		taken[name] = true
+		if hasOption(options, "omitempty") && isEmpty(value) {
+			continue
+		}
+		if hasOption(options, "omitzero") && isZero(value) {
+			continue
+		}
+		converted, err := convert(value, false)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if hasOption(options, "string") {
+			switch converted.(type) {
+			case int64, uint64, float64, bool:
+				converted = fmt.Sprint(converted)
+			}
+		}
+		*object = object.Set(name, converted)
+	}
+
+	for _, inner := range promoted {
+		if err := appendFields(object, inner, taken); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// convertMap converts a map into an Object with sorted keys
+func convertMap(v reflect.Value) (interface{}, error) {
+	if v.IsNil() {
+		return nil, nil
+	}
+	keys := make([]string, 0, v.Len())
+	values := make(map[string]reflect.Value, v.Len())
+	for _, key := range v.MapKeys() {
+		name := fmt.Sprint(key.Interface())
+		keys = append(keys, name)
+		values[name] = v.MapIndex(key)
+	}
+	sort.Strings(keys)
+
+	object := make(Object, 0, len(keys))
+	for _, key := range keys {
+		converted, err := convert(values[key], false)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		object = append(object, Member{Key: key, Value: converted})
+	}
+	return object, nil
+}
+
+// convertList converts a slice or array
+func convertList(v reflect.Value) (interface{}, error) {
+	list := make([]interface{}, v.Len())
+	for i := range list {
+		converted, err := convert(v.Index(i), false)
+		if err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+		list[i] = converted
+	}
+	return list, nil
+}
+
+// viaJSON converts a value with its own JSON encoding
+func viaJSON(v interface{}) (interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var tree interface{}
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// formatTime renders a timestamp as UTC RFC3339, with fractional seconds only when set
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// isMessage reports whether t is a protobuf message struct (or a pointer to one)
+func isMessage(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("protobuf") != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// protoName returns the name=... entry of a protobuf struct tag
+func protoName(tag, fallback string) string {
+	for _, part := range strings.Split(tag, ",") {
+		if name, ok := strings.CutPrefix(part, "name="); ok {
+			return name
+		}
+	}
+	return fallback
+}
+
+// SnakeCase converts a proto field name such as "Providers" or "nextKey" to snake_case
+func SnakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && runes[i-1] != '_')) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// hasOption reports whether a json tag carries the option
+func hasOption(options, option string) bool {
+	for _, o := range strings.Split(options, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmpty mirrors encoding/json's omitempty rule
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// isZero mirrors encoding/json's omitzero rule
+func isZero(v reflect.Value) bool {
+	if zeroer, ok := v.Interface().(interface{ IsZero() bool }); ok {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return true
+		}
+		return zeroer.IsZero()
+	}
+	return v.IsZero()
+}
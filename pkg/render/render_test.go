@@ -0,0 +1,151 @@
+package render
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decode(t *testing.T, v interface{}) map[string]interface{} {
+	raw, err := JSON(v)
+	require.NoError(t, err)
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+	return decoded
+}
+
+func TestJSON_ProtoMessage(t *testing.T) {
+	registered := time.Date(2024, 1, 15, 11, 30, 0, 0, time.FixedZone("CET", 3600))
+	response := &overlockv1beta1.QueryListProviderResponse{
+		Providers: []overlockv1beta1.Provider{
+			{Id: 1, Creator: "overlock1abc", RegisterTime: &registered},
+			{Id: 2},
+		},
+		Pagination: &query.PageResponse{NextKey: []byte{0x00, 0x02}, Total: 2},
+	}
+
+	decoded := decode(t, response)
+
+	// The proto field is named "Providers"; it renders in snake_case
+	assert.NotContains(t, decoded, "Providers")
+	providers, ok := decoded["providers"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, providers, 2)
+
+	first := providers[0].(map[string]interface{})
+	assert.Equal(t, 1.0, first["id"])
+	assert.Equal(t, "2024-01-15T10:30:00Z", first["register_time"])
+
+	// Unset fields are explicit
+	second := providers[1].(map[string]interface{})
+	assert.Equal(t, "", second["creator"])
+	assert.Equal(t, 0.0, second["port"])
+	assert.Contains(t, second, "metadata")
+	assert.Nil(t, second["metadata"])
+	assert.Nil(t, second["register_time"])
+
+	pagination := decoded["pagination"].(map[string]interface{})
+	assert.Equal(t, "0002", pagination["next_key"])
+	assert.Equal(t, 2.0, pagination["total"])
+}
+
+func TestJSON_EmptyRepeatedField(t *testing.T) {
+	decoded := decode(t, &overlockv1beta1.QueryListEnvironmentResponse{})
+
+	assert.Equal(t, []interface{}{}, decoded["environments"])
+	assert.Nil(t, decoded["pagination"])
+}
+
+func TestJSON_TaggedStruct(t *testing.T) {
+	type Inner struct {
+		Shared string `json:"shared"`
+		Nested int    `json:"nested"`
+	}
+	type Response struct {
+		Inner
+		Shared  string            `json:"shared"`
+		Name    string            `json:"name,omitempty"`
+		At      time.Time         `json:"at,omitzero"`
+		Labels  map[string]string `json:"labels"`
+		Hidden  string            `json:"-"`
+		Count   int64             `json:"count,string"`
+		private string
+	}
+
+	raw, err := JSON(Response{
+		Inner:   Inner{Shared: "inner", Nested: 3},
+		Shared:  "outer",
+		Labels:  map[string]string{"b": "2", "a": "1"},
+		Hidden:  "secret",
+		Count:   42,
+		private: "x",
+	})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"shared":"outer","labels":{"a":"1","b":"2"},"count":"42","nested":3}`, string(raw))
+	// Struct fields keep their declaration order and map keys are sorted
+	assert.Equal(t, `{
+  "shared": "outer",
+  "labels": {
+    "a": "1",
+    "b": "2"
+  },
+  "count": "42",
+  "nested": 3
+}`, string(raw))
+}
+
+func TestJSON_Time(t *testing.T) {
+	type Response struct {
+		At time.Time `json:"at"`
+	}
+	at := time.Date(2025, 6, 1, 14, 0, 0, 500, time.FixedZone("CEST", 7200))
+
+	decoded := decode(t, Response{At: at})
+
+	assert.Equal(t, "2025-06-01T12:00:00.0000005Z", decoded["at"])
+}
+
+func TestJSON_Marshaler(t *testing.T) {
+	type Response struct {
+		Raw json.RawMessage `json:"raw"`
+	}
+
+	raw, err := JSON(Response{Raw: json.RawMessage(`{"z":1,"a":[true]}`)})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"raw":{"a":[true],"z":1}}`, string(raw))
+}
+
+func TestObject_Set(t *testing.T) {
+	object := Object{{Key: "a", Value: 1}}
+	object = object.Set("b", 2)
+	object = object.Set("a", 3)
+
+	value, ok := object.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 3, value)
+
+	raw, err := json.Marshal(object)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":3,"b":2}`, string(raw))
+}
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"Providers":    "providers",
+		"Provider":     "provider",
+		"nextKey":      "next_key",
+		"country_code": "country_code",
+		"IPAddress":    "ip_address",
+		"id":           "id",
+	}
+	for input, expected := range tests {
+		assert.Equal(t, expected, SnakeCase(input), input)
+	}
+}
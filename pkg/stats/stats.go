@@ -0,0 +1,261 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+)
+
+// Provider fields providers can be grouped by
+const (
+	ProviderCountry         = "country_code"
+	ProviderEnvironmentType = "environment_type"
+	ProviderAvailability    = "availability"
+	ProviderCreator         = "creator"
+)
+
+// Environment fields environments can be grouped by
+const (
+	EnvironmentProvider = "provider"
+	EnvironmentCreator  = "creator"
+)
+
+// Histogram bucket sizes
+const (
+	BucketDay   = "day"
+	BucketWeek  = "week"
+	BucketMonth = "month"
+	BucketYear  = "year"
+)
+
+// Unspecified is the group of records that leave the grouped field empty
+const Unspecified = "unspecified"
+
+// maxFilledBuckets bounds the number of empty buckets added to close gaps in a histogram
+const maxFilledBuckets = 5000
+
+// ProviderGroupings lists every provider field that can be grouped by
+var ProviderGroupings = []string{ProviderCountry, ProviderEnvironmentType, ProviderAvailability, ProviderCreator}
+
+// EnvironmentGroupings lists every environment field that can be grouped by
+var EnvironmentGroupings = []string{EnvironmentProvider, EnvironmentCreator}
+
+// Buckets lists every histogram bucket size
+var Buckets = []string{BucketDay, BucketWeek, BucketMonth, BucketYear}
+
+// Options selects what Compute aggregates
+type Options struct {
+	ProviderGroupBy    []string
+	EnvironmentGroupBy []string
+	Bucket             string
+	Top                int // values kept per group, the rest are summed into Other; 0 keeps all
+}
+
+// Count is the number of records sharing a value
+type Count struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Group is the distribution of records over one field, most frequent values first
+type Group struct {
+	Distinct int     `json:"distinct"`
+	Values   []Count `json:"values"`
+	Other    int     `json:"other,omitempty"` // records whose value was cut by Top
+}
+
+// Totals aggregates one record type
+type Totals struct {
+	Total  int              `json:"total"`
+	Groups map[string]Group `json:"groups,omitempty"`
+}
+
+// Bucket is a histogram bucket starting at Start
+type Bucket struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+}
+
+// Histogram counts provider registrations over time
+type Histogram struct {
+	Bucket  string   `json:"bucket"`
+	Buckets []Bucket `json:"buckets"`
+	Unknown int      `json:"unknown,omitempty"` // providers without a register time
+}
+
+// Stats is the aggregate view of a network's providers and environments
+type Stats struct {
+	Providers     Totals    `json:"providers"`
+	Environments  Totals    `json:"environments"`
+	Registrations Histogram `json:"registrations"`
+}
+
+// Compute aggregates providers and environments
+func Compute(providers []overlockv1beta1.Provider, environments []overlockv1beta1.Environment, opts Options) (Stats, error) {
+	bucketStart, next, err := bucketFuncs(opts.Bucket)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	stats := Stats{
+		Providers:     Totals{Total: len(providers), Groups: make(map[string]Group)},
+		Environments:  Totals{Total: len(environments), Groups: make(map[string]Group)},
+		Registrations: Histogram{Bucket: opts.Bucket, Buckets: []Bucket{}},
+	}
+
+	for _, field := range opts.ProviderGroupBy {
+		value, err := providerField(field)
+		if err != nil {
+			return Stats{}, err
+		}
+		counts := make(map[string]int)
+		for _, provider := range providers {
+			counts[orUnspecified(value(provider))]++
+		}
+		stats.Providers.Groups[field] = group(counts, opts.Top)
+	}
+
+	for _, field := range opts.EnvironmentGroupBy {
+		value, err := environmentField(field)
+		if err != nil {
+			return Stats{}, err
+		}
+		counts := make(map[string]int)
+		for _, environment := range environments {
+			counts[orUnspecified(value(environment))]++
+		}
+		stats.Environments.Groups[field] = group(counts, opts.Top)
+	}
+
+	counts := make(map[time.Time]int)
+	for _, provider := range providers {
+		if provider.RegisterTime == nil || provider.RegisterTime.IsZero() {
+			stats.Registrations.Unknown++
+			continue
+		}
+		counts[bucketStart(provider.RegisterTime.UTC())]++
+	}
+	stats.Registrations.Buckets = histogram(counts, next)
+	return stats, nil
+}
+
+// providerField returns the accessor of a provider grouping
+func providerField(field string) (func(overlockv1beta1.Provider) string, error) {
+	switch field {
+	case ProviderCountry:
+		return func(p overlockv1beta1.Provider) string { return p.CountryCode }, nil
+	case ProviderEnvironmentType:
+		return func(p overlockv1beta1.Provider) string { return p.EnvironmentType }, nil
+	case ProviderAvailability:
+		return func(p overlockv1beta1.Provider) string { return p.Availability }, nil
+	case ProviderCreator:
+		return func(p overlockv1beta1.Provider) string { return p.Creator }, nil
+	}
+	return nil, fmt.Errorf("unknown provider grouping '%s'", field)
+}
+
+// environmentField returns the accessor of an environment grouping
+func environmentField(field string) (func(overlockv1beta1.Environment) string, error) {
+	switch field {
+	case EnvironmentProvider:
+		return func(e overlockv1beta1.Environment) string {
+			if e.Provider == 0 {
+				return ""
+			}
+			return strconv.FormatUint(e.Provider, 10)
+		}, nil
+	case EnvironmentCreator:
+		return func(e overlockv1beta1.Environment) string { return e.Creator }, nil
+	}
+	return nil, fmt.Errorf("unknown environment grouping '%s'", field)
+}
+
+// group orders counts by frequency (then value) and keeps the top values
+func group(counts map[string]int, top int) Group {
+	values := make([]Count, 0, len(counts))
+	for value, count := range counts {
+		values = append(values, Count{Value: value, Count: count})
+	}
+	sort.Slice(values, func(a, b int) bool {
+		if values[a].Count != values[b].Count {
+			return values[a].Count > values[b].Count
+		}
+		return values[a].Value < values[b].Value
+	})
+
+	g := Group{Distinct: len(values), Values: values}
+	if top > 0 && len(values) > top {
+		for _, cut := range values[top:] {
+			g.Other += cut.Count
+		}
+		g.Values = values[:top]
+	}
+	return g
+}
+
+// histogram orders the buckets by time and fills the gaps between them with empty buckets
+func histogram(counts map[time.Time]int, next func(time.Time) time.Time) []Bucket {
+	starts := make([]time.Time, 0, len(counts))
+	for start := range counts {
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(a, b int) bool { return starts[a].Before(starts[b]) })
+
+	buckets := make([]Bucket, 0, len(starts))
+	for i, start := range starts {
+		buckets = append(buckets, Bucket{Start: start, Count: counts[start]})
+		if i+1 == len(starts) {
+			break
+		}
+		filled := 0
+		for gap := next(start); gap.Before(starts[i+1]) && filled < maxFilledBuckets; gap = next(gap) {
+			buckets = append(buckets, Bucket{Start: gap})
+			filled++
+		}
+	}
+	return buckets
+}
+
+// bucketFuncs returns the functions truncating a time to its bucket and advancing to the next bucket
+func bucketFuncs(bucket string) (func(time.Time) time.Time, func(time.Time) time.Time, error) {
+	switch bucket {
+	case BucketDay:
+		return func(t time.Time) time.Time {
+				return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+			}, func(t time.Time) time.Time {
+				return t.AddDate(0, 0, 1)
+			}, nil
+	case BucketWeek:
+		return func(t time.Time) time.Time {
+				// Weeks start on Monday
+				day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+				return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+			}, func(t time.Time) time.Time {
+				return t.AddDate(0, 0, 7)
+			}, nil
+	case BucketMonth:
+		return func(t time.Time) time.Time {
+				return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+			}, func(t time.Time) time.Time {
+				return t.AddDate(0, 1, 0)
+			}, nil
+	case BucketYear:
+		return func(t time.Time) time.Time {
+				return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+			}, func(t time.Time) time.Time {
+				return t.AddDate(1, 0, 0)
+			}, nil
+	}
+	return nil, nil, fmt.Errorf("unknown bucket '%s'", bucket)
+}
+
+// orUnspecified returns the group of a field value
+func orUnspecified(value string) string {
+	if value == "" {
+		return Unspecified
+	}
+	return value
+}
@@ -0,0 +1,110 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func registered(year int, month time.Month, day int) *time.Time {
+	t := time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+	return &t
+}
+
+func testProviders() []overlockv1beta1.Provider {
+	return []overlockv1beta1.Provider{
+		{Id: 1, Creator: "overlock1alice", CountryCode: "DE", Availability: "online", RegisterTime: registered(2025, 1, 15)},
+		{Id: 2, Creator: "overlock1alice", CountryCode: "DE", Availability: "offline", RegisterTime: registered(2025, 1, 20)},
+		{Id: 3, Creator: "overlock1bob", CountryCode: "US", Availability: "online", RegisterTime: registered(2025, 4, 2)},
+		{Id: 4, Creator: "overlock1carol", Availability: "online"},
+	}
+}
+
+func testEnvironments() []overlockv1beta1.Environment {
+	return []overlockv1beta1.Environment{
+		{Id: 1001, Creator: "overlock1dave", Provider: 1},
+		{Id: 1002, Creator: "overlock1dave", Provider: 1},
+		{Id: 1003, Creator: "overlock1erin", Provider: 3},
+	}
+}
+
+func TestCompute(t *testing.T) {
+	stats, err := Compute(testProviders(), testEnvironments(), Options{
+		ProviderGroupBy:    ProviderGroupings,
+		EnvironmentGroupBy: EnvironmentGroupings,
+		Bucket:             BucketMonth,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, stats.Providers.Total)
+	assert.Equal(t, Group{Distinct: 3, Values: []Count{{"DE", 2}, {"US", 1}, {Unspecified, 1}}}, stats.Providers.Groups[ProviderCountry])
+	assert.Equal(t, Group{Distinct: 2, Values: []Count{{"online", 3}, {"offline", 1}}}, stats.Providers.Groups[ProviderAvailability])
+	assert.Equal(t, Group{Distinct: 1, Values: []Count{{Unspecified, 4}}}, stats.Providers.Groups[ProviderEnvironmentType])
+	assert.Equal(t, 3, stats.Providers.Groups[ProviderCreator].Distinct)
+
+	assert.Equal(t, 3, stats.Environments.Total)
+	assert.Equal(t, Group{Distinct: 2, Values: []Count{{"1", 2}, {"3", 1}}}, stats.Environments.Groups[EnvironmentProvider])
+	assert.Equal(t, Group{Distinct: 2, Values: []Count{{"overlock1dave", 2}, {"overlock1erin", 1}}}, stats.Environments.Groups[EnvironmentCreator])
+
+	// Gaps between registrations are filled with empty buckets
+	assert.Equal(t, BucketMonth, stats.Registrations.Bucket)
+	assert.Equal(t, 1, stats.Registrations.Unknown)
+	assert.Equal(t, []Bucket{
+		{Start: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Count: 2},
+		{Start: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), Count: 1},
+	}, stats.Registrations.Buckets)
+}
+
+func TestCompute_SelectedGroupings(t *testing.T) {
+	stats, err := Compute(testProviders(), testEnvironments(), Options{
+		ProviderGroupBy: []string{ProviderAvailability},
+		Bucket:          BucketYear,
+	})
+	require.NoError(t, err)
+
+	assert.Len(t, stats.Providers.Groups, 1)
+	assert.Contains(t, stats.Providers.Groups, ProviderAvailability)
+	assert.Empty(t, stats.Environments.Groups)
+	assert.Equal(t, []Bucket{{Start: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Count: 3}}, stats.Registrations.Buckets)
+}
+
+func TestCompute_Top(t *testing.T) {
+	stats, err := Compute(testProviders(), nil, Options{
+		ProviderGroupBy: []string{ProviderCreator},
+		Bucket:          BucketMonth,
+		Top:             1,
+	})
+	require.NoError(t, err)
+
+	group := stats.Providers.Groups[ProviderCreator]
+	assert.Equal(t, 3, group.Distinct)
+	assert.Equal(t, []Count{{"overlock1alice", 2}}, group.Values)
+	assert.Equal(t, 2, group.Other)
+}
+
+func TestCompute_WeekBuckets(t *testing.T) {
+	// 2025-01-15 is a Wednesday, 2025-01-20 the following Monday
+	stats, err := Compute(testProviders()[:2], nil, Options{Bucket: BucketWeek})
+	require.NoError(t, err)
+
+	assert.Equal(t, []Bucket{
+		{Start: time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC), Count: 1},
+		{Start: time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC), Count: 1},
+	}, stats.Registrations.Buckets)
+}
+
+func TestCompute_InvalidOptions(t *testing.T) {
+	_, err := Compute(nil, nil, Options{Bucket: "decade"})
+	assert.ErrorContains(t, err, "unknown bucket")
+
+	_, err = Compute(nil, nil, Options{Bucket: BucketDay, ProviderGroupBy: []string{"ip"}})
+	assert.ErrorContains(t, err, "unknown provider grouping")
+
+	_, err = Compute(nil, nil, Options{Bucket: BucketDay, EnvironmentGroupBy: []string{"country_code"}})
+	assert.ErrorContains(t, err, "unknown environment grouping")
+}
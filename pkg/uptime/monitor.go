@@ -0,0 +1,221 @@
+package uptime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"overlock-mcp-server/pkg/audit"
+	"overlock-mcp-server/pkg/diagnose"
+	"overlock-mcp-server/pkg/indexer"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/rs/zerolog/log"
+)
+
+// Options configures a Monitor
+type Options struct {
+	Interval    time.Duration // time between probe rounds
+	Timeout     time.Duration // how long a single TCP probe may take
+	Concurrency int           // probes running at once
+	Retention   time.Duration // how long samples are kept, 0 keeps them forever
+
+	// ProbePrivate lets probes connect to loopback, private and link-local endpoints.
+	// Without it such endpoints are recorded as skipped and never dialed.
+	ProbePrivate bool
+}
+
+// ProviderSource returns the providers to probe
+type ProviderSource func(ctx context.Context) ([]overlockv1beta1.Provider, error)
+
+// Monitor periodically TCP-probes the endpoint of every provider of a network and stores the samples
+type Monitor struct {
+	network string
+	source  ProviderSource
+	store   Store
+	probe   diagnose.Prober
+	opts    Options
+	now     func() time.Time
+}
+
+// NewMonitor creates a monitor for the named network
+func NewMonitor(network string, source ProviderSource, store Store, opts Options) *Monitor {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Minute
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 3 * time.Second
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 16
+	}
+	probe := diagnose.PublicTCPProber(opts.Timeout)
+	if opts.ProbePrivate {
+		probe = diagnose.TCPProber(opts.Timeout)
+	}
+	return &Monitor{
+		network: network,
+		source:  source,
+		store:   store,
+		probe:   probe,
+		opts:    opts,
+		now:     time.Now,
+	}
+}
+
+// Run probes immediately and then on every interval until ctx is cancelled
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.ProbeAll(ctx); err != nil && ctx.Err() == nil {
+			log.Warn().Err(err).Str("network", m.network).Msg("Uptime probe round failed")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProbeAll probes every provider with an endpoint once and stores the samples
+func (m *Monitor) ProbeAll(ctx context.Context) error {
+	providers, err := m.source(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list providers: %w", err)
+	}
+
+	var targets []overlockv1beta1.Provider
+	for _, provider := range providers {
+		if provider.Ip != "" && provider.Port != 0 {
+			targets = append(targets, provider)
+		}
+	}
+
+	samples := make([]Sample, len(targets))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(m.opts.Concurrency, len(targets)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				samples[i] = m.probeOne(ctx, targets[i])
+			}
+		}()
+	}
+	for i := range targets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err := m.store.Append(samples); err != nil {
+		return fmt.Errorf("failed to store samples: %w", err)
+	}
+	if m.opts.Retention > 0 {
+		if err := m.store.Prune(m.now().Add(-m.opts.Retention)); err != nil {
+			log.Warn().Err(err).Str("network", m.network).Msg("Failed to prune uptime samples")
+		}
+	}
+
+	up, skipped := 0, 0
+	for _, sample := range samples {
+		switch {
+		case sample.Skipped:
+			skipped++
+		case sample.Up:
+			up++
+		}
+	}
+	log.Debug().
+		Str("network", m.network).
+		Int("probed", len(samples)-skipped).
+		Int("up", up).
+		Int("skipped", skipped).
+		Msg("Uptime probe round completed")
+	return nil
+}
+
+// probeOne probes a single provider, recording non-public endpoints as skipped unless they may be probed
+func (m *Monitor) probeOne(ctx context.Context, provider overlockv1beta1.Provider) Sample {
+	sample := Sample{
+		Network:    m.network,
+		ProviderID: provider.Id,
+		Address:    net.JoinHostPort(provider.Ip, fmt.Sprint(provider.Port)),
+		At:         m.now().UTC(),
+	}
+	if kind := audit.NonPublicKind(provider.Ip); kind != "" && !m.opts.ProbePrivate {
+		sample.Skipped = true
+		sample.Error = fmt.Sprintf("not probed: %s is a %s address", provider.Ip, kind)
+		return sample
+	}
+	latency, err := m.probe(ctx, sample.Address)
+	if errors.Is(err, diagnose.ErrNonPublicAddress) {
+		sample.Skipped = true
+		sample.Error = "not probed: " + err.Error()
+		return sample
+	}
+	if err != nil {
+		sample.Error = err.Error()
+		return sample
+	}
+	sample.Up = true
+	sample.LatencyMs = float64(latency.Microseconds()) / 1000
+	return sample
+}
+
+// IndexSource reads the providers from the local index
+func IndexSource(idx *indexer.Indexer) ProviderSource {
+	return func(ctx context.Context) ([]overlockv1beta1.Provider, error) {
+		snapshot, err := idx.Snapshot()
+		if err != nil {
+			return nil, err
+		}
+		return snapshot.Providers, nil
+	}
+}
+
+// chainSourceMaxPages bounds the pages read per round, so a node repeating its next key cannot
+// keep the walk going forever
+const chainSourceMaxPages = 200
+
+// ChainSource lists the providers from the chain, page by page, up to chainSourceMaxPages pages
+func ChainSource(client overlockv1beta1.QueryClient, timeout time.Duration) ProviderSource {
+	return func(ctx context.Context) ([]overlockv1beta1.Provider, error) {
+		if client == nil {
+			return nil, errors.New("gRPC connection to blockchain is not available")
+		}
+		var providers []overlockv1beta1.Provider
+		var nextKey []byte
+		for page := 0; page < chainSourceMaxPages; page++ {
+			pageCtx, cancel := context.WithTimeout(ctx, timeout)
+			resp, err := client.ListProvider(pageCtx, &overlockv1beta1.QueryListProviderRequest{
+				Pagination: &query.PageRequest{Key: nextKey, Limit: 100},
+			})
+			cancel()
+			if err != nil {
+				return nil, err
+			}
+			providers = append(providers, resp.Providers...)
+			if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 || len(resp.Providers) == 0 {
+				return providers, nil
+			}
+			nextKey = resp.Pagination.NextKey
+		}
+		log.Warn().
+			Int("max_pages", chainSourceMaxPages).
+			Int("providers", len(providers)).
+			Msg("Provider list truncated at the page limit, the remaining providers are not probed")
+		return providers, nil
+	}
+}
@@ -0,0 +1,197 @@
+package uptime
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// defaultMemoryCapacity bounds the number of samples a MemoryStore keeps per provider
+const defaultMemoryCapacity = 10000
+
+// providerKey identifies one provider of one network
+type providerKey struct {
+	network string
+	id      uint64
+}
+
+// MemoryStore keeps the most recent samples of every provider in memory
+type MemoryStore struct {
+	mu       sync.RWMutex
+	samples  map[providerKey][]Sample
+	capacity int
+}
+
+// NewMemoryStore creates an in-memory store holding at most capacity samples per provider (0 uses the default)
+func NewMemoryStore(capacity int) *MemoryStore {
+	if capacity <= 0 {
+		capacity = defaultMemoryCapacity
+	}
+	return &MemoryStore{samples: make(map[providerKey][]Sample), capacity: capacity}
+}
+
+// Append implements Store
+func (s *MemoryStore) Append(samples []Sample) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sample := range samples {
+		key := providerKey{network: sample.Network, id: sample.ProviderID}
+		s.samples[key] = append(s.samples[key], sample)
+		if overflow := len(s.samples[key]) - s.capacity; overflow > 0 {
+			s.samples[key] = append([]Sample(nil), s.samples[key][overflow:]...)
+		}
+	}
+	return nil
+}
+
+// Query implements Store
+func (s *MemoryStore) Query(filter Filter) ([]Sample, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var matched []Sample
+	for _, sample := range s.samples[providerKey{network: filter.Network, id: filter.ProviderID}] {
+		if filter.Matches(sample) {
+			matched = append(matched, sample)
+		}
+	}
+	return matched, nil
+}
+
+// Prune implements Store
+func (s *MemoryStore) Prune(cutoff time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, samples := range s.samples {
+		n := 0
+		for n < len(samples) && samples[n].At.Before(cutoff) {
+			n++
+		}
+		if n == len(samples) {
+			delete(s.samples, key)
+			continue
+		}
+		s.samples[key] = append([]Sample(nil), samples[n:]...)
+	}
+	return nil
+}
+
+// Close implements Store
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+var samplesBucket = []byte("samples")
+
+// BoltStore persists samples in a bbolt database, keyed by network, provider and time
+// so the samples of one provider in a time range are a single cursor scan
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// OpenBoltStore opens (or creates) the bbolt sample store at path
+func OpenBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uptime database '%s': %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(samplesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize uptime database '%s': %w", path, err)
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// Append implements Store
+func (s *BoltStore) Append(samples []Sample) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(samplesBucket)
+		for _, sample := range samples {
+			value, err := json.Marshal(sample)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(sampleKey(sample.Network, sample.ProviderID, sample.At), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Query implements Store
+func (s *BoltStore) Query(filter Filter) ([]Sample, error) {
+	var matched []Sample
+	err := s.db.View(func(tx *bolt.Tx) error {
+		prefix := providerPrefix(filter.Network, filter.ProviderID)
+		start := prefix
+		if !filter.Since.IsZero() {
+			start = sampleKey(filter.Network, filter.ProviderID, filter.Since)
+		}
+		cursor := tx.Bucket(samplesBucket).Cursor()
+		for key, value := cursor.Seek(start); key != nil && bytes.HasPrefix(key, prefix); key, value = cursor.Next() {
+			var sample Sample
+			if err := json.Unmarshal(value, &sample); err != nil {
+				return fmt.Errorf("failed to decode uptime sample: %w", err)
+			}
+			if !filter.Until.IsZero() && sample.At.After(filter.Until) {
+				break
+			}
+			if filter.Matches(sample) {
+				matched = append(matched, sample)
+			}
+		}
+		return nil
+	})
+	return matched, err
+}
+
+// Prune implements Store
+func (s *BoltStore) Prune(cutoff time.Time) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(samplesBucket)
+		// Keys are ordered by provider first, so every key has to be checked
+		var expired [][]byte
+		err := bucket.ForEach(func(key, _ []byte) error {
+			if len(key) >= 8 && int64(binary.BigEndian.Uint64(key[len(key)-8:])) < cutoff.UnixNano() {
+				expired = append(expired, append([]byte(nil), key...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range expired {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Close implements Store
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// providerPrefix is the key prefix shared by every sample of a provider
+func providerPrefix(network string, id uint64) []byte {
+	prefix := make([]byte, 0, len(network)+9)
+	prefix = append(prefix, network...)
+	prefix = append(prefix, 0)
+	return binary.BigEndian.AppendUint64(prefix, id)
+}
+
+// sampleKey orders samples by network, provider and time
+func sampleKey(network string, id uint64, at time.Time) []byte {
+	return binary.BigEndian.AppendUint64(providerPrefix(network, id), uint64(at.UnixNano()))
+}
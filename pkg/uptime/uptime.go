@@ -0,0 +1,165 @@
+package uptime
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Sample is the outcome of one TCP probe of a provider's endpoint
+type Sample struct {
+	Network    string    `json:"network"`
+	ProviderID uint64    `json:"provider_id"`
+	Address    string    `json:"address"`
+	At         time.Time `json:"at"`
+	Up         bool      `json:"up"`
+	Skipped    bool      `json:"skipped,omitempty"`    // the endpoint was not dialed, Error says why
+	LatencyMs  float64   `json:"latency_ms,omitempty"` // connect latency of successful probes
+	Error      string    `json:"error,omitempty"`
+}
+
+// Filter selects the samples of one provider in a time range. Zero bounds are unbounded.
+type Filter struct {
+	Network    string
+	ProviderID uint64
+	Since      time.Time
+	Until      time.Time
+}
+
+// Matches reports whether the sample passes the filter
+func (f Filter) Matches(s Sample) bool {
+	if s.Network != f.Network || s.ProviderID != f.ProviderID {
+		return false
+	}
+	if !f.Since.IsZero() && s.At.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && s.At.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// Store keeps probe samples
+type Store interface {
+	// Append stores samples
+	Append(samples []Sample) error
+	// Query returns the samples matching the filter, oldest first
+	Query(filter Filter) ([]Sample, error)
+	// Prune removes samples taken before cutoff
+	Prune(cutoff time.Time) error
+	// Close releases the store's resources
+	Close() error
+}
+
+// Outage is a run of consecutive failed probes
+type Outage struct {
+	Start     time.Time  `json:"start"`         // first failed probe
+	End       *time.Time `json:"end,omitempty"` // first successful probe afterwards, unset while the outage is ongoing
+	Duration  string     `json:"duration"`      // until End, or until the last failed probe while ongoing
+	Probes    int        `json:"failed_probes"`
+	LastError string     `json:"last_error,omitempty"`
+}
+
+// Report summarizes the samples of one provider
+type Report struct {
+	Network       string     `json:"network"`
+	ProviderID    uint64     `json:"provider_id"`
+	Addresses     []string   `json:"addresses"` // endpoints probed, in the order they were first seen
+	Probes        int        `json:"probes"`
+	Successful    int        `json:"successful"`
+	Skipped       int        `json:"skipped"`               // rounds the endpoint was not dialed, not counted as probes
+	SkipReason    string     `json:"skip_reason,omitempty"` // why the latest skipped round was not dialed
+	UptimePercent *float64   `json:"uptime_percent"`        // unset without samples
+	LatencyP50Ms  *float64   `json:"latency_p50_ms"`        // unset without successful probes
+	LatencyP95Ms  *float64   `json:"latency_p95_ms"`
+	FirstProbe    *time.Time `json:"first_probe,omitempty"`
+	LastProbe     *time.Time `json:"last_probe,omitempty"`
+	Outages       []Outage   `json:"outages"`
+}
+
+// Summarize builds the report of one provider from its samples, which must be sorted oldest first
+func Summarize(network string, providerID uint64, samples []Sample) Report {
+	report := Report{
+		Network:    network,
+		ProviderID: providerID,
+		Addresses:  []string{},
+		Outages:    []Outage{},
+	}
+
+	seen := make(map[string]bool)
+	var latencies []float64
+	var outage *Outage
+	var lastDown time.Time
+	var first, last time.Time
+	closeOutage := func(end *time.Time) {
+		if outage == nil {
+			return
+		}
+		until := lastDown
+		if end != nil {
+			outage.End = end
+			until = *end
+		}
+		outage.Duration = until.Sub(outage.Start).Round(time.Second).String()
+		report.Outages = append(report.Outages, *outage)
+		outage = nil
+	}
+	for _, sample := range samples {
+		if sample.Skipped {
+			report.Skipped++
+			report.SkipReason = sample.Error
+			continue
+		}
+		report.Probes++
+		if first.IsZero() {
+			first = sample.At
+		}
+		last = sample.At
+		if !seen[sample.Address] {
+			seen[sample.Address] = true
+			report.Addresses = append(report.Addresses, sample.Address)
+		}
+		if sample.Up {
+			report.Successful++
+			latencies = append(latencies, sample.LatencyMs)
+			at := sample.At
+			closeOutage(&at)
+			continue
+		}
+		if outage == nil {
+			outage = &Outage{Start: sample.At}
+		}
+		outage.Probes++
+		outage.LastError = sample.Error
+		lastDown = sample.At
+	}
+	closeOutage(nil)
+	if report.Probes == 0 {
+		return report
+	}
+
+	uptime := round(100 * float64(report.Successful) / float64(report.Probes))
+	report.UptimePercent = &uptime
+	report.FirstProbe, report.LastProbe = &first, &last
+	if len(latencies) > 0 {
+		sort.Float64s(latencies)
+		p50, p95 := round(Percentile(latencies, 50)), round(Percentile(latencies, 95))
+		report.LatencyP50Ms, report.LatencyP95Ms = &p50, &p95
+	}
+	return report
+}
+
+// Percentile returns the nearest-rank percentile p of sorted values
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[min(max(rank, 1), len(sorted))-1]
+}
+
+// round rounds to two decimals
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,262 @@
+package uptime
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+	overlockv1beta1 "github.com/overlock-network/api/go/node/overlock/crossplane/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+var base = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func sample(minute int, up bool, latency float64) Sample {
+	s := Sample{Network: "default", ProviderID: 1, Address: "203.0.113.10:8080", At: base.Add(time.Duration(minute) * time.Minute), Up: up}
+	if up {
+		s.LatencyMs = latency
+	} else {
+		s.Error = "connection refused"
+	}
+	return s
+}
+
+func TestSummarize(t *testing.T) {
+	samples := []Sample{
+		sample(0, true, 10),
+		sample(1, false, 0),
+		sample(2, false, 0),
+		sample(3, true, 30),
+		sample(4, true, 20),
+		sample(5, false, 0),
+	}
+
+	report := Summarize("default", 1, samples)
+
+	assert.Equal(t, 6, report.Probes)
+	assert.Equal(t, 3, report.Successful)
+	require.NotNil(t, report.UptimePercent)
+	assert.Equal(t, 50.0, *report.UptimePercent)
+	assert.Equal(t, 20.0, *report.LatencyP50Ms)
+	assert.Equal(t, 30.0, *report.LatencyP95Ms)
+	assert.Equal(t, []string{"203.0.113.10:8080"}, report.Addresses)
+	assert.Equal(t, base, *report.FirstProbe)
+	assert.Equal(t, base.Add(5*time.Minute), *report.LastProbe)
+
+	require.Len(t, report.Outages, 2)
+	assert.Equal(t, base.Add(time.Minute), report.Outages[0].Start)
+	require.NotNil(t, report.Outages[0].End)
+	assert.Equal(t, base.Add(3*time.Minute), *report.Outages[0].End)
+	assert.Equal(t, "2m0s", report.Outages[0].Duration)
+	assert.Equal(t, 2, report.Outages[0].Probes)
+	assert.Equal(t, "connection refused", report.Outages[0].LastError)
+	// The last outage is still ongoing
+	assert.Nil(t, report.Outages[1].End)
+	assert.Equal(t, "0s", report.Outages[1].Duration)
+}
+
+func TestSummarize_NoSamples(t *testing.T) {
+	report := Summarize("default", 1, nil)
+
+	assert.Nil(t, report.UptimePercent)
+	assert.Nil(t, report.LatencyP50Ms)
+	assert.Empty(t, report.Outages)
+	assert.NotNil(t, report.Outages)
+}
+
+func TestSummarize_SkippedSamples(t *testing.T) {
+	skipped := sample(2, false, 0)
+	skipped.Skipped = true
+	skipped.Error = "not probed: 10.0.0.5 is a private address"
+
+	report := Summarize("default", 1, []Sample{sample(0, true, 10), sample(1, false, 0), skipped})
+
+	assert.Equal(t, 2, report.Probes)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, skipped.Error, report.SkipReason)
+	require.NotNil(t, report.UptimePercent)
+	assert.Equal(t, 50.0, *report.UptimePercent)
+	assert.Equal(t, base.Add(time.Minute), *report.LastProbe)
+
+	onlySkipped := Summarize("default", 1, []Sample{skipped})
+	assert.Equal(t, 0, onlySkipped.Probes)
+	assert.Equal(t, 1, onlySkipped.Skipped)
+	assert.Nil(t, onlySkipped.UptimePercent)
+	assert.Nil(t, onlySkipped.FirstProbe)
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	assert.Equal(t, 5.0, Percentile(values, 50))
+	assert.Equal(t, 10.0, Percentile(values, 95))
+	assert.Equal(t, 1.0, Percentile(values, 0))
+	assert.Equal(t, 0.0, Percentile(nil, 50))
+}
+
+func testStore(t *testing.T, store Store) {
+	other := sample(2, true, 5)
+	other.ProviderID = 2
+	require.NoError(t, store.Append([]Sample{sample(0, true, 10), sample(1, false, 0), other, sample(3, true, 12)}))
+
+	all, err := store.Query(Filter{Network: "default", ProviderID: 1})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, base, all[0].At)
+
+	ranged, err := store.Query(Filter{Network: "default", ProviderID: 1, Since: base.Add(time.Minute), Until: base.Add(2 * time.Minute)})
+	require.NoError(t, err)
+	require.Len(t, ranged, 1)
+	assert.False(t, ranged[0].Up)
+
+	require.NoError(t, store.Prune(base.Add(90*time.Second)))
+	remaining, err := store.Query(Filter{Network: "default", ProviderID: 1})
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, base.Add(3*time.Minute), remaining[0].At)
+
+	otherRemaining, err := store.Query(Filter{Network: "default", ProviderID: 2})
+	require.NoError(t, err)
+	assert.Len(t, otherRemaining, 1)
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore(0))
+}
+
+func TestMemoryStore_Capacity(t *testing.T) {
+	store := NewMemoryStore(2)
+	require.NoError(t, store.Append([]Sample{sample(0, true, 1), sample(1, true, 1), sample(2, true, 1)}))
+
+	samples, err := store.Query(Filter{Network: "default", ProviderID: 1})
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+	assert.Equal(t, base.Add(time.Minute), samples[0].At)
+}
+
+func TestBoltStore(t *testing.T) {
+	store, err := OpenBoltStore(filepath.Join(t.TempDir(), "uptime.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	testStore(t, store)
+}
+
+func TestMonitor_ProbeAll(t *testing.T) {
+	providers := []overlockv1beta1.Provider{
+		{Id: 1, Ip: "203.0.113.10", Port: 8080},
+		{Id: 2, Ip: "203.0.113.11", Port: 8080},
+		{Id: 3, Ip: "", Port: 8080}, // no endpoint, not probed
+	}
+	store := NewMemoryStore(0)
+	monitor := NewMonitor("default", func(ctx context.Context) ([]overlockv1beta1.Provider, error) {
+		return providers, nil
+	}, store, Options{Retention: time.Hour})
+	monitor.now = func() time.Time { return base }
+	monitor.probe = func(ctx context.Context, address string) (time.Duration, error) {
+		if address == "203.0.113.11:8080" {
+			return 0, errors.New("i/o timeout")
+		}
+		return 1500 * time.Microsecond, nil
+	}
+
+	require.NoError(t, monitor.ProbeAll(context.Background()))
+
+	up, err := store.Query(Filter{Network: "default", ProviderID: 1})
+	require.NoError(t, err)
+	require.Len(t, up, 1)
+	assert.True(t, up[0].Up)
+	assert.Equal(t, 1.5, up[0].LatencyMs)
+	assert.Equal(t, base, up[0].At)
+
+	down, err := store.Query(Filter{Network: "default", ProviderID: 2})
+	require.NoError(t, err)
+	require.Len(t, down, 1)
+	assert.False(t, down[0].Up)
+	assert.Equal(t, "i/o timeout", down[0].Error)
+
+	skipped, err := store.Query(Filter{Network: "default", ProviderID: 3})
+	require.NoError(t, err)
+	assert.Empty(t, skipped)
+}
+
+func TestMonitor_ProbeAll_NonPublicEndpoints(t *testing.T) {
+	providers := []overlockv1beta1.Provider{
+		{Id: 1, Ip: "127.0.0.1", Port: 8080},
+		{Id: 2, Ip: "10.0.0.5", Port: 8080},
+		{Id: 3, Ip: "169.254.169.254", Port: 80},
+		{Id: 4, Ip: "localhost", Port: 8080}, // resolves to loopback, refused by the prober
+	}
+	store := NewMemoryStore(0)
+	monitor := NewMonitor("default", func(ctx context.Context) ([]overlockv1beta1.Provider, error) {
+		return providers, nil
+	}, store, Options{})
+
+	require.NoError(t, monitor.ProbeAll(context.Background()))
+
+	for _, provider := range providers {
+		samples, err := store.Query(Filter{Network: "default", ProviderID: provider.Id})
+		require.NoError(t, err)
+		require.Len(t, samples, 1)
+		assert.True(t, samples[0].Skipped, provider.Ip)
+		assert.False(t, samples[0].Up, provider.Ip)
+		assert.Contains(t, samples[0].Error, "not probed", provider.Ip)
+	}
+}
+
+func TestMonitor_ProbeAll_ProbePrivate(t *testing.T) {
+	store := NewMemoryStore(0)
+	monitor := NewMonitor("default", func(ctx context.Context) ([]overlockv1beta1.Provider, error) {
+		return []overlockv1beta1.Provider{{Id: 1, Ip: "10.0.0.5", Port: 8080}}, nil
+	}, store, Options{ProbePrivate: true})
+	var probed string
+	monitor.probe = func(ctx context.Context, address string) (time.Duration, error) {
+		probed = address
+		return time.Millisecond, nil
+	}
+
+	require.NoError(t, monitor.ProbeAll(context.Background()))
+
+	assert.Equal(t, "10.0.0.5:8080", probed)
+	samples, err := store.Query(Filter{Network: "default", ProviderID: 1})
+	require.NoError(t, err)
+	require.Len(t, samples, 1)
+	assert.True(t, samples[0].Up)
+}
+
+func TestMonitor_ProbeAll_SourceError(t *testing.T) {
+	monitor := NewMonitor("default", func(ctx context.Context) ([]overlockv1beta1.Provider, error) {
+		return nil, errors.New("index has not completed its first sync")
+	}, NewMemoryStore(0), Options{})
+
+	err := monitor.ProbeAll(context.Background())
+	assert.ErrorContains(t, err, "failed to list providers")
+}
+
+// repeatingKeyClient returns one provider per page and always the same next key
+type repeatingKeyClient struct {
+	overlockv1beta1.QueryClient
+	calls int
+}
+
+func (c *repeatingKeyClient) ListProvider(ctx context.Context, req *overlockv1beta1.QueryListProviderRequest, opts ...grpc.CallOption) (*overlockv1beta1.QueryListProviderResponse, error) {
+	c.calls++
+	return &overlockv1beta1.QueryListProviderResponse{
+		Providers:  []overlockv1beta1.Provider{{Id: uint64(c.calls)}},
+		Pagination: &query.PageResponse{NextKey: []byte("same")},
+	}, nil
+}
+
+func TestChainSource_PageCap(t *testing.T) {
+	client := &repeatingKeyClient{}
+
+	providers, err := ChainSource(client, time.Second)(context.Background())
+
+	require.NoError(t, err)
+	assert.Len(t, providers, chainSourceMaxPages)
+	assert.Equal(t, chainSourceMaxPages, client.calls)
+}
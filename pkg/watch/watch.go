@@ -0,0 +1,344 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// URIScheme is the scheme of every resource URI served by this server
+const URIScheme = "overlock"
+
+// Target kinds
+const (
+	KindProvider     = "provider"
+	KindEnvironment  = "environment"
+	KindProviders    = "providers"
+	KindEnvironments = "environments"
+)
+
+// Errors returned by Manager
+var (
+	ErrInvalidURI           = errors.New("invalid resource URI")
+	ErrTooManySubscriptions = errors.New("too many subscriptions")
+)
+
+// Target identifies a watched record or collection on a network
+type Target struct {
+	Network string `json:"network"`
+	Kind    string `json:"kind"`
+	ID      uint64 `json:"id,omitempty"` // zero for collections
+}
+
+// URI returns the resource URI of the target, e.g. overlock://mainnet/providers/7
+func (t Target) URI() string {
+	switch t.Kind {
+	case KindProvider:
+		return fmt.Sprintf("%s://%s/providers/%d", URIScheme, t.Network, t.ID)
+	case KindEnvironment:
+		return fmt.Sprintf("%s://%s/environments/%d", URIScheme, t.Network, t.ID)
+	default:
+		return fmt.Sprintf("%s://%s/%s", URIScheme, t.Network, t.Kind)
+	}
+}
+
+// ParseURI parses a resource URI of the form overlock://<network>/<providers|environments>[/<id>]
+func ParseURI(uri string) (Target, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != URIScheme || u.Host == "" {
+		return Target{}, fmt.Errorf("%w '%s': expected %s://<network>/<providers|environments>[/<id>]", ErrInvalidURI, uri, URIScheme)
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	target := Target{Network: u.Host}
+	switch {
+	case len(segments) == 1 && (segments[0] == KindProviders || segments[0] == KindEnvironments):
+		target.Kind = segments[0]
+	case len(segments) == 2 && (segments[0] == KindProviders || segments[0] == KindEnvironments):
+		id, err := strconv.ParseUint(segments[1], 10, 64)
+		if err != nil || id == 0 {
+			return Target{}, fmt.Errorf("%w '%s': '%s' is not a valid ID", ErrInvalidURI, uri, segments[1])
+		}
+		target.Kind = strings.TrimSuffix(segments[0], "s")
+		target.ID = id
+	default:
+		return Target{}, fmt.Errorf("%w '%s': expected %s://<network>/<providers|environments>[/<id>]", ErrInvalidURI, uri, URIScheme)
+	}
+	return target, nil
+}
+
+// Fetcher reads the current state of a target, returning nil when the record does not exist
+type Fetcher func(ctx context.Context, target Target) ([]byte, error)
+
+// Notifier is called when a subscription's target changed since its previous poll
+type Notifier func(ctx context.Context, sub Subscription, state []byte)
+
+// Options configures a Manager
+type Options struct {
+	Tick          time.Duration // how often due subscriptions are checked
+	MinInterval   time.Duration // floor for every subscription's polling interval
+	MaxPerSession int           // maximum subscriptions per session, 0 means unlimited
+
+	// SessionAlive reports whether a session is still connected; subscriptions of
+	// closed sessions are dropped. Nil keeps subscriptions until they are removed.
+	SessionAlive func(sessionID string) bool
+
+	// Released is called with a URI once its last subscription is removed, either by
+	// Unsubscribe or because its session closed. Nil ignores released URIs.
+	Released func(uri string)
+}
+
+// Subscription is a session's watch on a target
+type Subscription struct {
+	ID            string        `json:"id"`
+	SessionID     string        `json:"-"`
+	URI           string        `json:"uri"`
+	Target        Target        `json:"target"`
+	Interval      time.Duration `json:"-"`
+	CreatedAt     time.Time     `json:"created_at"`
+	LastPolledAt  time.Time     `json:"last_polled_at,omitzero"`
+	LastChangedAt time.Time     `json:"last_changed_at,omitzero"`
+	Notifications int           `json:"notifications"`
+	LastError     string        `json:"last_error,omitempty"`
+
+	sequence uint64
+	state    []byte
+	primed   bool
+}
+
+// Manager polls the targets of active subscriptions and notifies subscribers of changes
+type Manager struct {
+	fetch  Fetcher
+	notify Notifier
+	opts   Options
+
+	mu            sync.Mutex
+	sequence      uint64
+	subscriptions map[string]*Subscription
+}
+
+// NewManager creates a subscription manager
+func NewManager(fetch Fetcher, notify Notifier, opts Options) *Manager {
+	if opts.Tick <= 0 {
+		opts.Tick = time.Second
+	}
+	if opts.MinInterval <= 0 {
+		opts.MinInterval = 10 * time.Second
+	}
+	return &Manager{
+		fetch:         fetch,
+		notify:        notify,
+		opts:          opts,
+		subscriptions: make(map[string]*Subscription),
+	}
+}
+
+// MinInterval returns the smallest polling interval a subscription can use
+func (m *Manager) MinInterval() time.Duration {
+	return m.opts.MinInterval
+}
+
+// Subscribe adds a subscription for the session. Intervals below the minimum are raised to it.
+func (m *Manager) Subscribe(sessionID string, target Target, interval time.Duration) (Subscription, error) {
+	if interval < m.opts.MinInterval {
+		interval = m.opts.MinInterval
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	uri := target.URI()
+	count := 0
+	for _, sub := range m.subscriptions {
+		if sub.SessionID != sessionID {
+			continue
+		}
+		if sub.URI == uri {
+			// Re-subscribing to the same target updates the interval
+			sub.Interval = interval
+			return sub.snapshot(), nil
+		}
+		count++
+	}
+	if m.opts.MaxPerSession > 0 && count >= m.opts.MaxPerSession {
+		return Subscription{}, fmt.Errorf("%w: a session can hold at most %d", ErrTooManySubscriptions, m.opts.MaxPerSession)
+	}
+
+	m.sequence++
+	sub := &Subscription{
+		ID:        "sub-" + strconv.FormatUint(m.sequence, 10),
+		SessionID: sessionID,
+		URI:       uri,
+		Target:    target,
+		Interval:  interval,
+		CreatedAt: time.Now().UTC(),
+		sequence:  m.sequence,
+	}
+	m.subscriptions[sub.ID] = sub
+	return sub.snapshot(), nil
+}
+
+// Unsubscribe removes a session's subscription by ID or URI and reports whether one was removed
+func (m *Manager) Unsubscribe(sessionID, idOrURI string) bool {
+	m.mu.Lock()
+	var removed []string
+	for id, sub := range m.subscriptions {
+		if sub.SessionID == sessionID && (sub.ID == idOrURI || sub.URI == idOrURI) {
+			delete(m.subscriptions, id)
+			removed = append(removed, sub.URI)
+			break
+		}
+	}
+	released := m.unwatchedLocked(removed)
+	m.mu.Unlock()
+
+	m.release(released)
+	return len(removed) > 0
+}
+
+// unwatchedLocked returns the URIs that no remaining subscription watches. m.mu must be held.
+func (m *Manager) unwatchedLocked(uris []string) []string {
+	var unwatched []string
+	for _, uri := range uris {
+		if !slices.Contains(unwatched, uri) && !m.watchedLocked(uri) {
+			unwatched = append(unwatched, uri)
+		}
+	}
+	return unwatched
+}
+
+// watchedLocked reports whether any subscription watches uri. m.mu must be held.
+func (m *Manager) watchedLocked(uri string) bool {
+	for _, sub := range m.subscriptions {
+		if sub.URI == uri {
+			return true
+		}
+	}
+	return false
+}
+
+// release reports URIs that lost their last subscription
+func (m *Manager) release(uris []string) {
+	if m.opts.Released == nil {
+		return
+	}
+	for _, uri := range uris {
+		m.opts.Released(uri)
+	}
+}
+
+// List returns the session's subscriptions ordered by creation
+func (m *Manager) List(sessionID string) []Subscription {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var subs []Subscription
+	for _, sub := range m.subscriptions {
+		if sub.SessionID == sessionID {
+			subs = append(subs, sub.snapshot())
+		}
+	}
+	sort.Slice(subs, func(a, b int) bool { return subs[a].sequence < subs[b].sequence })
+	return subs
+}
+
+// Run polls due subscriptions on every tick until ctx is cancelled
+func (m *Manager) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.opts.Tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.Poll(ctx, now)
+		}
+	}
+}
+
+// Poll fetches every target with a due subscription once and notifies the subscriptions
+// whose state changed. The first poll of a subscription only records its baseline.
+func (m *Manager) Poll(ctx context.Context, now time.Time) {
+	due, released := m.due(now)
+	m.release(released)
+	if len(due) == 0 {
+		return
+	}
+
+	// Subscriptions to the same target share a single fetch
+	byTarget := make(map[Target][]*Subscription)
+	for _, sub := range due {
+		byTarget[sub.Target] = append(byTarget[sub.Target], sub)
+	}
+
+	for target, subs := range byTarget {
+		state, err := m.fetch(ctx, target)
+
+		var changed []Subscription
+		m.mu.Lock()
+		for _, sub := range subs {
+			if _, ok := m.subscriptions[sub.ID]; !ok {
+				continue // unsubscribed while fetching
+			}
+			sub.LastPolledAt = now
+			if err != nil {
+				sub.LastError = err.Error()
+				continue
+			}
+			sub.LastError = ""
+			if sub.primed && !bytes.Equal(sub.state, state) {
+				sub.LastChangedAt = now
+				sub.Notifications++
+				changed = append(changed, sub.snapshot())
+			}
+			sub.state = state
+			sub.primed = true
+		}
+		m.mu.Unlock()
+
+		if err != nil {
+			log.Warn().Err(err).Str("uri", target.URI()).Msg("Failed to poll watched resource")
+			continue
+		}
+		for _, sub := range changed {
+			log.Info().Str("uri", sub.URI).Str("subscription_id", sub.ID).Msg("Watched resource changed")
+			m.notify(ctx, sub, state)
+		}
+	}
+}
+
+// due returns the subscriptions whose interval has elapsed, dropping those of closed sessions.
+// It also returns the URIs whose last subscription was dropped.
+func (m *Manager) due(now time.Time) ([]*Subscription, []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var due []*Subscription
+	var dropped []string
+	for id, sub := range m.subscriptions {
+		if m.opts.SessionAlive != nil && !m.opts.SessionAlive(sub.SessionID) {
+			delete(m.subscriptions, id)
+			dropped = append(dropped, sub.URI)
+			continue
+		}
+		if sub.LastPolledAt.IsZero() || now.Sub(sub.LastPolledAt) >= sub.Interval {
+			due = append(due, sub)
+		}
+	}
+	return due, m.unwatchedLocked(dropped)
+}
+
+// snapshot returns a copy of the subscription that is safe to hand out
+func (s *Subscription) snapshot() Subscription {
+	c := *s
+	c.state = nil
+	return c
+}
@@ -0,0 +1,191 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseURI(t *testing.T) {
+	tests := []struct {
+		uri    string
+		target Target
+	}{
+		{"overlock://mainnet/providers/7", Target{Network: "mainnet", Kind: KindProvider, ID: 7}},
+		{"overlock://testnet/environments/1001", Target{Network: "testnet", Kind: KindEnvironment, ID: 1001}},
+		{"overlock://mainnet/providers", Target{Network: "mainnet", Kind: KindProviders}},
+		{"overlock://mainnet/environments/", Target{Network: "mainnet", Kind: KindEnvironments}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			target, err := ParseURI(tt.uri)
+			require.NoError(t, err)
+			assert.Equal(t, tt.target, target)
+		})
+	}
+
+	for _, uri := range []string{
+		"https://mainnet/providers/7",
+		"overlock:///providers/7",
+		"overlock://mainnet/validators/7",
+		"overlock://mainnet/providers/abc",
+		"overlock://mainnet/providers/0",
+		"overlock://mainnet/providers/7/extra",
+	} {
+		t.Run(uri, func(t *testing.T) {
+			_, err := ParseURI(uri)
+			assert.ErrorIs(t, err, ErrInvalidURI)
+		})
+	}
+}
+
+func TestTarget_URI_RoundTrip(t *testing.T) {
+	for _, target := range []Target{
+		{Network: "mainnet", Kind: KindProvider, ID: 7},
+		{Network: "mainnet", Kind: KindEnvironment, ID: 9},
+		{Network: "mainnet", Kind: KindProviders},
+	} {
+		parsed, err := ParseURI(target.URI())
+		require.NoError(t, err)
+		assert.Equal(t, target, parsed)
+	}
+}
+
+// fakeSource serves mutable states and records fetches and notifications
+type fakeSource struct {
+	mu       sync.Mutex
+	states   map[Target][]byte
+	err      error
+	fetches  int
+	notified []Subscription
+}
+
+func (f *fakeSource) fetch(_ context.Context, target Target) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fetches++
+	return f.states[target], f.err
+}
+
+func (f *fakeSource) notify(_ context.Context, sub Subscription, _ []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.notified = append(f.notified, sub)
+}
+
+func TestManager_Poll(t *testing.T) {
+	provider := Target{Network: "mainnet", Kind: KindProvider, ID: 7}
+	source := &fakeSource{states: map[Target][]byte{provider: []byte(`{"ip":"10.0.0.1"}`)}}
+	manager := NewManager(source.fetch, source.notify, Options{MinInterval: time.Minute})
+
+	sub, err := manager.Subscribe("session-a", provider, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, sub.Interval, "interval is raised to the minimum")
+	_, err = manager.Subscribe("session-b", provider, time.Minute)
+	require.NoError(t, err)
+
+	start := time.Now()
+	manager.Poll(context.Background(), start)
+	assert.Equal(t, 1, source.fetches, "subscriptions to the same target share a fetch")
+	assert.Empty(t, source.notified, "the first poll records the baseline")
+
+	source.states[provider] = []byte(`{"ip":"10.0.0.2"}`)
+
+	// Not due yet
+	manager.Poll(context.Background(), start.Add(30*time.Second))
+	assert.Equal(t, 1, source.fetches)
+
+	manager.Poll(context.Background(), start.Add(time.Minute))
+	assert.Equal(t, 2, source.fetches)
+	require.Len(t, source.notified, 2)
+	assert.Equal(t, "overlock://mainnet/providers/7", source.notified[0].URI)
+
+	// Unchanged state does not notify again
+	manager.Poll(context.Background(), start.Add(2*time.Minute))
+	assert.Len(t, source.notified, 2)
+
+	subs := manager.List("session-a")
+	require.Len(t, subs, 1)
+	assert.Equal(t, 1, subs[0].Notifications)
+	assert.Equal(t, start.Add(time.Minute), subs[0].LastChangedAt)
+}
+
+func TestManager_PollError(t *testing.T) {
+	provider := Target{Network: "mainnet", Kind: KindProvider, ID: 7}
+	source := &fakeSource{err: errors.New("connection refused")}
+	manager := NewManager(source.fetch, source.notify, Options{})
+
+	_, err := manager.Subscribe("", provider, 0)
+	require.NoError(t, err)
+	manager.Poll(context.Background(), time.Now())
+
+	subs := manager.List("")
+	require.Len(t, subs, 1)
+	assert.Equal(t, "connection refused", subs[0].LastError)
+	assert.Empty(t, source.notified)
+}
+
+func TestManager_SubscribeLimitsAndUnsubscribe(t *testing.T) {
+	source := &fakeSource{}
+	var released []string
+	manager := NewManager(source.fetch, source.notify, Options{MaxPerSession: 2, Released: func(uri string) { released = append(released, uri) }})
+
+	first, err := manager.Subscribe("s", Target{Network: "mainnet", Kind: KindProviders}, 0)
+	require.NoError(t, err)
+	_, err = manager.Subscribe("s", Target{Network: "mainnet", Kind: KindProvider, ID: 1}, 0)
+	require.NoError(t, err)
+
+	// Re-subscribing to a watched target does not count against the limit
+	again, err := manager.Subscribe("s", Target{Network: "mainnet", Kind: KindProviders}, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, again.ID)
+	assert.Equal(t, time.Hour, again.Interval)
+
+	_, err = manager.Subscribe("s", Target{Network: "mainnet", Kind: KindProvider, ID: 2}, 0)
+	assert.ErrorIs(t, err, ErrTooManySubscriptions)
+
+	// Other sessions have their own limit and cannot remove this session's subscriptions
+	_, err = manager.Subscribe("other", Target{Network: "mainnet", Kind: KindProvider, ID: 2}, 0)
+	require.NoError(t, err)
+	assert.False(t, manager.Unsubscribe("other", first.ID))
+
+	assert.True(t, manager.Unsubscribe("s", first.ID))
+	assert.True(t, manager.Unsubscribe("s", "overlock://mainnet/providers/1"))
+	assert.Empty(t, manager.List("s"))
+	assert.Equal(t, []string{"overlock://mainnet/providers", "overlock://mainnet/providers/1"}, released)
+
+	// A URI is released only once no session watches it
+	_, err = manager.Subscribe("s", Target{Network: "mainnet", Kind: KindProvider, ID: 2}, 0)
+	require.NoError(t, err)
+	assert.True(t, manager.Unsubscribe("s", "overlock://mainnet/providers/2"))
+	assert.Len(t, released, 2)
+	assert.True(t, manager.Unsubscribe("other", "overlock://mainnet/providers/2"))
+	assert.Equal(t, "overlock://mainnet/providers/2", released[2])
+}
+
+func TestManager_DropsClosedSessions(t *testing.T) {
+	source := &fakeSource{}
+	alive := map[string]bool{"open": true}
+	var released []string
+	manager := NewManager(source.fetch, source.notify, Options{
+		SessionAlive: func(id string) bool { return alive[id] },
+		Released:     func(uri string) { released = append(released, uri) },
+	})
+
+	_, err := manager.Subscribe("open", Target{Network: "mainnet", Kind: KindProviders}, 0)
+	require.NoError(t, err)
+	_, err = manager.Subscribe("closed", Target{Network: "mainnet", Kind: KindEnvironments}, 0)
+	require.NoError(t, err)
+
+	manager.Poll(context.Background(), time.Now())
+
+	assert.Len(t, manager.List("open"), 1)
+	assert.Empty(t, manager.List("closed"))
+	assert.Equal(t, 1, source.fetches)
+	assert.Equal(t, []string{"overlock://mainnet/environments"}, released)
+}
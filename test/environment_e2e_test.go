@@ -94,9 +94,9 @@ var _ = Describe("Environment E2E Test", func() {
 				}
 
 				result, err := environmentHandler.Handle(ctx, session, params)
-				Expect(err).To(HaveOccurred())
-				Expect(result).To(BeNil())
-				Expect(err.Error()).To(ContainSubstring("validation failed"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(*mcp.TextContent).Text).To(ContainSubstring("validation failed"))
 			})
 		})
 
@@ -110,9 +110,9 @@ var _ = Describe("Environment E2E Test", func() {
 				}
 
 				result, err := environmentHandler.Handle(ctx, session, params)
-				Expect(err).To(HaveOccurred())
-				Expect(result).To(BeNil())
-				Expect(err.Error()).To(ContainSubstring("validation failed"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(*mcp.TextContent).Text).To(ContainSubstring("validation failed"))
 			})
 		})
 
@@ -97,9 +97,9 @@ var _ = Describe("Provider E2E Test", func() {
 				}
 
 				result, err := providersHandler.HandleShow(ctx, session, params)
-				Expect(err).To(HaveOccurred())
-				Expect(result).To(BeNil())
-				Expect(err.Error()).To(ContainSubstring("validation failed"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(*mcp.TextContent).Text).To(ContainSubstring("validation failed"))
 			})
 		})
 
@@ -113,9 +113,9 @@ var _ = Describe("Provider E2E Test", func() {
 				}
 
 				result, err := providersHandler.HandleShow(ctx, session, params)
-				Expect(err).To(HaveOccurred())
-				Expect(result).To(BeNil())
-				Expect(err.Error()).To(ContainSubstring("validation failed"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(*mcp.TextContent).Text).To(ContainSubstring("validation failed"))
 			})
 		})
 
@@ -129,9 +129,9 @@ var _ = Describe("Provider E2E Test", func() {
 				}
 
 				result, err := providersHandler.HandleShow(ctx, session, params)
-				Expect(err).To(HaveOccurred())
-				Expect(result).To(BeNil())
-				Expect(err.Error()).To(ContainSubstring("validation failed"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(*mcp.TextContent).Text).To(ContainSubstring("validation failed"))
 			})
 		})
 	})